@@ -0,0 +1,96 @@
+package nogo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MatchAbs does the same as MatchErr for an absolute path of the OS.
+// The root is the absolute directory the rules are relative to, e.g. the
+// directory which was used for os.DirFS. The path is converted to the form
+// expected by Match, relative to the root.
+//
+// If the root or a parent of the path is a symlink (e.g. /tmp on macOS),
+// both are resolved before they are compared. With WithIgnoreCase the root
+// is compared case-insensitively.
+//
+// An *InvalidPathError is returned if one of the paths is not absolute
+// or the path is not inside of the root.
+func (n *NoGo) MatchAbs(root, absPath string, isDir bool) (bool, error) {
+	rel, err := n.relPath(root, absPath)
+	if err != nil {
+		return false, err
+	}
+	return n.MatchErr(rel, isDir)
+}
+
+// relPath converts the absolute path to a slash separated path relative to the root.
+func (n *NoGo) relPath(root, absPath string) (string, error) {
+	if !filepath.IsAbs(root) {
+		return "", &InvalidPathError{Path: root, Reason: "the root is not absolute"}
+	}
+	if !filepath.IsAbs(absPath) {
+		return "", &InvalidPathError{Path: absPath, Reason: "not absolute"}
+	}
+
+	root = filepath.Clean(root)
+	absPath = filepath.Clean(absPath)
+	if rel, ok := n.trimRoot(root, absPath); ok {
+		return rel, nil
+	}
+
+	// Only the parent of the path is resolved,
+	// as a symlink inside of the root has to be matched itself.
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		resolvedRoot = root
+	}
+	resolvedPath := absPath
+	if absPath != filepath.Dir(absPath) {
+		resolvedPath = filepath.Join(resolveExisting(filepath.Dir(absPath)), filepath.Base(absPath))
+	}
+	if rel, ok := n.trimRoot(resolvedRoot, resolvedPath); ok {
+		return rel, nil
+	}
+
+	return "", &InvalidPathError{Path: absPath, Reason: "not inside of the root " + root}
+}
+
+// trimRoot returns the path relative to the root if it is inside of it.
+// Both have to be cleaned.
+func (n *NoGo) trimRoot(root, path string) (string, bool) {
+	hasPrefix := strings.HasPrefix
+	if n.ignoreCase {
+		hasPrefix = func(s, prefix string) bool {
+			return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+		}
+	}
+
+	if len(path) == len(root) && hasPrefix(path, root) {
+		return ".", true
+	}
+
+	// Only the root of a volume ends with a separator, e.g. "/" or `C:\`.
+	if !os.IsPathSeparator(root[len(root)-1]) {
+		root += string(filepath.Separator)
+	}
+	if !hasPrefix(path, root) {
+		return "", false
+	}
+	return filepath.ToSlash(path[len(root):]), true
+}
+
+// resolveExisting resolves the symlinks of the longest existing parent
+// of the path. The remaining elements are kept as they are.
+func resolveExisting(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(resolveExisting(parent), filepath.Base(path))
+}
@@ -0,0 +1,74 @@
+package nogo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_MatchAbs(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "repo")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "build"), 0o755))
+
+	link := filepath.Join(base, "link")
+	hasSymlink := os.Symlink(root, link) == nil
+
+	n := New(WithRules(MustCompileAll("", []byte("build/\n*.log\n/root.txt"))...))
+
+	tests := []struct {
+		name    string
+		root    string
+		path    string
+		isDir   bool
+		symlink bool
+		want    bool
+		wantErr bool
+	}{
+		{name: "ignored file", root: root, path: filepath.Join(root, "a", "debug.log"), want: true},
+		{name: "not ignored file", root: root, path: filepath.Join(root, "a", "main.go"), want: false},
+		{name: "ignored dir", root: root, path: filepath.Join(root, "build"), isDir: true, want: true},
+		{name: "inside of ignored dir", root: root, path: filepath.Join(root, "build", "out"), want: true},
+		{name: "anchored", root: root, path: filepath.Join(root, "root.txt"), want: true},
+		{name: "root itself", root: root, path: root, isDir: true, want: false},
+		{name: "root with trailing separator", root: root + string(filepath.Separator), path: filepath.Join(root, "debug.log"), want: true},
+		{name: "not cleaned", root: root, path: root + "/a/../debug.log", want: true},
+		{name: "outside of root", root: root, path: filepath.Join(base, "debug.log"), wantErr: true},
+		{name: "sibling with same prefix", root: root, path: root + "2/debug.log", wantErr: true},
+		{name: "relative path", root: root, path: "debug.log", wantErr: true},
+		{name: "relative root", root: "repo", path: filepath.Join(root, "debug.log"), wantErr: true},
+		{name: "symlinked root", root: link, path: filepath.Join(root, "build"), isDir: true, symlink: true, want: true},
+		{name: "symlinked path", root: root, path: filepath.Join(link, "build", "out"), symlink: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.symlink && !hasSymlink {
+				t.Skip("symlinks are not supported")
+			}
+
+			got, err := n.MatchAbs(tt.root, tt.path, tt.isDir)
+			if tt.wantErr {
+				var pathErr *InvalidPathError
+				assert.ErrorAs(t, err, &pathErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNoGo_MatchAbs_ignoreCase(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Repo")
+	n := New(WithIgnoreCase(), WithRules(MustCompileAll("", []byte("*.log"))...))
+
+	got, err := n.MatchAbs(root, filepath.Join(filepath.Dir(root), "REPO", "Debug.LOG"), false)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	_, err = New().MatchAbs(root, filepath.Join(filepath.Dir(root), "REPO", "Debug.LOG"), false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package nogo
+
+// NewAllowList creates a NoGo instance where the rules define what to include.
+// Everything which is not matched by any rule is excluded, so Match returns
+// true for all paths which are not included. This is the same as the "files"
+// list of a package.json compared to a .npmignore file.
+//
+//	n := nogo.NewAllowList()
+//	n.AddRules(nogo.MustCompileAll("", []byte("/src\n*.md\n!/src/testdata"))...)
+//	n.Match("src/main.go", false)    // false, it is included
+//	n.Match("src/testdata/a", false) // true, the negation excludes it
+//	n.Match("go.mod", false)         // true, no rule includes it
+//
+// A rule which includes a directory includes everything inside of it.
+// Directories are only excluded if no rule may match anything inside of them,
+// so walking an allow list still visits e.g. "src" for the rule "src/**/*.go".
+// Such directories are returned with a Result where Found is false.
+//
+// Options and rules work the same way as for New, but rules such as DotGitRule
+// which are meant to exclude paths include them instead.
+// MatchAll returns the same decisions as Match, but Result.Resolve of its
+// results returns true for included paths.
+func NewAllowList(opts ...Option) *NoGo {
+	n := &NoGo{allowList: true}
+	for _, opt := range opts {
+		opt.apply(n)
+	}
+	n.initLazyLoading()
+	n.changed()
+	return n
+}
+
+// allowedPaths returns the literal start of the paths of all non-negated rules.
+func allowedPaths(groups []group) []terminalRule {
+	var allowed []terminalRule
+	for _, g := range groups {
+		for _, rule := range g.rules {
+			if !rule.Negate {
+				allowed = append(allowed, analyzeTerminalRule(rule))
+			}
+		}
+	}
+	return allowed
+}
+
+// excluded resolves the result of an allow list.
+func (n *NoGo) excluded(path string, isDir bool, because Result) bool {
+	if because.Found || !isDir {
+		return !because.Resolve(isDir)
+	}
+
+	// The directory may contain included paths.
+	return !overlapsAny(terminalRule{path: path}, n.allowed)
+}
@@ -0,0 +1,102 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAllowList(t *testing.T) {
+	rules := MustCompileAll("", []byte("/src\n*.md\n!/src/testdata\ncmd/**/*.go\ndocs/"))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "src", isDir: true, want: false},
+		{path: "src/main.go", want: false},
+		{path: "src/pkg/a/b.txt", want: false},
+		{path: "src/testdata", isDir: true, want: true},
+		{path: "src/testdata/a.go", want: true},
+		{path: "README.md", want: false},
+		{path: "sub/README.md", want: false},
+		{path: "go.mod", want: true},
+		{path: "sub/go.mod", want: true},
+		// May contain included files.
+		{path: "sub", isDir: true, want: false},
+		{path: "cmd", isDir: true, want: false},
+		{path: "cmd/nogo", isDir: true, want: false},
+		{path: "cmd/nogo/main.go", want: false},
+		{path: "cmd/nogo/main.txt", want: true},
+		{path: "cmd.go", want: true},
+		{path: "docs", isDir: true, want: false},
+		{path: "docs", isDir: false, want: true},
+		{path: "docs/api", isDir: true, want: false},
+	}
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		for _, compile := range []bool{false, true} {
+			n := NewAllowList(WithBackend(backend), WithCache(100))
+			n.AddRules(rules...)
+			if compile {
+				n.Compile()
+			}
+
+			for _, tt := range tests {
+				assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir), "%v %v: %s", backend, compile, tt.path)
+
+				match, _ := n.MatchBecause(tt.path, tt.isDir)
+				assert.Equal(t, tt.want, match, "%v %v: %s", backend, compile, tt.path)
+
+				// Included parents have to be checked anyway.
+				match, _ = n.MatchWithoutParents(tt.path, tt.isDir)
+				assert.Equal(t, tt.want, match, "%v %v: %s", backend, compile, tt.path)
+			}
+		}
+	}
+}
+
+func TestNewAllowList_Empty(t *testing.T) {
+	n := NewAllowList()
+	assert.True(t, n.Match("a", false))
+	assert.True(t, n.Match("a", true))
+	assert.NotEqual(t, New().Hash(), n.Hash())
+}
+
+func TestNewAllowList_Walk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":              {},
+		"README.md":           {},
+		"src/main.go":         {},
+		"src/testdata/a.go":   {},
+		"cmd/nogo/main.go":    {},
+		"cmd/nogo/README.txt": {},
+		"other/file.go":       {},
+	}
+
+	n := NewAllowList()
+	n.AddRules(MustCompileAll("", []byte("/src\n*.md\n!/src/testdata\ncmd/**/*.go"))...)
+
+	var walked []string
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		walked = append(walked, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		".",
+		"README.md",
+		"cmd",
+		"cmd/nogo",
+		"cmd/nogo/main.go",
+		// May contain *.md files.
+		"other",
+		"src",
+		"src/main.go",
+	}, walked)
+}
@@ -0,0 +1,185 @@
+package nogo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxExpansions limits the amount of patterns a single pattern may expand to.
+const maxExpansions = 1024
+
+// ExpandBraces expands all brace expressions and extended globs of the pattern
+// into plain gitignore patterns, the same way as e.g. minimatch does it:
+//
+//	{src,lib}/*.js  -> src/*.js, lib/*.js
+//	file{1..3}      -> file1, file2, file3
+//	*.@(js|ts)      -> *.js, *.ts
+//	*.js?(x)        -> *.js, *.jsx
+//
+// Braces without a ',' or a range and escaped braces stay literal.
+// The escaping of braces, parentheses, ',', '|' and '@' is removed.
+// Braces and parentheses inside of character ranges (e.g. "[{]") are not expanded.
+//
+// The extended globs "*(...)", "+(...)" and "!(...)" can't be expressed
+// by gitignore patterns and return an error.
+func ExpandBraces(pattern string) ([]string, error) {
+	expanded, err := expandBraces(pattern, true)
+	if err != nil {
+		return nil, fmt.Errorf("expand %q: %w", pattern, err)
+	}
+	for i := range expanded {
+		expanded[i] = unescapeBraces(expanded[i])
+	}
+	return expanded, nil
+}
+
+// expandBraces expands the pattern. Extended globs are only expanded if extglob is true.
+func expandBraces(pattern string, extglob bool) ([]string, error) {
+	start, end, alternatives, err := findBraceGroup(pattern, extglob)
+	if err != nil || start < 0 {
+		return []string{pattern}, err
+	}
+
+	var result []string
+	for _, alternative := range alternatives {
+		rest, err := expandBraces(alternative+pattern[end+1:], extglob)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rest {
+			result = append(result, pattern[:start]+r)
+		}
+		if len(result) > maxExpansions {
+			return nil, fmt.Errorf("more than %d expansions", maxExpansions)
+		}
+	}
+	return result, nil
+}
+
+// findBraceGroup finds the first group which can be expanded.
+// start is -1 if there is none.
+func findBraceGroup(pattern string, extglob bool) (start, end int, alternatives []string, err error) {
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			// Skip the escaped char.
+			i++
+		case c == '[':
+			if close := strings.IndexByte(pattern[i+1:], ']'); close >= 0 {
+				i += close + 1
+			}
+		case c == '{':
+			end, parts := splitGroup(pattern, i+1, '}', ',')
+			if end < 0 {
+				continue
+			}
+			if len(parts) == 1 {
+				// Braces without ',' are only expanded if they contain a range.
+				if parts, ok := expandRange(parts[0]); ok {
+					return i, end, parts, nil
+				}
+				continue
+			}
+			return i, end, parts, nil
+		case extglob && strings.IndexByte("@?*+!", c) >= 0 && i+1 < len(pattern) && pattern[i+1] == '(':
+			end, parts := splitGroup(pattern, i+2, ')', '|')
+			if end < 0 {
+				continue
+			}
+			switch c {
+			case '@':
+				return i, end, parts, nil
+			case '?':
+				return i, end, append([]string{""}, parts...), nil
+			default:
+				return -1, -1, nil, fmt.Errorf("unsupported extended glob %q", pattern[i:end+1])
+			}
+		}
+	}
+	return -1, -1, nil, nil
+}
+
+// splitGroup finds the closing char of the group starting at the given index
+// and splits its content at the separators which are not nested in another group.
+// end is -1 if the group is not closed.
+func splitGroup(pattern string, start int, closing, separator byte) (end int, parts []string) {
+	depth := 0
+	last := start
+	for i := start; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case c == '{' || c == '(':
+			depth++
+		case depth > 0 && (c == '}' || c == ')'):
+			depth--
+		case depth == 0 && c == closing:
+			return i, append(parts, pattern[last:i])
+		case depth == 0 && c == separator:
+			parts = append(parts, pattern[last:i])
+			last = i + 1
+		}
+	}
+	return -1, nil
+}
+
+// expandRange expands numeric ranges such as "1..3" or "10..-2"
+// and ranges of single chars such as "a..e".
+func expandRange(content string) ([]string, bool) {
+	sep := strings.Index(content, "..")
+	if sep < 0 {
+		return nil, false
+	}
+	from, to := content[:sep], content[sep+2:]
+
+	if start, err := strconv.Atoi(from); err == nil {
+		if end, err := strconv.Atoi(to); err == nil {
+			return expandSequence(start, end, strconv.Itoa)
+		}
+	}
+
+	if len(from) == 1 && len(to) == 1 {
+		return expandSequence(int(from[0]), int(to[0]), func(c int) string {
+			return string([]byte{byte(c)})
+		})
+	}
+	return nil, false
+}
+
+func expandSequence(start, end int, format func(int) string) ([]string, bool) {
+	step := 1
+	if end < start {
+		step = -1
+	}
+
+	var result []string
+	for i := start; ; i += step {
+		result = append(result, format(i))
+		if i == end || len(result) > maxExpansions {
+			return result, true
+		}
+	}
+}
+
+// unescapeBraces removes the escaping of chars which are only special for
+// brace expansion, as gitignore patterns match them literally anyway.
+func unescapeBraces(pattern string) string {
+	if !strings.Contains(pattern, `\`) {
+		return pattern
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			if strings.IndexByte("{},|()@", pattern[i+1]) < 0 {
+				// Keep all other escapes.
+				b.WriteByte('\\')
+			}
+			i++
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package nogo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{pattern: "*.js", want: []string{"*.js"}},
+		{pattern: "{src,lib}/**/*.js", want: []string{"src/**/*.js", "lib/**/*.js"}},
+		{pattern: "a{b,c}d{e,f}", want: []string{"abde", "abdf", "acde", "acdf"}},
+		{pattern: "a{b,{c,d}}", want: []string{"ab", "ac", "ad"}},
+		{pattern: "a{,.min}.js", want: []string{"a.js", "a.min.js"}},
+		{pattern: "file{1..3}", want: []string{"file1", "file2", "file3"}},
+		{pattern: "file{2..-1}", want: []string{"file2", "file1", "file0", "file-1"}},
+		{pattern: "{a..c}.txt", want: []string{"a.txt", "b.txt", "c.txt"}},
+		{pattern: "*.@(js|ts)", want: []string{"*.js", "*.ts"}},
+		{pattern: "*.js?(x)", want: []string{"*.js", "*.jsx"}},
+		{pattern: "@(a|{b,c})", want: []string{"a", "b", "c"}},
+		{pattern: "{a,@(b|c)}", want: []string{"a", "b", "c"}},
+
+		// Literals.
+		{pattern: "{a}", want: []string{"{a}"}},
+		{pattern: "{a", want: []string{"{a"}},
+		{pattern: "a}", want: []string{"a}"}},
+		{pattern: "{a..}", want: []string{"{a..}"}},
+		{pattern: `\{a,b}`, want: []string{"{a,b}"}},
+		{pattern: `{a\,b,c}`, want: []string{"a,b", "c"}},
+		{pattern: "[{]a,b}", want: []string{"[{]a,b}"}},
+		{pattern: "?x", want: []string{"?x"}},
+		{pattern: `\*.js`, want: []string{`\*.js`}},
+
+		{pattern: "*(a|b)", wantErr: assert.Error},
+		{pattern: "a+(b)", wantErr: assert.Error},
+		{pattern: "a!(b)", wantErr: assert.Error},
+		{pattern: "{1..2000}", wantErr: assert.Error},
+		{pattern: strings.Repeat("{a,b}", 11), wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := ExpandBraces(tt.pattern)
+			if tt.wantErr != nil {
+				tt.wantErr(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package nogo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBundleEntrySize is the maximum size of a single file inside of a bundle.
+// Larger files are rejected, so that a small compressed archive can't be used
+// to exhaust the memory.
+const maxBundleEntrySize = 10 << 20
+
+// bundleEntry is a single ignore file of a rules bundle.
+type bundleEntry struct {
+	name string
+	data []byte
+}
+
+// AddBundle loads a rules bundle.
+// A bundle is a zip, tar or gzip compressed tar archive which only contains
+// ignore files. Each file is loaded the same way as with AddFile, so the folder
+// of a file inside the archive is used as prefix for its rules.
+// The name of the files is not checked.
+//
+// Example content of a bundle:
+//
+//	.gitignore
+//	vendor/.gitignore
+//	services/api/.gitignore
+//
+// The files are added with parent folders first, independently of the order
+// inside the archive. If any file is invalid, no rules are added at all.
+func (n *NoGo) AddBundle(r io.ReaderAt, size int64) error {
+	entries, err := readBundle(r, size)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.Count(entries[i].name, "/") < strings.Count(entries[j].name, "/")
+	})
+
+	groups := make([]group, 0, len(entries))
+	loads := make([]sourceLoad, 0, len(entries))
+	for _, entry := range entries {
+		start := time.Now()
+		folder := path.Dir(entry.name)
+		if folder == "." {
+			folder = ""
+		}
+
+		rules, err := n.dialect.CompileAll(folder, entry.data)
+		if err != nil {
+			return fmt.Errorf("bundle entry %q: %w", entry.name, err)
+		}
+
+		groups = append(groups, group{
+			prefix: folder,
+			rules:  rules,
+			source: entry.name,
+		})
+		loads = append(loads, sourceLoad{at: start, duration: time.Since(start)})
+	}
+
+	// Only record the sources once all entries are valid.
+	for i, g := range groups {
+		n.addGroup(g)
+		if n.loads == nil {
+			n.loads = make(map[string]sourceLoad)
+		}
+		n.loads[g.source] = loads[i]
+	}
+	n.changed()
+	return nil
+}
+
+// readBundle detects the archive format and reads all regular files of it.
+func readBundle(r io.ReaderAt, size int64) ([]bundleEntry, error) {
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK")):
+		return readZipBundle(r, size)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return readTarBundle(gz)
+	default:
+		return readTarBundle(io.NewSectionReader(r, 0, size))
+	}
+}
+
+func readZipBundle(r io.ReaderAt, size int64) ([]bundleEntry, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []bundleEntry
+	for _, file := range archive.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		name, err := bundleEntryName(file.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := readBundleEntry(content, name)
+		_ = content.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, bundleEntry{name: name, data: data})
+	}
+	return entries, nil
+}
+
+func readTarBundle(r io.Reader) ([]bundleEntry, error) {
+	archive := tar.NewReader(r)
+
+	var entries []bundleEntry
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Old archives use the deprecated TypeRegA for regular files.
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		name, err := bundleEntryName(header.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := readBundleEntry(archive, name)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, bundleEntry{name: name, data: data})
+	}
+}
+
+// readBundleEntry reads the content of a file inside of a bundle.
+// It fails if the file is larger than maxBundleEntrySize.
+func readBundleEntry(r io.Reader, name string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBundleEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBundleEntrySize {
+		return nil, fmt.Errorf("bundle entry %q is larger than %d bytes", name, maxBundleEntrySize)
+	}
+	return data, nil
+}
+
+// bundleEntryName cleans the name of an archive entry.
+// Names which would point outside of the root folder are rejected.
+func bundleEntryName(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+	if !fs.ValidPath(cleaned) || cleaned == "." {
+		return "", fmt.Errorf("invalid bundle entry name %q", name)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,122 @@
+package nogo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bundleFile struct {
+	name    string
+	content string
+}
+
+func zipBundle(t *testing.T, files []bundleFile) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, f := range files {
+		file, err := w.Create(f.name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func tarBundle(t *testing.T, files []bundleFile) []byte {
+	var b bytes.Buffer
+	w := tar.NewWriter(&b)
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: "sub/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, f := range files {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(f.content))}))
+		_, err := w.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func gzipBundle(t *testing.T, files []bundleFile) []byte {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write(tarBundle(t, files))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func TestNoGo_AddBundle(t *testing.T) {
+	// The child is first in the archive, but has to be added after the parent
+	// to be able to re-include the file.
+	files := []bundleFile{
+		{name: "sub/.gitignore", content: "!important.log\n"},
+		{name: "./.gitignore", content: "*.log\n"},
+	}
+
+	for name, create := range map[string]func(*testing.T, []bundleFile) []byte{
+		"zip":    zipBundle,
+		"tar":    tarBundle,
+		"tar.gz": gzipBundle,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := create(t, files)
+
+			n := New()
+			require.NoError(t, n.AddBundle(bytes.NewReader(data), int64(len(data))))
+
+			assert.True(t, n.Match("debug.log", false))
+			assert.True(t, n.Match("sub/debug.log", false))
+			assert.False(t, n.Match("sub/important.log", false))
+			assert.True(t, n.Match("important.log", false))
+			assert.False(t, n.Match("main.go", false))
+		})
+	}
+}
+
+func TestNoGo_AddBundle_TypeRegA(t *testing.T) {
+	var b bytes.Buffer
+	w := tar.NewWriter(&b)
+	content := "*.log\n"
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: ".gitignore", Typeflag: tar.TypeRegA, Mode: 0644, Size: int64(len(content))}))
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	n := New()
+	require.NoError(t, n.AddBundle(bytes.NewReader(b.Bytes()), int64(b.Len())))
+	assert.True(t, n.Match("debug.log", false))
+}
+
+func TestNoGo_AddBundle_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "not an archive", data: []byte("*.log\n")},
+		{name: "entry outside of root", data: zipBundle(t, []bundleFile{{name: "../.gitignore", content: "*.log"}})},
+		{name: "absolute entry", data: tarBundle(t, []bundleFile{{name: "/.gitignore", content: "*.log"}})},
+		{name: "invalid pattern", data: zipBundle(t, []bundleFile{
+			{name: ".gitignore", content: "*.log"},
+			{name: "sub/.gitignore", content: "[a"},
+		})},
+		{name: "entry too large", data: gzipBundle(t, []bundleFile{
+			{name: ".gitignore", content: "*.log"},
+			{name: "sub/.gitignore", content: strings.Repeat("#", maxBundleEntrySize+1)},
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New()
+			assert.Error(t, n.AddBundle(bytes.NewReader(tt.data), int64(len(tt.data))))
+			assert.Empty(t, n.groups)
+			assert.Empty(t, n.loads)
+		})
+	}
+}
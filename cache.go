@@ -0,0 +1,82 @@
+package nogo
+
+import (
+	"container/list"
+	"sync"
+)
+
+type cacheKey struct {
+	path      string
+	isDir     bool
+	noParents bool
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	match   bool
+	because Result
+}
+
+// matchCache is a simple LRU cache for match results.
+// It is safe for concurrent use.
+type matchCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[cacheKey]*list.Element
+}
+
+func newMatchCache(size int) *matchCache {
+	return &matchCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[cacheKey]*list.Element, size),
+	}
+}
+
+func (c *matchCache) get(key cacheKey) (entry cacheEntry, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(cacheEntry), true
+}
+
+func (c *matchCache) put(entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+	}
+}
+
+func (c *matchCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// clear removes all cached results.
+func (c *matchCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[cacheKey]*list.Element, c.size)
+}
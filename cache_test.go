@@ -0,0 +1,75 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCache(t *testing.T) {
+	c := newMatchCache(2)
+
+	a := cacheEntry{key: cacheKey{path: "a"}, match: true}
+	b := cacheEntry{key: cacheKey{path: "b", isDir: true}}
+	d := cacheEntry{key: cacheKey{path: "d"}}
+
+	c.put(a)
+	c.put(b)
+
+	got, ok := c.get(a.key)
+	assert.True(t, ok)
+	assert.Equal(t, a, got)
+
+	// b is now the least recently used one and gets removed.
+	c.put(d)
+	assert.Equal(t, 2, c.len())
+
+	_, ok = c.get(b.key)
+	assert.False(t, ok)
+	_, ok = c.get(a.key)
+	assert.True(t, ok)
+	_, ok = c.get(d.key)
+	assert.True(t, ok)
+
+	// The same path as file is a different entry.
+	_, ok = c.get(cacheKey{path: "b"})
+	assert.False(t, ok)
+
+	c.clear()
+	assert.Equal(t, 0, c.len())
+}
+
+func TestNoGo_WithCache(t *testing.T) {
+	t.Run("same results as without cache", func(t *testing.T) {
+		n := New(WithCache(10))
+		n.groups = TestFSGroups
+
+		for path, tt := range TestFSData {
+			// Match twice to get the second one from the cache.
+			for i := 0; i < 2; i++ {
+				gotMatch, gotBecause := n.MatchBecause(path, tt.isDir)
+				wantMatch, wantBecause := (&NoGo{groups: TestFSGroups}).MatchBecause(path, tt.isDir)
+				assert.Equal(t, wantMatch, gotMatch, path)
+				assert.Equal(t, wantBecause, gotBecause, path)
+			}
+		}
+
+		assert.Equal(t, 10, n.cache.len())
+	})
+
+	t.Run("invalidate on new rules", func(t *testing.T) {
+		n := New(WithCache(10))
+		assert.False(t, n.Match("aFile", false))
+		assert.Equal(t, 1, n.cache.len())
+
+		n.AddRules(MustCompileAll("", []byte("aFile"))...)
+		assert.Equal(t, 0, n.cache.len())
+		assert.True(t, n.Match("aFile", false))
+	})
+
+	t.Run("disabled cache", func(t *testing.T) {
+		n := New(WithCache(0))
+		assert.Nil(t, n.cache)
+		assert.False(t, n.Match("aFile", false))
+	})
+}
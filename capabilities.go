@@ -0,0 +1,237 @@
+package nogo
+
+// BehaviorVersion is the semantic version of the matching behavior of nogo.
+// It changes independently of the module version:
+//   - The major version is increased for incompatible changes of the matching.
+//   - The minor version is increased if new syntax or extensions are added.
+//   - The patch version is increased for bug fixes.
+const BehaviorVersion = "1.3.3"
+
+// Names of the syntax profiles reported by Capabilities.
+const (
+	SyntaxGitignore    = "gitignore"
+	SyntaxBraces       = "braces"
+	SyntaxHelmignore   = "helmignore"
+	SyntaxGcloudignore = "gcloudignore"
+	SyntaxHgignore     = "hgignore"
+)
+
+// Names of the extensions reported by Capabilities.
+// Extensions are features which go beyond plain matching of gitignore files.
+const (
+	ExtensionCache                = "cache"
+	ExtensionCompile              = "compile"
+	ExtensionBundle               = "bundle"
+	ExtensionMarshalBinary        = "marshal-binary"
+	ExtensionWindowsReservedNames = "windows-reserved-names"
+	ExtensionWindowsStreams       = "windows-streams"
+	ExtensionWalkPostDir          = "walk-post-dir"
+	ExtensionWalkConcurrent       = "walk-concurrent"
+	ExtensionWalkLazyLoading      = "walk-lazy-loading"
+	ExtensionWalkLoadEvents       = "walk-load-events"
+	ExtensionWalkFollowSymlinks   = "walk-follow-symlinks"
+	ExtensionWatch                = "watch"
+	ExtensionTerminalRules        = "terminal-rules"
+	ExtensionAnchoring            = "anchoring"
+	ExtensionAllowList            = "allow-list"
+	ExtensionValidatePaths        = "validate-paths"
+	ExtensionDirHint              = "dir-hint"
+	ExtensionIgnoreCase           = "ignore-case"
+	ExtensionWindowsPaths         = "windows-paths"
+	ExtensionReader               = "reader"
+	ExtensionGroups               = "groups"
+	ExtensionWalkStrict           = "walk-strict"
+	ExtensionLazyLoading          = "lazy-loading"
+	ExtensionMatchFS              = "match-fs"
+	ExtensionWalkSkipVCSDirs      = "walk-skip-vcs-dirs"
+	ExtensionParseReport          = "parse-report"
+	ExtensionStats                = "stats"
+	ExtensionWriter               = "writer"
+	ExtensionOptimize             = "optimize"
+	ExtensionProgress             = "progress"
+	ExtensionLogger               = "logger"
+	ExtensionProfiles             = "profiles"
+	ExtensionForDir               = "for-dir"
+	ExtensionRebase               = "rebase"
+	ExtensionSub                  = "sub"
+	ExtensionGlob                 = "glob"
+	ExtensionPathspec             = "pathspec"
+	ExtensionMerge                = "merge"
+	ExtensionDefaultRules         = "default-rules"
+	ExtensionTemplates            = "templates"
+	ExtensionCopyFS               = "copy-fs"
+	ExtensionDu                   = "du"
+	ExtensionManifest             = "manifest"
+	ExtensionTranslate            = "translate"
+	ExtensionSuggest              = "suggest"
+	ExtensionUpdateFile           = "update-file"
+	ExtensionOverlay              = "overlay"
+	ExtensionMatchEntry           = "match-entry"
+	ExtensionMatchKind            = "match-kind"
+	ExtensionGitDir               = "git-dir"
+	ExtensionSubmodules           = "submodules"
+	ExtensionSparseCone           = "sparse-cone"
+	ExtensionExportIgnore         = "export-ignore"
+	ExtensionSources              = "sources"
+	ExtensionStrictIO             = "strict-io"
+	ExtensionWalkMaxDepth         = "walk-max-depth"
+	ExtensionWalkPruneDirs        = "walk-prune-dirs"
+	ExtensionMatchDir             = "match-dir"
+	ExtensionWalkPrecise          = "walk-precise"
+	ExtensionMatchPattern         = "match-pattern"
+	ExtensionConfig               = "config"
+	ExtensionTags                 = "tags"
+	ExtensionWalkErrorPolicy      = "walk-error-policy"
+	ExtensionMatchAbs             = "match-abs"
+	ExtensionNewRule              = "new-rule"
+	ExtensionHasNegations         = "has-negations"
+	ExtensionPacked               = "packed"
+	ExtensionServer               = "server"
+	ExtensionWasm                 = "wasm"
+	ExtensionEventFilter          = "event-filter"
+	ExtensionWatchableDirs        = "watchable-dirs"
+	ExtensionWalkContext          = "walk-context"
+)
+
+// CapabilitySet describes what this version of nogo supports.
+type CapabilitySet struct {
+	// BehaviorVersion is the semantic version of the matching behavior.
+	BehaviorVersion string
+
+	// GitVersion is the version of the gitignore specification which is implemented.
+	GitVersion string
+
+	// MarshalVersion is the version of the format created by NoGo.MarshalBinary.
+	MarshalVersion int
+
+	// Syntaxes contains the supported syntax profiles.
+	Syntaxes []string
+
+	// Backends contains the supported backends for WithBackend.
+	Backends []Backend
+
+	// Extensions contains all supported extensions.
+	Extensions []string
+}
+
+// Capabilities reports the supported syntax, extensions and versions of nogo.
+// Tools which cache rules or communicate with other processes using nogo can
+// use it to detect incompatibilities.
+func Capabilities() CapabilitySet {
+	return CapabilitySet{
+		BehaviorVersion: BehaviorVersion,
+		GitVersion:      "2.34.0",
+		MarshalVersion:  marshalVersion,
+		Syntaxes:        []string{SyntaxGitignore, SyntaxBraces, SyntaxHelmignore, SyntaxGcloudignore, SyntaxHgignore},
+		Backends:        []Backend{RegexpBackend, GlobBackend},
+		Extensions: []string{
+			ExtensionCache,
+			ExtensionCompile,
+			ExtensionBundle,
+			ExtensionMarshalBinary,
+			ExtensionWindowsReservedNames,
+			ExtensionWindowsStreams,
+			ExtensionWalkPostDir,
+			ExtensionWalkConcurrent,
+			ExtensionWalkLazyLoading,
+			ExtensionWalkLoadEvents,
+			ExtensionWalkFollowSymlinks,
+			ExtensionWatch,
+			ExtensionTerminalRules,
+			ExtensionAnchoring,
+			ExtensionAllowList,
+			ExtensionValidatePaths,
+			ExtensionDirHint,
+			ExtensionIgnoreCase,
+			ExtensionWindowsPaths,
+			ExtensionReader,
+			ExtensionGroups,
+			ExtensionWalkStrict,
+			ExtensionLazyLoading,
+			ExtensionMatchFS,
+			ExtensionWalkSkipVCSDirs,
+			ExtensionParseReport,
+			ExtensionStats,
+			ExtensionWriter,
+			ExtensionOptimize,
+			ExtensionProgress,
+			ExtensionLogger,
+			ExtensionProfiles,
+			ExtensionForDir,
+			ExtensionRebase,
+			ExtensionSub,
+			ExtensionGlob,
+			ExtensionPathspec,
+			ExtensionMerge,
+			ExtensionDefaultRules,
+			ExtensionTemplates,
+			ExtensionCopyFS,
+			ExtensionDu,
+			ExtensionManifest,
+			ExtensionTranslate,
+			ExtensionSuggest,
+			ExtensionUpdateFile,
+			ExtensionOverlay,
+			ExtensionMatchEntry,
+			ExtensionMatchKind,
+			ExtensionGitDir,
+			ExtensionSubmodules,
+			ExtensionSparseCone,
+			ExtensionExportIgnore,
+			ExtensionSources,
+			ExtensionStrictIO,
+			ExtensionWalkMaxDepth,
+			ExtensionWalkPruneDirs,
+			ExtensionMatchDir,
+			ExtensionWalkPrecise,
+			ExtensionMatchPattern,
+			ExtensionConfig,
+			ExtensionTags,
+			ExtensionWalkErrorPolicy,
+			ExtensionMatchAbs,
+			ExtensionNewRule,
+			ExtensionHasNegations,
+			ExtensionPacked,
+			ExtensionServer,
+			ExtensionWasm,
+			ExtensionEventFilter,
+			ExtensionWatchableDirs,
+			ExtensionWalkContext,
+		},
+	}
+}
+
+// HasSyntax returns true if the syntax profile is supported.
+func (c CapabilitySet) HasSyntax(syntax string) bool {
+	return contains(c.Syntaxes, syntax)
+}
+
+// HasExtension returns true if the extension is supported.
+func (c CapabilitySet) HasExtension(extension string) bool {
+	return contains(c.Extensions, extension)
+}
+
+// CompatibleWith returns true if rules and results created by a nogo with the
+// other capabilities match the same way as with this nogo.
+// This is the case if the major version of the matching behavior is the same.
+func (c CapabilitySet) CompatibleWith(other CapabilitySet) bool {
+	return majorVersion(c.BehaviorVersion) == majorVersion(other.BehaviorVersion)
+}
+
+func majorVersion(version string) string {
+	for i, c := range version {
+		if c == '.' {
+			return version[:i]
+		}
+	}
+	return version
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
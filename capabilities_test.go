@@ -0,0 +1,43 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities()
+	assert.Equal(t, BehaviorVersion, c.BehaviorVersion)
+	assert.Equal(t, marshalVersion, c.MarshalVersion)
+	assert.True(t, c.HasSyntax(SyntaxGitignore))
+	assert.False(t, c.HasSyntax("unknown"))
+	assert.True(t, c.HasExtension(ExtensionCompile))
+	assert.False(t, c.HasExtension("unknown"))
+	assert.Contains(t, c.Backends, GlobBackend)
+
+	// Each call returns a new value, so that callers can't modify the capabilities.
+	c.Extensions[0] = "modified"
+	assert.NotEqual(t, "modified", Capabilities().Extensions[0])
+}
+
+func TestCapabilitySet_CompatibleWith(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "same", a: "1.2.3", b: "1.2.3", want: true},
+		{name: "other minor", a: "1.2.3", b: "1.5.0", want: true},
+		{name: "other major", a: "1.2.3", b: "2.0.0", want: false},
+		{name: "prefix of other major", a: "1.0.0", b: "10.0.0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := CapabilitySet{BehaviorVersion: tt.a}
+			b := CapabilitySet{BehaviorVersion: tt.b}
+			assert.Equal(t, tt.want, a.CompatibleWith(b))
+			assert.Equal(t, tt.want, b.CompatibleWith(a))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strconv"
+)
+
+// runGen compiles the ignore files of the dir into Go source, which loads
+// them using nogo.MustLoadPacked. It is meant to be used with go:generate:
+//
+//	//go:generate nogo gen -pkg defaults -o excludes.go ./excludes
+func runGen(e *env, args []string) error {
+	set := e.flagSet("gen")
+	var w walkFlags
+	w.register(set)
+	output := set.String("o", "", "the file to write, defaults to stdout")
+	pkg := set.String("pkg", os.Getenv("GOPACKAGE"), "the package of the generated file, defaults to $GOPACKAGE")
+	name := set.String("var", "Matcher", "the name of the generated variable")
+	if err := parse(set, args); err != nil {
+		return err
+	}
+
+	if *pkg == "" {
+		return fmt.Errorf("gen: -pkg is required outside of go:generate")
+	}
+	if !token.IsIdentifier(*name) {
+		return fmt.Errorf("gen: invalid variable name %q", *name)
+	}
+
+	dir, err := dir(set)
+	if err != nil {
+		return err
+	}
+	n, err := w.load(e.dirFS(dir))
+	if err != nil {
+		return err
+	}
+	data, err := n.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	src, err := genSource(*pkg, *name, data)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = e.stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// genSource returns the formatted Go source of the generated file.
+func genSource(pkg, name string, data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, `// Code generated by "nogo gen"; DO NOT EDIT.`)
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintln(&b, `import "github.com/aligator/nogo"`)
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "// %s contains the packed rules of the ignore files.\n", name)
+	fmt.Fprintln(&b, "// Run go generate again after updating nogo, as the format may change.")
+	fmt.Fprintf(&b, "var %s = nogo.MustLoadPacked([]byte(\"\" +\n", name)
+
+	// Split the data into lines to keep the file readable in diffs.
+	const lineLength = 64
+	for start := 0; start < len(data); start += lineLength {
+		end := start + lineLength
+		if end > len(data) {
+			end = len(data)
+		}
+		fmt.Fprintf(&b, "\t%s +\n", strconv.Quote(string(data[start:end])))
+	}
+	fmt.Fprintln(&b, "\t\"\"))")
+
+	return format.Source(b.Bytes())
+}
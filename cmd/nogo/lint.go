@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/aligator/nogo/lint"
+)
+
+func runLint(e *env, args []string) error {
+	set := e.flagSet("lint")
+	ignoreFile := set.String("ignore-file", ".gitignore", "the name of the ignore files to lint")
+	if err := parse(set, args); err != nil {
+		return err
+	}
+
+	dir, err := dir(set)
+	if err != nil {
+		return err
+	}
+
+	// The ignore files can't be loaded to skip ignored folders, as they
+	// may contain invalid patterns. Only .git folders are skipped.
+	fsys := e.dirFS(dir)
+	var problems int
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+		if d.IsDir() || d.Name() != *ignoreFile {
+			return nil
+		}
+
+		issues, err := lint.LintFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(e.stdout, "%s:%d: %s: %s (%s)\n", p, issue.Line, issue.Severity, issue.Message, issue.Code)
+			if issue.Severity >= lint.SeverityWarning {
+				problems++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problems", problems)
+	}
+	return nil
+}
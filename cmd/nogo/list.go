@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// node is one file or folder of the printed tree.
+type node struct {
+	Name     string  `json:"name"`
+	Path     string  `json:"path"`
+	IsDir    bool    `json:"isDir"`
+	Children []*node `json:"children,omitempty"`
+}
+
+// buildTree walks the fsys and collects all not ignored files and folders.
+func buildTree(w *walkFlags, fsys fs.FS) (*node, error) {
+	root := &node{Name: ".", Path: ".", IsDir: true}
+	dirs := map[string]*node{".": root}
+
+	err := w.walk(fsys, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == "." {
+			return nil
+		}
+
+		n := &node{Name: d.Name(), Path: p, IsDir: d.IsDir()}
+		parent := dirs[path.Dir(p)]
+		parent.Children = append(parent.Children, n)
+
+		if d.IsDir() {
+			dirs[p] = n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+func runList(e *env, args []string) error {
+	var w walkFlags
+	set := e.flagSet("list")
+	w.register(set)
+	asJSON := set.Bool("json", false, "print the list as json array")
+	if err := parse(set, args); err != nil {
+		return err
+	}
+
+	dir, err := dir(set)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	err = w.walk(e.dirFS(dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			p += "/"
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if paths == nil {
+			paths = []string{}
+		}
+		return printJSON(e.stdout, paths)
+	}
+
+	for _, p := range paths {
+		fmt.Fprintln(e.stdout, p)
+	}
+	return nil
+}
+
+func runTree(e *env, args []string) error {
+	var w walkFlags
+	set := e.flagSet("tree")
+	w.register(set)
+	asJSON := set.Bool("json", false, "print the tree as json object")
+	if err := parse(set, args); err != nil {
+		return err
+	}
+
+	dir, err := dir(set)
+	if err != nil {
+		return err
+	}
+
+	root, err := buildTree(&w, e.dirFS(dir))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(e.stdout, root)
+	}
+
+	fmt.Fprintln(e.stdout, root.Name)
+	printTree(e.stdout, root, "")
+	return nil
+}
+
+func printTree(out io.Writer, n *node, indent string) {
+	for i, child := range n.Children {
+		branch, next := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		name := child.Name
+		if child.IsDir {
+			name += "/"
+		}
+		fmt.Fprintln(out, indent+branch+name)
+		printTree(out, child, indent+next)
+	}
+}
+
+func printJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
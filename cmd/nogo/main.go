@@ -0,0 +1,215 @@
+// Nogo is a small command line tool around the nogo package.
+//
+// Usage:
+//
+//	nogo <command> [flags] [dir]
+//
+// The commands are:
+//
+//	list  prints all files and folders which are not ignored
+//	tree  prints the not ignored files and folders as a tree
+//	lint  reports issues of all ignore files
+//	gen   generates Go source which embeds the rules of all ignore files
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/aligator/nogo"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(e *env, args []string) error
+}
+
+// env is the environment the commands run in.
+// The tests replace it to run the commands without the real file system.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	// dirFS returns the file system of the dir to walk.
+	dirFS func(dir string) fs.FS
+}
+
+// flagError is returned for invalid flags, which are already reported
+// by the flag set.
+type flagError struct {
+	err error
+}
+
+func (e *flagError) Error() string {
+	return e.err.Error()
+}
+
+func (e *flagError) Unwrap() error {
+	return e.err
+}
+
+var commands = []command{
+	{
+		name:  "list",
+		usage: "prints all files and folders which are not ignored",
+		run:   runList,
+	},
+	{
+		name:  "tree",
+		usage: "prints the not ignored files and folders as a tree",
+		run:   runTree,
+	},
+	{
+		name:  "lint",
+		usage: "reports issues of all ignore files",
+		run:   runLint,
+	},
+	{
+		name:  "gen",
+		usage: "generates Go source which embeds the rules of all ignore files",
+		run:   runGen,
+	},
+}
+
+func main() {
+	os.Exit(run(&env{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		dirFS:  os.DirFS,
+	}, os.Args[1:]))
+}
+
+// run runs the command of the arguments and returns the exit code.
+func run(e *env, args []string) int {
+	set := e.flagSet("nogo")
+	set.Usage = func() { e.usage() }
+	if err := parse(set, args); err != nil {
+		return e.exitCode(err)
+	}
+
+	if set.NArg() < 1 {
+		e.usage()
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == set.Arg(0) {
+			if err := cmd.run(e, set.Args()[1:]); err != nil {
+				return e.exitCode(err)
+			}
+			return 0
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "nogo: unknown command %q\n", set.Arg(0))
+	e.usage()
+	return 2
+}
+
+// exitCode reports the error if needed and returns the exit code for it.
+// Like flag.ExitOnError, it is 0 for -help and 2 for invalid flags.
+func (e *env) exitCode(err error) int {
+	var flagErr *flagError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &flagErr):
+		return 2
+	default:
+		fmt.Fprintln(e.stderr, "nogo:", err)
+		return 1
+	}
+}
+
+func (e *env) usage() {
+	fmt.Fprintln(e.stderr, "Usage: nogo <command> [flags] [dir]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "The commands are:")
+	for _, cmd := range commands {
+		fmt.Fprintf(e.stderr, "  %-6s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// flagSet creates a flag set which reports invalid flags to stderr.
+func (e *env) flagSet(name string) *flag.FlagSet {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(e.stderr)
+	return set
+}
+
+// parse parses the flags of a command.
+func parse(set *flag.FlagSet, args []string) error {
+	if err := set.Parse(args); err != nil {
+		return &flagError{err: err}
+	}
+	return nil
+}
+
+// walkFlags are the flags shared by all commands which walk a directory.
+type walkFlags struct {
+	ignoreFile string
+	noDotGit   bool
+	excludes   stringsFlag
+}
+
+func (w *walkFlags) register(set *flag.FlagSet) {
+	set.StringVar(&w.ignoreFile, "ignore-file", ".gitignore", "the name of the ignore files to load")
+	set.BoolVar(&w.noDotGit, "no-dot-git", false, "do not ignore .git folders automatically")
+	set.Var(&w.excludes, "exclude", "additional pattern relative to the dir, may be repeated")
+}
+
+// stringsFlag is a flag which may be set several times.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// dir returns the directory to walk which is either the first argument
+// or the working directory.
+func dir(set *flag.FlagSet) (string, error) {
+	if set.NArg() > 0 {
+		return set.Arg(0), nil
+	}
+
+	return os.Getwd()
+}
+
+// walk loads all ignore files of the fsys and then calls fn
+// for all files and folders which are not ignored.
+func (w *walkFlags) walk(fsys fs.FS, fn fs.WalkDirFunc) error {
+	n, err := w.load(fsys)
+	if err != nil {
+		return err
+	}
+
+	return fs.WalkDir(n.ForWalkDir(fsys, ".", fn))
+}
+
+// load loads all ignore files of the fsys together with the additional rules of the flags.
+func (w *walkFlags) load(fsys fs.FS) (*nogo.NoGo, error) {
+	var opts []nogo.Option
+	if !w.noDotGit {
+		opts = append(opts, nogo.DotGitRule)
+	}
+
+	n := nogo.New(opts...)
+	if err := n.AddFromFS(fsys, w.ignoreFile); err != nil {
+		return nil, err
+	}
+	if err := n.AddPatterns("", w.excludes, nogo.WithAnchoring(nogo.AnchorPrefix)); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\nbuild/\n")},
+		".git/config":    {Data: []byte("")},
+		"main.go":        {Data: []byte("package main\n")},
+		"debug.log":      {Data: []byte("")},
+		"build/out.o":    {Data: []byte("")},
+		"sub/.gitignore": {Data: []byte("!keep.log\n")},
+		"sub/a.go":       {Data: []byte("package sub\n")},
+		"sub/keep.log":   {Data: []byte("")},
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		fsys       fstest.MapFS
+		wantCode   int
+		wantStdout string
+		// stdoutPrefix only compares the start of the stdout.
+		stdoutPrefix bool
+		wantStderr   string
+	}{
+		{
+			name:       "no command",
+			wantCode:   2,
+			wantStderr: "Usage: nogo <command> [flags] [dir]",
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"other"},
+			wantCode:   2,
+			wantStderr: `nogo: unknown command "other"`,
+		},
+		{
+			name:       "help",
+			args:       []string{"-h"},
+			wantCode:   0,
+			wantStderr: "Usage: nogo <command> [flags] [dir]",
+		},
+		{
+			name:       "list",
+			args:       []string{"list", "dir"},
+			fsys:       testFS(),
+			wantStdout: ".gitignore\nmain.go\nsub/\nsub/.gitignore\nsub/a.go\nsub/keep.log\n",
+		},
+		{
+			name:       "list json",
+			args:       []string{"list", "-json", "dir"},
+			fsys:       fstest.MapFS{".gitignore": {Data: []byte("*.log\n")}, "a.go": {}, "b.log": {}},
+			wantStdout: "[\n  \".gitignore\",\n  \"a.go\"\n]\n",
+		},
+		{
+			name:       "list empty json",
+			args:       []string{"list", "-json", "dir"},
+			fsys:       fstest.MapFS{".gitignore": {Data: []byte("*\n")}},
+			wantStdout: "[]\n",
+		},
+		{
+			name:       "list exclude",
+			args:       []string{"list", "-exclude", "sub", "-exclude", "*.go", "dir"},
+			fsys:       testFS(),
+			wantStdout: ".gitignore\n",
+		},
+		{
+			name:       "list with .git",
+			args:       []string{"list", "-no-dot-git", "-ignore-file", ".none", "dir"},
+			fsys:       fstest.MapFS{".git/config": {}, "a.go": {}},
+			wantStdout: ".git/\n.git/config\na.go\n",
+		},
+		{
+			name:       "list invalid pattern",
+			args:       []string{"list", "dir"},
+			fsys:       fstest.MapFS{".gitignore": {Data: []byte("[a\n")}},
+			wantCode:   1,
+			wantStderr: "nogo: ",
+		},
+		{
+			name:       "list invalid flag",
+			args:       []string{"list", "-other", "dir"},
+			fsys:       testFS(),
+			wantCode:   2,
+			wantStderr: "flag provided but not defined: -other",
+		},
+		{
+			name:       "tree",
+			args:       []string{"tree", "dir"},
+			fsys:       testFS(),
+			wantStdout: ".\n├── .gitignore\n├── main.go\n└── sub/\n    ├── .gitignore\n    ├── a.go\n    └── keep.log\n",
+		},
+		{
+			name:       "tree json",
+			args:       []string{"tree", "-json", "dir"},
+			fsys:       fstest.MapFS{".gitignore": {Data: []byte("*.log\n")}, "b.log": {}},
+			wantStdout: "{\n  \"name\": \".\",\n  \"path\": \".\",\n  \"isDir\": true,\n  \"children\": [\n    {\n      \"name\": \".gitignore\",\n      \"path\": \".gitignore\",\n      \"isDir\": false\n    }\n  ]\n}\n",
+		},
+		{
+			name:       "tree exclude",
+			args:       []string{"tree", "-exclude", "sub", "dir"},
+			fsys:       testFS(),
+			wantStdout: ".\n├── .gitignore\n└── main.go\n",
+		},
+		{
+			name: "lint",
+			args: []string{"lint", "dir"},
+			fsys: testFS(),
+		},
+		{
+			name:       "lint problems",
+			args:       []string{"lint", "dir"},
+			fsys:       fstest.MapFS{"sub/.gitignore": {Data: []byte("*.log\n[a\n")}, ".git/.gitignore": {Data: []byte("[a\n")}},
+			wantCode:   1,
+			wantStdout: "sub/.gitignore:2: error: unclosed range, the pattern never matches (never-matches)\n",
+			wantStderr: "nogo: found 1 problems",
+		},
+		{
+			name:         "gen",
+			args:         []string{"gen", "-pkg", "defaults", "-var", "Excludes", "dir"},
+			fsys:         testFS(),
+			wantStdout:   "// Code generated by \"nogo gen\"; DO NOT EDIT.\n\npackage defaults\n",
+			stdoutPrefix: true,
+		},
+		{
+			name:       "gen without package",
+			args:       []string{"gen", "dir"},
+			fsys:       testFS(),
+			wantCode:   1,
+			wantStderr: "nogo: gen: -pkg is required outside of go:generate",
+		},
+		{
+			name:       "gen invalid variable",
+			args:       []string{"gen", "-pkg", "defaults", "-var", "a-b", "dir"},
+			fsys:       testFS(),
+			wantCode:   1,
+			wantStderr: `nogo: gen: invalid variable name "a-b"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOPACKAGE", "")
+
+			var stdout, stderr bytes.Buffer
+			code := run(&env{
+				stdout: &stdout,
+				stderr: &stderr,
+				dirFS: func(dir string) fs.FS {
+					assert.Equal(t, "dir", dir)
+					return tt.fsys
+				},
+			}, tt.args)
+
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			if tt.stdoutPrefix {
+				assert.True(t, strings.HasPrefix(stdout.String(), tt.wantStdout), stdout.String())
+			} else {
+				assert.Equal(t, tt.wantStdout, stdout.String())
+			}
+			if tt.wantStderr == "" {
+				assert.Empty(t, stderr.String())
+			} else {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+//go:build js && wasm
+
+// Nogowasm exports the nogo matcher to JavaScript, so web based tools, such as
+// repository viewers, can use exactly the same rules as the Go tools.
+//
+// Build it using:
+//
+//	GOOS=js GOARCH=wasm go build -o nogo.wasm ./cmd/nogowasm
+//
+// and load it using the wasm_exec.js of the Go distribution. It sets the
+// global object "nogo":
+//
+//	// files maps the paths of the ignore files to their content.
+//	const matcher = nogo.compile({".gitignore": "*.log\n", "sub/.gitignore": "/build/\n"})
+//	if (matcher instanceof Error) { ... }
+//
+//	matcher.match("sub/build", true)        // true
+//	matcher.matchBecause("debug.log", false) // {ignored: true, kind: "direct match", pattern: "*.log", prefix: ""}
+//
+//	nogo.matchPattern("*.log", "a/debug.log", false) // true
+package main
+
+import (
+	"io/fs"
+	"path"
+	"syscall/js"
+	"testing/fstest"
+
+	"github.com/aligator/nogo"
+)
+
+func main() {
+	js.Global().Set("nogo", js.ValueOf(map[string]interface{}{
+		"compile":      js.FuncOf(compile),
+		"matchPattern": js.FuncOf(matchPattern),
+	}))
+
+	// Keep the exported functions alive.
+	select {}
+}
+
+// compile loads the ignore files of the object passed as first argument,
+// which maps their paths to their content. The optional second argument may
+// set {ignoreFileName: ".gitignore", ignoreCase: false, dotGit: true}.
+func compile(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].Type() != js.TypeObject {
+		return jsError("compile: expected an object with the ignore files")
+	}
+
+	ignoreFileName := ".gitignore"
+	opts := []nogo.Option{nogo.DotGitRule}
+	if len(args) > 1 && args[1].Type() == js.TypeObject {
+		if name := args[1].Get("ignoreFileName"); name.Type() == js.TypeString {
+			ignoreFileName = name.String()
+		}
+		if ignoreCase := args[1].Get("ignoreCase"); ignoreCase.Truthy() {
+			opts = append(opts, nogo.WithIgnoreCase())
+		}
+		if dotGit := args[1].Get("dotGit"); dotGit.Type() == js.TypeBoolean && !dotGit.Bool() {
+			opts = opts[1:]
+		}
+	}
+
+	// The files are loaded from an in-memory file system, so ignore files
+	// in ignored folders are skipped exactly as for a real one.
+	fsys := fstest.MapFS{}
+	keys := js.Global().Get("Object").Call("keys", args[0])
+	for i := 0; i < keys.Length(); i++ {
+		name := keys.Index(i).String()
+		if !fs.ValidPath(name) || path.Base(name) != ignoreFileName {
+			continue
+		}
+		fsys[name] = &fstest.MapFile{Data: []byte(args[0].Get(name).String())}
+	}
+
+	n, err := nogo.ForFS(fsys, ignoreFileName, opts...)
+	if err != nil {
+		return jsError(err.Error())
+	}
+
+	return js.ValueOf(map[string]interface{}{
+		"match": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			if len(args) < 1 {
+				return jsError("match: expected a path")
+			}
+			return n.Match(args[0].String(), len(args) > 1 && args[1].Truthy())
+		}),
+		"matchBecause": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			if len(args) < 1 {
+				return jsError("matchBecause: expected a path")
+			}
+			ignored, because := n.MatchBecause(args[0].String(), len(args) > 1 && args[1].Truthy())
+			return js.ValueOf(map[string]interface{}{
+				"ignored": ignored,
+				"kind":    because.Kind().String(),
+				"pattern": because.Pattern,
+				"prefix":  because.Prefix,
+			})
+		}),
+	})
+}
+
+// matchPattern calls nogo.MatchPattern with the pattern, the path and isDir.
+func matchPattern(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return jsError("matchPattern: expected a pattern and a path")
+	}
+	match, err := nogo.MatchPattern(args[0].String(), args[1].String(), len(args) > 2 && args[2].Truthy())
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return match
+}
+
+// jsError creates a JavaScript Error, which is returned instead of throwing it.
+func jsError(message string) js.Value {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,13 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "nogowasm: only GOOS=js GOARCH=wasm is supported")
+	os.Exit(2)
+}
@@ -0,0 +1,217 @@
+package nogo
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// compiledRules indexes rules by a literal text which has to occur in every
+// path the rule matches. An Aho-Corasick automaton finds all these literals
+// in a single pass over the path, so only the rules which may match have to be
+// checked using their regexps.
+type compiledRules struct {
+	// rules contains the rules of all groups in the order they have to be checked.
+	rules    []compiledRule
+	literals *ahoCorasick
+
+	// always contains the indexes of the rules without any literal.
+	always []int
+
+	// candidates is a pool of *[]int used while matching.
+	candidates sync.Pool
+}
+
+type compiledRule struct {
+	prefix string
+	rule   Rule
+	tag    string
+
+	// glob is only set for the GlobBackend.
+	glob *glob
+
+	// hits is only set if WithStats is used.
+	hits *uint64
+}
+
+// compileRules indexes the rules of the groups.
+// globs may be nil if the RegexpBackend is used.
+func compileRules(groups []group, globs [][]*glob) *compiledRules {
+	c := &compiledRules{
+		literals: newAhoCorasick(),
+	}
+
+	for gi, g := range groups {
+		for ri, rule := range g.rules {
+			index := len(c.rules)
+			compiled := compiledRule{prefix: g.prefix, rule: rule, tag: g.tag}
+			if globs != nil {
+				compiled.glob = globs[gi][ri]
+			}
+			if g.hits != nil {
+				compiled.hits = &g.hits[ri]
+			}
+			c.rules = append(c.rules, compiled)
+
+			if literal := requiredLiteral(rule); literal != "" {
+				c.literals.add(literal, index)
+			} else {
+				c.always = append(c.always, index)
+			}
+		}
+	}
+
+	c.literals.build()
+	c.candidates.New = func() interface{} {
+		candidates := make([]int, 0, 16)
+		return &candidates
+	}
+	return c
+}
+
+// requiredLiteral returns the longest literal which has to occur in every path
+// matched by the rule. Literals of the pattern are preferred to the prefix,
+// as all rules of a group share the same prefix.
+// It returns "" if there is no such literal.
+func requiredLiteral(rule Rule) string {
+	primitives := rule.Matchers()
+	if rule.Prefix != "" && len(primitives) >= 2 {
+		// Skip the prefix and its separator.
+		primitives = primitives[2:]
+	}
+
+	var literal string
+	for _, p := range primitives {
+		if p.Kind == PrimitiveLiteral && len(p.Value) > len(literal) {
+			literal = p.Value
+		}
+	}
+
+	if literal == "" {
+		return rule.Prefix
+	}
+	return literal
+}
+
+// match returns the result of the last rule which matches the path.
+func (c *compiledRules) match(path string, isDir bool) (because Result, found bool) {
+	pooled := c.candidates.Get().(*[]int)
+	defer c.candidates.Put(pooled)
+
+	candidates := append((*pooled)[:0], c.always...)
+	candidates = c.literals.find(path, candidates)
+	sort.Ints(candidates)
+
+	last := -1
+	for _, index := range candidates {
+		// The same rule may be found several times.
+		if index == last {
+			continue
+		}
+		last = index
+
+		r := c.rules[index]
+		if !isPathInside(path, r.prefix) {
+			continue
+		}
+
+		newRes := matchRule(r.rule, r.glob, path)
+		if newRes.Found && ((newRes.OnlyFolder && isDir) || !newRes.OnlyFolder) {
+			newRes.Tag = r.tag
+			because = newRes
+			found = true
+			if r.hits != nil {
+				atomic.AddUint64(r.hits, 1)
+			}
+		}
+	}
+
+	*pooled = candidates
+	return because, found
+}
+
+// ahoCorasick finds all added literals in a text in a single pass.
+type ahoCorasick struct {
+	nodes []acNode
+}
+
+type acNode struct {
+	next map[byte]int
+	fail int
+
+	// outputs are the values of all literals ending at this node,
+	// including the ones of the fail chain after build.
+	outputs []int
+}
+
+func newAhoCorasick() *ahoCorasick {
+	return &ahoCorasick{
+		nodes: []acNode{{next: map[byte]int{}}},
+	}
+}
+
+// add the literal with the given value.
+// build has to be called after adding all literals.
+func (a *ahoCorasick) add(literal string, value int) {
+	current := 0
+	for i := 0; i < len(literal); i++ {
+		next, ok := a.nodes[current].next[literal[i]]
+		if !ok {
+			next = len(a.nodes)
+			a.nodes = append(a.nodes, acNode{next: map[byte]int{}})
+			a.nodes[current].next[literal[i]] = next
+		}
+		current = next
+	}
+	a.nodes[current].outputs = append(a.nodes[current].outputs, value)
+}
+
+// build calculates the fail links breadth first.
+func (a *ahoCorasick) build() {
+	queue := make([]int, 0, len(a.nodes))
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for c, child := range a.nodes[current].next {
+			fail := a.nodes[current].fail
+			for {
+				if next, ok := a.nodes[fail].next[c]; ok {
+					a.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					a.nodes[child].fail = 0
+					break
+				}
+				fail = a.nodes[fail].fail
+			}
+
+			a.nodes[child].outputs = append(a.nodes[child].outputs, a.nodes[a.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// find appends the values of all literals which occur in the text to found.
+func (a *ahoCorasick) find(text string, found []int) []int {
+	current := 0
+	for i := 0; i < len(text); i++ {
+		for {
+			if next, ok := a.nodes[current].next[text[i]]; ok {
+				current = next
+				break
+			}
+			if current == 0 {
+				break
+			}
+			current = a.nodes[current].fail
+		}
+		found = append(found, a.nodes[current].outputs...)
+	}
+	return found
+}
@@ -0,0 +1,98 @@
+package nogo
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	a := newAhoCorasick()
+	a.add("he", 0)
+	a.add("she", 1)
+	a.add("his", 2)
+	a.add("hers", 3)
+	a.add(".go", 4)
+	a.build()
+
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{text: "ushers", want: []int{0, 1, 3}},
+		{text: "his/main.go", want: []int{2, 4}},
+		{text: "nothing", want: nil},
+		{text: "hehe", want: []int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := a.find(tt.text, nil)
+			sort.Ints(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNoGo_Compile(t *testing.T) {
+	t.Run("same results as without compile", func(t *testing.T) {
+		n := &NoGo{groups: TestFSGroups}
+		n.Compile()
+
+		for path, tt := range TestFSData {
+			gotMatch, gotBecause := n.MatchBecause(path, tt.isDir)
+			wantMatch, wantBecause := (&NoGo{groups: TestFSGroups}).MatchBecause(path, tt.isDir)
+			assert.Equal(t, wantMatch, gotMatch, path)
+			assert.Equal(t, wantBecause, gotBecause, path)
+		}
+	})
+
+	t.Run("same results as without compile for many rules", func(t *testing.T) {
+		rules := MustCompileAll("", benchRules(100))
+		n := New()
+		n.AddRules(rules...)
+		compiled := New()
+		compiled.AddRules(rules...)
+		compiled.Compile()
+
+		paths := append(benchPaths, "build7/a/b", "a/keep3.ext3", "a/keep3.ext4", "generated8", "x/generated8")
+		for _, path := range paths {
+			for _, isDir := range []bool{true, false} {
+				wantMatch, wantBecause := n.MatchBecause(path, isDir)
+				gotMatch, gotBecause := compiled.MatchBecause(path, isDir)
+				assert.Equal(t, wantMatch, gotMatch, path)
+				assert.Equal(t, wantBecause, gotBecause, path)
+			}
+		}
+	})
+
+	t.Run("rules added after compile", func(t *testing.T) {
+		n := New()
+		n.Compile()
+		assert.False(t, n.Match("a/b.go", false))
+
+		n.AddRules(MustCompileAll("", []byte("*.go"))...)
+		assert.True(t, n.Match("a/b.go", false))
+	})
+}
+
+func TestRequiredLiteral(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		pattern string
+		want    string
+	}{
+		{pattern: "*.go", want: ".go"},
+		{pattern: "/build/**/output*", want: "output"},
+		{prefix: "a/folder", pattern: "*.go", want: ".go"},
+		{prefix: "a/folder", pattern: "*", want: "a/folder"},
+		{pattern: "*", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, rule, err := Compile(tt.prefix, tt.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, requiredLiteral(rule))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package nogo
+
+import "io/fs"
+
+// Matcher decides if paths are ignored.
+// NoGo and all composed matchers implement it.
+type Matcher interface {
+	// Match returns true if the path is ignored.
+	Match(path string, isDir bool) bool
+
+	// MatchBecause returns true if the path is ignored and the Result
+	// of the rule which caused the decision.
+	MatchBecause(path string, isDir bool) (match bool, because Result)
+}
+
+var (
+	_ Matcher = (*NoGo)(nil)
+	_ Matcher = MatcherFunc(nil)
+)
+
+// MatcherFunc is an adapter to use an ordinary function as Matcher,
+// e.g. for hard-coded checks or mocks in tests.
+type MatcherFunc func(path string, isDir bool) (match bool, because Result)
+
+// Match calls f and returns only the match.
+func (f MatcherFunc) Match(path string, isDir bool) bool {
+	match, _ := f(path, isDir)
+	return match
+}
+
+// MatchBecause calls f.
+func (f MatcherFunc) MatchBecause(path string, isDir bool) (match bool, because Result) {
+	return f(path, isDir)
+}
+
+// WalkDirMatcher walks the file tree the same way as fs.WalkDir but skips
+// all files and directories which are ignored by the matcher.
+// The fn is only called for paths which are not ignored. The root "." is
+// never ignored.
+//
+// Use it for composed matchers. For a single NoGo, NoGo.WalkDir is faster
+// as it doesn't check the parents of each path again.
+func WalkDirMatcher(fsys fs.FS, root string, m Matcher, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(path, d, err)
+		}
+
+		if path != "." && m.Match(path, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		return fn(path, d, nil)
+	})
+}
+
+// Union returns a Matcher which ignores a path if any of the matchers ignores it.
+//
+// The Result is the one of the first matcher which ignores the path.
+// If no matcher ignores it, it is the last Result which was found,
+// e.g. the Result of a negated rule.
+func Union(matchers ...Matcher) Matcher {
+	return unionMatcher(matchers)
+}
+
+// Intersect returns a Matcher which ignores a path only if all matchers ignore it.
+// Without any matcher, nothing is ignored.
+//
+// The Result is the one of the first matcher which does not ignore the path.
+// If all matchers ignore it, it is the Result of the last matcher.
+func Intersect(matchers ...Matcher) Matcher {
+	return intersectMatcher(matchers)
+}
+
+// Subtract returns a Matcher which ignores a path if the base ignores it
+// but none of the matchers to remove ignores it. E.g. an allow list of a user
+// can be subtracted from the policy of a company:
+//
+//	nogo.Subtract(nogo.Union(companyPolicy, repoPolicy), userAllowList)
+//
+// If the path is removed, the Result is the one of the matcher which removed it.
+// Note that Result.Resolve of that Result returns true in this case, as the
+// rule matched. Use the returned bool for the decision.
+// Otherwise the Result is the one of the base.
+func Subtract(base Matcher, remove ...Matcher) Matcher {
+	return subtractMatcher{base: base, remove: remove}
+}
+
+// Chain returns a Matcher which checks the matchers in order, and the last
+// matcher which has a decision for a path wins. So later matchers override
+// earlier ones, the same way as the rules of a .dockerignore loaded after
+// a .gitignore would do it. E.g. a negated rule of a later matcher includes
+// a path ignored by an earlier one.
+//
+// A matcher has a decision if it ignores the path or a rule matched it
+// (Result.Found). If no matcher has a decision, the path is not ignored.
+// The Result is the one of the matcher which decided.
+func Chain(matchers ...Matcher) Matcher {
+	return chainMatcher(matchers)
+}
+
+type unionMatcher []Matcher
+
+func (u unionMatcher) Match(path string, isDir bool) bool {
+	match, _ := u.MatchBecause(path, isDir)
+	return match
+}
+
+func (u unionMatcher) MatchBecause(path string, isDir bool) (match bool, because Result) {
+	for _, m := range u {
+		match, res := m.MatchBecause(path, isDir)
+		if match {
+			return true, res
+		}
+		if res.Found {
+			because = res
+		}
+	}
+	return false, because
+}
+
+type intersectMatcher []Matcher
+
+func (i intersectMatcher) Match(path string, isDir bool) bool {
+	match, _ := i.MatchBecause(path, isDir)
+	return match
+}
+
+func (i intersectMatcher) MatchBecause(path string, isDir bool) (match bool, because Result) {
+	for _, m := range i {
+		match, because = m.MatchBecause(path, isDir)
+		if !match {
+			return false, because
+		}
+	}
+	return match, because
+}
+
+type subtractMatcher struct {
+	base   Matcher
+	remove []Matcher
+}
+
+func (s subtractMatcher) Match(path string, isDir bool) bool {
+	match, _ := s.MatchBecause(path, isDir)
+	return match
+}
+
+func (s subtractMatcher) MatchBecause(path string, isDir bool) (match bool, because Result) {
+	match, because = s.base.MatchBecause(path, isDir)
+	if !match {
+		return false, because
+	}
+
+	for _, m := range s.remove {
+		if removed, res := m.MatchBecause(path, isDir); removed {
+			return false, res
+		}
+	}
+	return true, because
+}
+
+type chainMatcher []Matcher
+
+func (c chainMatcher) Match(path string, isDir bool) bool {
+	match, _ := c.MatchBecause(path, isDir)
+	return match
+}
+
+func (c chainMatcher) MatchBecause(path string, isDir bool) (match bool, because Result) {
+	for i := len(c) - 1; i >= 0; i-- {
+		m, res := c[i].MatchBecause(path, isDir)
+		if m || res.Found {
+			return m, res
+		}
+	}
+	return false, because
+}
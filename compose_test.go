@@ -0,0 +1,199 @@
+package nogo
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newComposeTestNoGo(data string) *NoGo {
+	n := New()
+	n.AddRules(MustCompileAll("", []byte(data))...)
+	return n
+}
+
+func TestUnion(t *testing.T) {
+	a := newComposeTestNoGo("*.log\n!keep.log")
+	b := newComposeTestNoGo("/build\n*.tmp")
+	m := Union(a, b)
+
+	tests := []struct {
+		path        string
+		isDir       bool
+		want        bool
+		wantPattern string
+	}{
+		{path: "debug.log", want: true, wantPattern: "*.log"},
+		{path: "build", isDir: true, want: true, wantPattern: "/build"},
+		{path: "a.tmp", want: true, wantPattern: "*.tmp"},
+		{path: "main.go", want: false},
+		// The negation of a is the cause.
+		{path: "keep.log", want: false, wantPattern: "!keep.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because := m.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+			assert.Equal(t, tt.wantPattern, because.Pattern)
+		})
+	}
+
+	assert.False(t, Union().Match("a", false))
+}
+
+func TestIntersect(t *testing.T) {
+	a := newComposeTestNoGo("*.log")
+	b := newComposeTestNoGo("/logs")
+	m := Intersect(a, b)
+
+	tests := []struct {
+		path        string
+		isDir       bool
+		want        bool
+		wantPattern string
+	}{
+		{path: "logs/debug.log", want: true, wantPattern: "/logs"},
+		{path: "debug.log", want: false, wantPattern: ""},
+		{path: "logs/main.go", want: false, wantPattern: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because := m.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+			assert.Equal(t, tt.wantPattern, because.Pattern)
+		})
+	}
+
+	assert.False(t, Intersect().Match("a", false))
+}
+
+func TestSubtract(t *testing.T) {
+	company := newComposeTestNoGo("*.log\n*.secret")
+	repo := newComposeTestNoGo("/build")
+	allowList := newComposeTestNoGo("important.log")
+	m := Subtract(Union(company, repo), allowList)
+
+	tests := []struct {
+		path        string
+		isDir       bool
+		want        bool
+		wantPattern string
+	}{
+		{path: "debug.log", want: true, wantPattern: "*.log"},
+		{path: "build", isDir: true, want: true, wantPattern: "/build"},
+		{path: "important.log", want: false, wantPattern: "important.log"},
+		{path: "main.go", want: false, wantPattern: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because := m.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+			assert.Equal(t, tt.wantPattern, because.Pattern)
+		})
+	}
+}
+
+func TestMatcherFunc(t *testing.T) {
+	m := MatcherFunc(func(path string, isDir bool) (bool, Result) {
+		if isDir && path == "vendor" {
+			return true, Result{Found: true, Rule: Rule{Pattern: "vendor/"}}
+		}
+		return false, Result{}
+	})
+
+	assert.True(t, m.Match("vendor", true))
+	assert.False(t, m.Match("vendor", false))
+
+	// It can be composed like any other matcher.
+	u := Union(newComposeTestNoGo("*.log"), m)
+	match, because := u.MatchBecause("vendor", true)
+	assert.True(t, match)
+	assert.Equal(t, "vendor/", because.Pattern)
+	assert.True(t, u.Match("a.log", false))
+}
+
+func TestWalkDirMatcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":          {},
+		"debug.log":        {},
+		"build/out.bin":    {},
+		"src/a.go":         {},
+		"src/keep.log":     {},
+		"src/vendor/b.go":  {},
+		"vendor/c.go":      {},
+		"docs/readme.md":   {},
+		"docs/api/api.log": {},
+	}
+
+	m := Subtract(
+		Union(newComposeTestNoGo("*.log\n/build"), MatcherFunc(func(path string, isDir bool) (bool, Result) {
+			return isDir && strings.HasSuffix("/"+path, "/vendor"), Result{Found: true}
+		})),
+		newComposeTestNoGo("keep.log"),
+	)
+
+	var walked []string
+	err := WalkDirMatcher(fsys, ".", m, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		walked = append(walked, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "docs", "docs/api", "docs/readme.md", "main.go", "src", "src/a.go", "src/keep.log"}, walked)
+
+	t.Run("error", func(t *testing.T) {
+		err := WalkDirMatcher(fsys, "missing", m, func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestChain(t *testing.T) {
+	gitignore := newComposeTestNoGo("*.log\n/build\n/dist")
+	dockerignore := newComposeTestNoGo("!debug.log\n/docs\n!/dist")
+	m := Chain(gitignore, dockerignore)
+
+	tests := []struct {
+		path        string
+		isDir       bool
+		want        bool
+		wantPattern string
+	}{
+		{path: "a.log", want: true, wantPattern: "*.log"},
+		{path: "debug.log", want: false, wantPattern: "!debug.log"},
+		{path: "build", isDir: true, want: true, wantPattern: "/build"},
+		{path: "docs", isDir: true, want: true, wantPattern: "/docs"},
+		{path: "dist", isDir: true, want: false, wantPattern: "!/dist"},
+		{path: "main.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because := m.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+			assert.Equal(t, tt.wantPattern, because.Pattern)
+		})
+	}
+
+	t.Run("allow list", func(t *testing.T) {
+		allow := NewAllowList()
+		allow.AddRules(MustCompileAll("", []byte("*.go"))...)
+		m := Chain(gitignore, allow)
+
+		// The allow list excludes everything not listed, without a rule.
+		assert.True(t, m.Match("README.md", false))
+		assert.False(t, m.Match("main.go", false))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.False(t, Chain().Match("a", false))
+	})
+}
@@ -0,0 +1,65 @@
+package nogo
+
+import (
+	"os"
+	"path"
+)
+
+// IgnoreConfig contains the ignore settings of a tool, so they can be part of
+// its config file without writing adapter code:
+//
+//	var config struct {
+//		Ignore nogo.IgnoreConfig `json:"ignore"`
+//	}
+//	// ...
+//	n, err := nogo.FromConfig(config.Ignore)
+//
+// All fields are optional. The dialect is written by its name, e.g. "helmignore".
+type IgnoreConfig struct {
+	// Root is the folder the patterns and files are relative to.
+	// It defaults to the working directory.
+	Root string `json:"root,omitempty" yaml:"root,omitempty"`
+
+	// Patterns are added after the files, relative to Root.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+
+	// Files are ignore files inside of Root, e.g. ".gitignore" or
+	// "docker/.dockerignore". Like for AddFile, their rules are relative to
+	// the folder of the file. Missing files are an error.
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+
+	// Dialect is the syntax of the patterns and files.
+	Dialect Dialect `json:"dialect,omitempty" yaml:"dialect,omitempty"`
+
+	// IgnoreCase enables case-insensitive matching, see WithIgnoreCase.
+	IgnoreCase bool `json:"ignoreCase,omitempty" yaml:"ignoreCase,omitempty"`
+}
+
+// FromConfig creates a NoGo instance from the config. The files are loaded
+// immediately and their errors are returned, as well as invalid patterns.
+func FromConfig(cfg IgnoreConfig) (*NoGo, error) {
+	opts := []Option{WithDialect(cfg.Dialect)}
+	if cfg.IgnoreCase {
+		opts = append(opts, WithIgnoreCase())
+	}
+	n := New(opts...)
+
+	root := cfg.Root
+	if root == "" {
+		root = "."
+	}
+	fsys := os.DirFS(root)
+
+	for _, file := range cfg.Files {
+		if err := n.AddFile(fsys, path.Clean(file)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cfg.Patterns) > 0 {
+		if err := n.AddPatterns("", cfg.Patterns); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package nogo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreConfig_json(t *testing.T) {
+	var config struct {
+		Ignore IgnoreConfig `json:"ignore"`
+	}
+	data := `{"ignore": {"root": "repo", "patterns": ["*.log"], "files": [".gitignore"], "dialect": "helmignore", "ignoreCase": true}}`
+	require.NoError(t, json.Unmarshal([]byte(data), &config))
+
+	assert.Equal(t, IgnoreConfig{
+		Root:       "repo",
+		Patterns:   []string{"*.log"},
+		Files:      []string{".gitignore"},
+		Dialect:    DialectHelmignore,
+		IgnoreCase: true,
+	}, config.Ignore)
+
+	written, err := json.Marshal(IgnoreConfig{Patterns: []string{"*.tmp"}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"patterns":["*.tmp"]}`, string(written))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"dialect": "unknown"}`), &IgnoreConfig{}))
+}
+
+func TestFromConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, ".gitignore", "*.log\n")
+	writeTestFile(t, root, "docs/.ignore", "/*.tmp\n")
+
+	t.Run("files and patterns", func(t *testing.T) {
+		n, err := FromConfig(IgnoreConfig{
+			Root:     root,
+			Files:    []string{".gitignore", "./docs/.ignore"},
+			Patterns: []string{"/build", "!keep.log"},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, n.Match("debug.log", false))
+		assert.False(t, n.Match("keep.log", false))
+		assert.True(t, n.Match("docs/a.tmp", false))
+		assert.False(t, n.Match("a.tmp", false))
+		assert.True(t, n.Match("build", true))
+		assert.False(t, n.Match("DEBUG.LOG", false))
+	})
+
+	t.Run("ignore case", func(t *testing.T) {
+		n, err := FromConfig(IgnoreConfig{Root: root, Files: []string{".gitignore"}, IgnoreCase: true})
+		require.NoError(t, err)
+		assert.True(t, n.Match("DEBUG.LOG", false))
+	})
+
+	t.Run("dialect", func(t *testing.T) {
+		n, err := FromConfig(IgnoreConfig{Patterns: []string{"{a,b}.txt"}, Dialect: DialectBraces})
+		require.NoError(t, err)
+		assert.True(t, n.Match("b.txt", false))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := FromConfig(IgnoreConfig{Root: root, Files: []string{"missing"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("file outside of the root", func(t *testing.T) {
+		_, err := FromConfig(IgnoreConfig{Root: root, Files: []string{"../.gitignore"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := FromConfig(IgnoreConfig{Patterns: []string{"a["}})
+		var patternErr *PatternError
+		assert.ErrorAs(t, err, &patternErr)
+	})
+}
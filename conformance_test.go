@@ -0,0 +1,206 @@
+package nogo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceCheck is a single check of a conformance file.
+type conformanceCheck struct {
+	line    int
+	todo    bool
+	path    string
+	isDir   bool
+	ignored bool
+	// because is the line deciding about the path formatted as
+	// "<source>:<line>:<pattern>", or empty if no line matches.
+	because string
+}
+
+// conformanceSuite is the content of a single conformance file.
+type conformanceSuite struct {
+	files  fstest.MapFS
+	checks []conformanceCheck
+}
+
+// conformanceEngines are the ways to match which are checked by TestConformance.
+var conformanceEngines = []struct {
+	name    string
+	opts    []Option
+	compile bool
+}{
+	{name: "regexp"},
+	{name: "compiled", compile: true},
+	{name: "glob", opts: []Option{WithBackend(GlobBackend)}},
+}
+
+// TestConformance runs the suites of testdata/conformance.
+// See testdata/conformance/README.md for the format.
+func TestConformance(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "conformance", "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		file := file
+		suite, err := readConformanceSuite(file)
+		require.NoError(t, err)
+
+		// All engines have to make the same decisions as git.
+		for _, engine := range conformanceEngines {
+			engine := engine
+			t.Run(filepath.Base(file)+"/"+engine.name, func(t *testing.T) {
+				n, err := ForFS(suite.files, ".gitignore", engine.opts...)
+				require.NoError(t, err)
+				if engine.compile {
+					n.Compile()
+				}
+
+				for _, c := range suite.checks {
+					ignored, result := n.MatchBecause(c.path, c.isDir)
+					because := ""
+					if result.Found {
+						because = conformanceBecause(t, suite.files, result.Rule)
+					}
+
+					ok := ignored == c.ignored && because == c.because
+					if c.todo {
+						// The todos document where the default engine differs from git.
+						if engine.name != conformanceEngines[0].name {
+							continue
+						}
+						assert.False(t, ok, "%s:%d: %q matches git now, change the todo to a check", file, c.line, c.path)
+						continue
+					}
+					assert.Equal(t, c.ignored, ignored, "%s:%d: %q ignored", file, c.line, c.path)
+					assert.Equal(t, c.because, because, "%s:%d: %q matched by", file, c.line, c.path)
+				}
+			})
+		}
+	}
+}
+
+// conformanceBecause finds the line of the ignore file which compiles to the rule.
+// If several lines compile to it, the last one is used as it takes precedence.
+// Like "git check-ignore -v", unescaped trailing spaces are not part of the pattern.
+func conformanceBecause(t *testing.T, files fstest.MapFS, rule Rule) string {
+	source := path.Join(rule.Prefix, ".gitignore")
+	file, ok := files[source]
+	require.True(t, ok, "no ignore file for %q", rule.Prefix)
+
+	because := ""
+	for i, line := range strings.Split(string(file.Data), "\n") {
+		skip, compiled, err := Compile(rule.Prefix, line)
+		if err != nil || skip {
+			continue
+		}
+		if compiled.Pattern == rule.Pattern && compiled.Negate == rule.Negate && compiled.OnlyFolder == rule.OnlyFolder {
+			pattern := strings.TrimRight(line, " ")
+			if len(pattern) < len(line) && strings.HasSuffix(pattern, "\\") {
+				pattern += " "
+			}
+			because = fmt.Sprintf("%s:%d:%s", source, i+1, pattern)
+		}
+	}
+	require.NotEmpty(t, because, "no line of %s compiles to %q", source, rule.Pattern)
+	return because
+}
+
+// readConformanceSuite parses a conformance file.
+func readConformanceSuite(name string) (conformanceSuite, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return conformanceSuite{}, err
+	}
+	defer f.Close()
+
+	suite := conformanceSuite{files: fstest.MapFS{}}
+	var current *fstest.MapFile
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if current != nil && strings.HasPrefix(line, "\t") {
+			current.Data = append(current.Data, line[1:]+"\n"...)
+			continue
+		}
+		current = nil
+
+		fields, err := splitConformanceFields(line)
+		if err != nil {
+			return conformanceSuite{}, fmt.Errorf("%s:%d: %w", name, lineNumber, err)
+		}
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch {
+		case fields[0] == "file" && len(fields) == 2:
+			current = &fstest.MapFile{}
+			suite.files[fields[1]] = current
+		case (fields[0] == "check" || fields[0] == "todo") && (len(fields) == 3 || len(fields) == 4):
+			c := conformanceCheck{
+				line:  lineNumber,
+				todo:  fields[0] == "todo",
+				path:  strings.TrimSuffix(fields[1], "/"),
+				isDir: strings.HasSuffix(fields[1], "/"),
+			}
+			switch fields[2] {
+			case "ignored":
+				c.ignored = true
+			case "included":
+			default:
+				return conformanceSuite{}, fmt.Errorf("%s:%d: invalid verdict %q", name, lineNumber, fields[2])
+			}
+			if len(fields) == 4 {
+				c.because = fields[3]
+			}
+			suite.checks = append(suite.checks, c)
+		default:
+			return conformanceSuite{}, fmt.Errorf("%s:%d: invalid line %q", name, lineNumber, line)
+		}
+	}
+	return suite, scanner.Err()
+}
+
+// splitConformanceFields splits the line at spaces. Fields starting with '"'
+// are unquoted as Go strings, so they may contain spaces.
+func splitConformanceFields(line string) ([]string, error) {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			return fields, nil
+		}
+
+		if line[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, err
+			}
+			field, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+			line = line[len(quoted):]
+			continue
+		}
+
+		end := strings.IndexByte(line, ' ')
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
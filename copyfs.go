@@ -0,0 +1,152 @@
+package nogo
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ReadLinkFS is a fs.FS which can read the target of a symlink.
+// It has the same method as fs.ReadLinkFS of newer Go versions,
+// which is implemented e.g. by os.DirFS since Go 1.25.
+type ReadLinkFS interface {
+	fs.FS
+
+	// ReadLink returns the target of the symlink with the name.
+	ReadLink(name string) (string, error)
+}
+
+// CopyOption configures CopyFS.
+type CopyOption interface {
+	applyCopy(c *copier)
+}
+
+type copyOptionFunc func(c *copier)
+
+func (f copyOptionFunc) applyCopy(c *copier) {
+	f(c)
+}
+
+// WithCopySymlinks copies symlinks as symlinks with the same target.
+// The source fs.FS has to implement ReadLinkFS, otherwise CopyFS fails
+// at the first symlink.
+//
+// By default, like os.CopyFS, symlinks which are not ignored result in an
+// error which is fs.ErrInvalid. Their targets are never copied, as they may be
+// outside of the source fs.FS.
+func WithCopySymlinks() CopyOption {
+	return copyOptionFunc(func(c *copier) {
+		c.symlinks = true
+	})
+}
+
+// CopyFS copies all files of src, which are not ignored by n, into the directory dst,
+// similar to os.CopyFS of Go 1.23.
+// The ignore files of src have to be loaded into n before, e.g. using ForFS.
+//
+// Files are created with the permissions of the source file, but existing files
+// are never overwritten: CopyFS returns an error which is fs.ErrExist then.
+// Directories are created if they don't exist and get the permissions of the
+// source directory after all of their children were copied. Only the permissions
+// of dst itself are not changed.
+// Ignored files are skipped, ignored directories are not created at all.
+//
+// Only regular files, directories and, with WithCopySymlinks, symlinks can be
+// copied. Other files result in an error which is fs.ErrInvalid.
+func CopyFS(dst string, src fs.FS, n *NoGo, opts ...CopyOption) error {
+	c := &copier{dst: dst, src: src}
+	for _, opt := range opts {
+		opt.applyCopy(c)
+	}
+
+	return n.WalkDir(src, ".", c.copy, WithPostDir(c.chmodDir))
+}
+
+var errNoReadLink = errors.New("nogo: the fs.FS doesn't implement ReadLinkFS")
+
+type copier struct {
+	dst      string
+	src      fs.FS
+	symlinks bool
+}
+
+func (c *copier) copy(path string, d fs.DirEntry, err error) error {
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(c.dst, filepath.FromSlash(path))
+	switch {
+	case d.IsDir():
+		// The final permissions are set by chmodDir, as a read only
+		// directory can't be filled.
+		return os.MkdirAll(target, 0o700)
+	case d.Type()&fs.ModeSymlink != 0 && c.symlinks:
+		return c.copySymlink(path, target)
+	case d.Type().IsRegular():
+		return c.copyFile(path, target)
+	default:
+		return &fs.PathError{Op: "CopyFS", Path: path, Err: fs.ErrInvalid}
+	}
+}
+
+func (c *copier) copySymlink(path, target string) error {
+	linkFS, ok := c.src.(ReadLinkFS)
+	if !ok {
+		return &fs.PathError{Op: "readlink", Path: path, Err: errNoReadLink}
+	}
+
+	link, err := linkFS.ReadLink(path)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(link, target)
+}
+
+func (c *copier) copyFile(path, target string) error {
+	r, err := c.src.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	info, err := r.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return &fs.PathError{Op: "CopyFS", Path: path, Err: fs.ErrInvalid}
+	}
+
+	w, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return &fs.PathError{Op: "Copy", Path: target, Err: err}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	// The permissions of OpenFile are reduced by the umask.
+	return os.Chmod(target, info.Mode().Perm())
+}
+
+// chmodDir sets the permissions of a copied directory.
+// The dst itself is kept unchanged.
+func (c *copier) chmodDir(path string, d fs.DirEntry, _ DirStats) error {
+	if path == "." {
+		return nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	return os.Chmod(filepath.Join(c.dst, filepath.FromSlash(path)), info.Mode().Perm())
+}
@@ -0,0 +1,160 @@
+package nogo
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLinkDirFS adds ReadLinkFS to os.DirFS, which doesn't implement it
+// before Go 1.25.
+type readLinkDirFS struct {
+	fs.FS
+	dir string
+}
+
+func (f readLinkDirFS) ReadLink(name string) (string, error) {
+	return os.Readlink(filepath.Join(f.dir, filepath.FromSlash(name)))
+}
+
+func copiedFiles(t *testing.T, dir string) map[string]fs.FileMode {
+	got := make(map[string]fs.FileMode)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		rel, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		require.NoError(t, err)
+		got[filepath.ToSlash(rel)] = info.Mode()
+		return nil
+	})
+	require.NoError(t, err)
+	return got
+}
+
+func TestCopyFS(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	for _, f := range fsys {
+		f.Mode = 0o644
+	}
+	fsys["main.go"].Mode = 0o755
+	fsys["sub"] = &fstest.MapFile{Mode: fs.ModeDir | 0o750}
+	fsys["sub/deeper"] = &fstest.MapFile{Mode: fs.ModeDir | 0o755}
+	n := newWalkTestNoGo(t, fsys)
+
+	dst := t.TempDir()
+	require.NoError(t, CopyFS(dst, fsys, n))
+
+	assert.Equal(t, map[string]fs.FileMode{
+		".gitignore":         0o644,
+		"main.go":            0o755,
+		"sub":                fs.ModeDir | 0o750,
+		"sub/.gitignore":     0o644,
+		"sub/deeper":         fs.ModeDir | 0o755,
+		"sub/deeper/keep.md": 0o644,
+		"sub/public.txt":     0o644,
+	}, copiedFiles(t, dst))
+
+	data, err := os.ReadFile(filepath.Join(dst, "sub", "public.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "public", string(data))
+}
+
+func TestCopyFS_Exists(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	dst := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "main.go"), []byte("existing"), 0o644))
+
+	err := CopyFS(dst, fsys, n)
+	assert.ErrorIs(t, err, fs.ErrExist)
+
+	data, err := os.ReadFile(filepath.Join(dst, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(data))
+}
+
+func TestCopyFS_Invalid(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pipe": {Mode: fs.ModeNamedPipe},
+	}
+
+	err := CopyFS(t.TempDir(), fsys, New())
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestWithCopySymlinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("ignoredLink\n"), 0o644))
+	if err := os.Symlink("file.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+	require.NoError(t, os.Symlink("file.txt", filepath.Join(dir, "ignoredLink")))
+	fsys := readLinkDirFS{FS: os.DirFS(dir), dir: dir}
+
+	n, err := ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	t.Run("without option", func(t *testing.T) {
+		err := CopyFS(t.TempDir(), fsys, n)
+		assert.ErrorIs(t, err, fs.ErrInvalid)
+	})
+
+	t.Run("copy symlink", func(t *testing.T) {
+		dst := t.TempDir()
+		require.NoError(t, CopyFS(dst, fsys, n, WithCopySymlinks()))
+
+		got := copiedFiles(t, dst)
+		assert.Equal(t, fs.ModeSymlink, got["link"]&fs.ModeType)
+		assert.NotContains(t, got, "ignoredLink")
+
+		target, err := os.Readlink(filepath.Join(dst, "link"))
+		require.NoError(t, err)
+		assert.Equal(t, "file.txt", target)
+	})
+
+	t.Run("no ReadLinkFS", func(t *testing.T) {
+		err := CopyFS(t.TempDir(), struct{ fs.FS }{fsys.FS}, n, WithCopySymlinks())
+		assert.ErrorIs(t, err, errNoReadLink)
+	})
+}
+
+func TestCopyFS_EscapingSymlink(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o600))
+
+	dir := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+	fsys := readLinkDirFS{FS: os.DirFS(dir), dir: dir}
+
+	t.Run("without option", func(t *testing.T) {
+		dst := t.TempDir()
+		err := CopyFS(dst, fsys, New())
+		assert.ErrorIs(t, err, fs.ErrInvalid)
+		assert.Empty(t, copiedFiles(t, dst), "the target must not be copied")
+	})
+
+	t.Run("copy symlink", func(t *testing.T) {
+		dst := t.TempDir()
+		require.NoError(t, CopyFS(dst, fsys, New(), WithCopySymlinks()))
+
+		got := copiedFiles(t, dst)
+		assert.Equal(t, fs.ModeSymlink, got["link"]&fs.ModeType)
+		target, err := os.Readlink(filepath.Join(dst, "link"))
+		require.NoError(t, err)
+		assert.Equal(t, outside, target)
+	})
+}
@@ -0,0 +1,284 @@
+package nogo
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ./internal/gendoc
+
+//go:embed corpus/behavior.txt
+var behaviorCorpus []byte
+
+//go:embed corpus/regressions.txt
+var regressionCorpus []byte
+
+// The corpora are parsed once as they are embedded and therefore can't change.
+var (
+	corpus      = mustParseCorpus(behaviorCorpus)
+	regressions = mustParseCorpus(regressionCorpus)
+)
+
+// CorpusCase is a single expected decision of the behavior corpus.
+type CorpusCase struct {
+	// Pattern is a single line of an ignore file in the root folder.
+	Pattern string
+
+	// Path is the checked path, relative to the root folder.
+	Path  string
+	IsDir bool
+
+	// Ignored is the decision git makes for the Path.
+	Ignored bool
+}
+
+// Check compiles the Pattern and returns if nogo ignores the Path.
+// The options are passed to New.
+func (c CorpusCase) Check(opts ...Option) (bool, error) {
+	rules, err := CompileAll("", []byte(c.Pattern))
+	if err != nil {
+		return false, err
+	}
+
+	n := New(opts...)
+	n.AddRules(rules...)
+	return n.Match(c.Path, c.IsDir), nil
+}
+
+// Corpus returns all cases of the behavior corpus which is embedded into nogo.
+// All decisions in it are the same as git makes.
+//
+// It can be used to check other implementations for conformance with nogo.
+func Corpus() []CorpusCase {
+	cases := make([]CorpusCase, len(corpus))
+	copy(cases, corpus)
+	return cases
+}
+
+// Regressions returns all cases of the regression corpus which is embedded into nogo.
+// It contains minimized cases of bugs which were fixed (e.g. found by fuzzing),
+// so that they can't happen again. All decisions in it are the same as git makes.
+func Regressions() []CorpusCase {
+	cases := make([]CorpusCase, len(regressions))
+	copy(cases, regressions)
+	return cases
+}
+
+// ParseCorpus reads cases in the format of the behavior corpus.
+//
+// Each block starts with a line "pattern <pattern>" followed by indented
+// lines "<path> <decision>". Paths ending with a '/' are directories.
+// The decision is either "ignored" or "included".
+// Empty lines and lines starting with '#' are skipped.
+//
+// Patterns and paths starting with '"' are Go string literals, e.g.
+// `pattern "   "` for a pattern of three spaces. WriteCorpus uses them for
+// whitespace which would be lost otherwise and for control characters.
+func ParseCorpus(r io.Reader) ([]CorpusCase, error) {
+	var cases []CorpusCase
+	var pattern *string
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "pattern ") {
+			p := strings.TrimPrefix(line, "pattern ")
+			if strings.HasPrefix(p, `"`) {
+				unquoted, err := strconv.Unquote(p)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid quoted pattern %s", lineNumber, p)
+				}
+				p = unquoted
+			}
+			pattern = &p
+			continue
+		}
+
+		if pattern == nil {
+			return nil, fmt.Errorf("line %d: path without pattern", lineNumber)
+		}
+
+		path, decision, err := parseCorpusPath(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		c := CorpusCase{
+			Pattern: *pattern,
+			Path:    strings.TrimSuffix(path, "/"),
+			IsDir:   strings.HasSuffix(path, "/"),
+		}
+
+		switch decision {
+		case "ignored":
+			c.Ignored = true
+		case "included":
+			c.Ignored = false
+		default:
+			return nil, fmt.Errorf("line %d: unknown decision %q", lineNumber, decision)
+		}
+
+		cases = append(cases, c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// parseCorpusPath splits an indented line into the path and the decision.
+func parseCorpusPath(line string) (path string, decision string, err error) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, `"`) {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return "", "", fmt.Errorf("expected '<path> <decision>' but got %q", line)
+		}
+		return fields[0], fields[1], nil
+	}
+
+	quoted, err := strconv.QuotedPrefix(trimmed)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid quoted path in %q", line)
+	}
+	path, _ = strconv.Unquote(quoted)
+	fields := strings.Fields(trimmed[len(quoted):])
+	if len(fields) != 1 {
+		return "", "", fmt.Errorf("expected '<path> <decision>' but got %q", line)
+	}
+	return path, fields[0], nil
+}
+
+// quoteCorpus quotes the pattern or path if ParseCorpus can't read it as it is.
+// Paths must not contain any whitespace, patterns only at the start and end.
+func quoteCorpus(s string, isPath bool) string {
+	trimmed := strings.TrimSpace(s)
+	quote := s == "" || trimmed != s || strings.HasPrefix(s, `"`) ||
+		isPath && strings.ContainsAny(s, " \t\v\f")
+	for _, c := range s {
+		if !strconv.IsPrint(c) {
+			quote = true
+		}
+	}
+
+	if quote {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// WriteCorpus writes the cases in the format read by ParseCorpus.
+// Consecutive cases with the same pattern are written into the same block.
+func WriteCorpus(w io.Writer, cases []CorpusCase) error {
+	for i, c := range cases {
+		if i == 0 || cases[i-1].Pattern != c.Pattern {
+			if _, err := fmt.Fprintf(w, "\npattern %s\n", quoteCorpus(c.Pattern, false)); err != nil {
+				return err
+			}
+		}
+
+		path := c.Path
+		if c.IsDir {
+			path += "/"
+		}
+
+		decision := "included"
+		if c.Ignored {
+			decision = "ignored"
+		}
+
+		if _, err := fmt.Fprintf(w, "    %-15s %s\n", quoteCorpus(path, true), decision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendCorpusFile appends the cases to the corpus file.
+// Use it to add new cases to corpus/regressions.txt, e.g. from a fuzz test.
+func AppendCorpusFile(filename string, cases ...CorpusCase) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteCorpus(file, cases); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func mustParseCorpus(data []byte) []CorpusCase {
+	cases, err := ParseCorpus(bytes.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return cases
+}
+
+// BehaviorMatrix contains the decisions of nogo for each pattern
+// and each path of a corpus.
+type BehaviorMatrix struct {
+	// Patterns contains all distinct patterns in the order of the corpus.
+	Patterns []string
+
+	// Paths contains all distinct paths in the order of the corpus.
+	// Directories end with a '/'.
+	Paths []string
+
+	// Ignored contains the decision for each pattern and path:
+	// Ignored[pattern][path].
+	Ignored [][]bool
+}
+
+// NewBehaviorMatrix checks each pattern of the cases against each path of the cases.
+// Use it with Corpus() to get the full behavior of nogo for the embedded corpus.
+func NewBehaviorMatrix(cases []CorpusCase) (BehaviorMatrix, error) {
+	var matrix BehaviorMatrix
+	var paths []CorpusCase
+	seenPatterns := make(map[string]bool)
+	seenPaths := make(map[string]bool)
+
+	for _, c := range cases {
+		if !seenPatterns[c.Pattern] {
+			seenPatterns[c.Pattern] = true
+			matrix.Patterns = append(matrix.Patterns, c.Pattern)
+		}
+
+		path := c.Path
+		if c.IsDir {
+			path += "/"
+		}
+		if !seenPaths[path] {
+			seenPaths[path] = true
+			matrix.Paths = append(matrix.Paths, path)
+			paths = append(paths, c)
+		}
+	}
+
+	for _, pattern := range matrix.Patterns {
+		decisions := make([]bool, len(paths))
+		for i, path := range paths {
+			ignored, err := CorpusCase{Pattern: pattern, Path: path.Path, IsDir: path.IsDir}.Check()
+			if err != nil {
+				return BehaviorMatrix{}, err
+			}
+			decisions[i] = ignored
+		}
+		matrix.Ignored = append(matrix.Ignored, decisions)
+	}
+
+	return matrix, nil
+}
@@ -0,0 +1,178 @@
+package nogo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpus(t *testing.T) {
+	cases := Corpus()
+	require.NotEmpty(t, cases)
+
+	for _, c := range cases {
+		t.Run(c.Pattern+"|"+c.Path, func(t *testing.T) {
+			got, err := c.Check()
+			require.NoError(t, err)
+			assert.Equal(t, c.Ignored, got)
+		})
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	cases := Regressions()
+	require.NotEmpty(t, cases)
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		for _, c := range cases {
+			t.Run(fmt.Sprintf("%v|%v|%v", backend, c.Pattern, c.Path), func(t *testing.T) {
+				got, err := c.Check(WithBackend(backend))
+				require.NoError(t, err)
+				assert.Equal(t, c.Ignored, got)
+			})
+		}
+	}
+}
+
+func TestParseCorpus(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []CorpusCase
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "valid corpus",
+			data: "# comment\n\npattern *.go\n  a.go ignored\n  a/   included\r\npattern /b\n  b ignored\n",
+			want: []CorpusCase{
+				{Pattern: "*.go", Path: "a.go", Ignored: true},
+				{Pattern: "*.go", Path: "a", IsDir: true, Ignored: false},
+				{Pattern: "/b", Path: "b", Ignored: true},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "quoted",
+			data: "pattern \"   \"\n  a included\npattern \"a \\\\ \"\n  \"a  \" ignored\n  \"b c/\"\tincluded\n",
+			want: []CorpusCase{
+				{Pattern: "   ", Path: "a"},
+				{Pattern: `a \ `, Path: "a  ", Ignored: true},
+				{Pattern: `a \ `, Path: "b c", IsDir: true},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid quoted pattern",
+			data:    "pattern \"a\n  a included\n",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid quoted path",
+			data:    "pattern a\n  \"a included\n",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "path without pattern",
+			data:    "  a.go ignored\n",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unknown decision",
+			data:    "pattern *.go\n  a.go maybe\n",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "missing decision",
+			data:    "pattern *.go\n  a.go\n",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCorpus(strings.NewReader(tt.data))
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteCorpus(t *testing.T) {
+	cases := []CorpusCase{
+		{Pattern: "*.go", Path: "a.go", Ignored: true},
+		{Pattern: "*.go", Path: "a", IsDir: true},
+		{Pattern: "/b", Path: "b", Ignored: true},
+		{Pattern: "*.go", Path: "b.go", Ignored: true},
+	}
+
+	var b strings.Builder
+	require.NoError(t, WriteCorpus(&b, cases))
+	assert.Equal(t, "\npattern *.go\n    a.go            ignored\n    a/              included\n\npattern /b\n    b               ignored\n\npattern *.go\n    b.go            ignored\n", b.String())
+
+	got, err := ParseCorpus(strings.NewReader(b.String()))
+	require.NoError(t, err)
+	assert.Equal(t, cases, got)
+}
+
+func TestWriteCorpus_Quoted(t *testing.T) {
+	cases := []CorpusCase{
+		{Pattern: "   ", Path: "a"},
+		{Pattern: `a \ `, Path: "a  ", Ignored: true},
+		{Pattern: "a b", Path: "a b", IsDir: true, Ignored: true},
+		{Pattern: "\x01", Path: `"a`},
+		{Pattern: `"a"`, Path: "ä"},
+	}
+
+	var b strings.Builder
+	require.NoError(t, WriteCorpus(&b, cases))
+	assert.Equal(t, "\npattern \"   \"\n    a               included\n\npattern \"a \\\\ \"\n    \"a  \"           ignored\n\npattern a b\n    \"a b/\"          ignored\n\npattern \"\\x01\"\n    \"\\\"a\"           included\n\npattern \"\\\"a\\\"\"\n    ä               included\n", b.String())
+
+	got, err := ParseCorpus(strings.NewReader(b.String()))
+	require.NoError(t, err)
+	assert.Equal(t, cases, got)
+}
+
+func TestAppendCorpusFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "regressions.txt")
+	require.NoError(t, os.WriteFile(filename, []byte("# A comment.\n"), 0644))
+
+	first := CorpusCase{Pattern: "*.go", Path: "a.go", Ignored: true}
+	second := CorpusCase{Pattern: "b/", Path: "b", IsDir: true, Ignored: true}
+	require.NoError(t, AppendCorpusFile(filename, first))
+	require.NoError(t, AppendCorpusFile(filename, second))
+
+	file, err := os.Open(filename)
+	require.NoError(t, err)
+	defer file.Close()
+
+	got, err := ParseCorpus(file)
+	require.NoError(t, err)
+	assert.Equal(t, []CorpusCase{first, second}, got)
+}
+
+func TestNewBehaviorMatrix(t *testing.T) {
+	matrix, err := NewBehaviorMatrix([]CorpusCase{
+		{Pattern: "*.go", Path: "a.go", Ignored: true},
+		{Pattern: "*.go", Path: "b", IsDir: true},
+		{Pattern: "b/", Path: "b", IsDir: true, Ignored: true},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, BehaviorMatrix{
+		Patterns: []string{"*.go", "b/"},
+		Paths:    []string{"a.go", "b/"},
+		Ignored: [][]bool{
+			{true, false},
+			{false, true},
+		},
+	}, matrix)
+
+	_, err = NewBehaviorMatrix([]CorpusCase{{Pattern: "[a", Path: "a"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package nogo
+
+import "strings"
+
+var osJunkPatterns = []string{
+	// macOS
+	".DS_Store",
+	".AppleDouble",
+	".LSOverride",
+	"._*",
+	".Spotlight-V100",
+	".Trashes",
+	".fseventsd",
+	".TemporaryItems",
+	// Windows
+	"Thumbs.db",
+	"Thumbs.db:encryptable",
+	"ehthumbs.db",
+	"ehthumbs_vista.db",
+	"[Dd]esktop.ini",
+	"$RECYCLE.BIN/",
+	// Linux
+	".directory",
+	".Trash-*",
+	".nfs*",
+}
+
+var editorPatterns = []string{
+	// JetBrains
+	".idea/",
+	"*.iml",
+	// Visual Studio and Visual Studio Code
+	".vscode/",
+	".vs/",
+	".history/",
+	// Vim
+	"*.swp",
+	"*.swo",
+	"Session.vim",
+	// Emacs
+	"*~",
+	`\#*#`,
+	".#*",
+	// Eclipse
+	".project",
+	".classpath",
+	".settings/",
+	// Sublime Text
+	"*.sublime-workspace",
+}
+
+var buildArtifactPatterns = []string{
+	// Object files, libraries and executables
+	"*.o",
+	"*.obj",
+	"*.a",
+	"*.lib",
+	"*.so",
+	"*.dylib",
+	"*.dll",
+	"*.exe",
+	// Go test binaries
+	"*.test",
+	// Java
+	"*.class",
+	".gradle/",
+	"target/",
+	// Python
+	"*.py[co]",
+	"__pycache__/",
+	// JavaScript
+	"node_modules/",
+	// Output folders
+	"dist/",
+	"build/",
+}
+
+var (
+	// OSJunkRules ignore the files which operating systems create on their own,
+	// e.g. ".DS_Store" of macOS or "Thumbs.db" of Windows.
+	OSJunkRules = MustCompileAll("", defaultPatterns(osJunkPatterns))
+
+	// EditorRules ignore the settings, swap and backup files of common editors
+	// and IDEs, e.g. ".idea/", ".vscode/" or "*.swp".
+	EditorRules = MustCompileAll("", defaultPatterns(editorPatterns))
+
+	// BuildArtifactRules ignore compiled files (e.g. "*.o" or "*.class") and the
+	// output and dependency folders of common build tools (e.g. "node_modules/",
+	// "target/" or "build/"). Don't use them if such folders contain sources.
+	BuildArtifactRules = MustCompileAll("", defaultPatterns(buildArtifactPatterns))
+)
+
+func defaultPatterns(patterns []string) []byte {
+	return []byte(strings.Join(patterns, "\n"))
+}
@@ -0,0 +1,45 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{name: "os", rules: OSJunkRules, path: "a/.DS_Store", want: true},
+		{name: "os", rules: OSJunkRules, path: "._main.go", want: true},
+		{name: "os", rules: OSJunkRules, path: "pics/Thumbs.db", want: true},
+		{name: "os", rules: OSJunkRules, path: "desktop.ini", want: true},
+		{name: "os", rules: OSJunkRules, path: "$RECYCLE.BIN", isDir: true, want: true},
+		{name: "os", rules: OSJunkRules, path: "main.go", want: false},
+		{name: "editor", rules: EditorRules, path: ".idea/workspace.xml", want: true},
+		{name: "editor", rules: EditorRules, path: "sub/.vscode", isDir: true, want: true},
+		{name: "editor", rules: EditorRules, path: ".vscode", want: false},
+		{name: "editor", rules: EditorRules, path: ".main.go.swp", want: true},
+		{name: "editor", rules: EditorRules, path: "main.go~", want: true},
+		{name: "editor", rules: EditorRules, path: "#main.go#", want: true},
+		{name: "editor", rules: EditorRules, path: "main.go", want: false},
+		{name: "build", rules: BuildArtifactRules, path: "main.o", want: true},
+		{name: "build", rules: BuildArtifactRules, path: "pkg/pkg.test", want: true},
+		{name: "build", rules: BuildArtifactRules, path: "a/b.pyc", want: true},
+		{name: "build", rules: BuildArtifactRules, path: "web/node_modules/x/index.js", want: true},
+		{name: "build", rules: BuildArtifactRules, path: "target", isDir: true, want: true},
+		{name: "build", rules: BuildArtifactRules, path: "main.go", want: false},
+		{name: "build", rules: BuildArtifactRules, path: "a/b.py", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.path, func(t *testing.T) {
+			n := New(WithRules(tt.rules...))
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package nogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect defines the syntax of ignore files.
+type Dialect int
+
+const (
+	// DialectGitignore is the syntax of .gitignore files. It is the default.
+	DialectGitignore Dialect = iota
+
+	// DialectBraces extends the gitignore syntax by brace expansion and
+	// extended globs, as used by many tools of the JavaScript ecosystem
+	// (e.g. "{src,lib}/**/*.js" or "*.@(js|ts)"). See ExpandBraces.
+	//
+	// Each line is expanded into several rules, which are then handled the
+	// same way as in .gitignore files.
+	DialectBraces
+
+	// DialectHelmignore is the syntax of .helmignore files of Helm charts.
+	// See helmignoreRules for the differences to gitignore.
+	DialectHelmignore
+
+	// DialectGcloudignore is the syntax of .gcloudignore files of the Google
+	// Cloud SDK. It is the same as gitignore, but lines like "#!include:.gitignore"
+	// include the rules of another file in the same folder.
+	//
+	// Includes are only resolved by AddFile (and the functions using it) as
+	// they need access to the file system. Otherwise they are just comments.
+	DialectGcloudignore
+
+	// DialectHgignore is the syntax of .hgignore files of Mercurial.
+	// It supports the "syntax: regexp" and "syntax: glob" sections and the
+	// "re:", "glob:" and "rootglob:" prefixes. The default syntax is regexp.
+	//
+	// The patterns are converted to regexps the same way as Mercurial does it,
+	// so the rules only contain the Regexp but no Pattern.
+	// Regexps use the syntax of the Go regexp package which doesn't support
+	// everything of Python, e.g. no look-ahead.
+	DialectHgignore
+
+	// DialectSparseCone is the syntax of the sparse-checkout file of git in
+	// cone mode (see "git sparse-checkout"), which lists the directories of
+	// the cone. Like for all dialects, matched paths are ignored, so the rules
+	// match everything outside of the cone. Use SparseConePatterns to create
+	// the lines for a list of directories.
+	//
+	// Only the patterns written by git in cone mode are valid, e.g. "/*",
+	// "!/*/", "/dir/" and "!/dir/*/". Everything else results in an error.
+	DialectSparseCone
+
+	// DialectExportIgnore reads .gitattributes files, but only uses the
+	// export-ignore attribute. Paths which have it set are ignored, e.g. to
+	// exclude them from archives like "git archive" does it. See ForArchive.
+	//
+	// Unsetting the attribute ("-export-ignore"), making it unspecified
+	// ("!export-ignore") or setting a value results in a negated rule.
+	// All other attributes, macro definitions and negative patterns are
+	// skipped, as git does it for the latter.
+	DialectExportIgnore
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectGitignore:
+		return "gitignore"
+	case DialectBraces:
+		return "braces"
+	case DialectHelmignore:
+		return "helmignore"
+	case DialectGcloudignore:
+		return "gcloudignore"
+	case DialectHgignore:
+		return "hgignore"
+	case DialectSparseCone:
+		return "sparse-cone"
+	case DialectExportIgnore:
+		return "export-ignore"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText returns the name of the dialect, as returned by String.
+// Together with UnmarshalText, this allows using dialects in config files.
+func (d Dialect) MarshalText() ([]byte, error) {
+	name := d.String()
+	if name == "unknown" {
+		return nil, fmt.Errorf("unknown dialect %d", int(d))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText parses the name of a dialect, as returned by String.
+// An empty name is DialectGitignore.
+func (d *Dialect) UnmarshalText(text []byte) error {
+	name := string(text)
+	if name == "" {
+		*d = DialectGitignore
+		return nil
+	}
+	for dialect := DialectGitignore; dialect.String() != "unknown"; dialect++ {
+		if dialect.String() == name {
+			*d = dialect
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown dialect %q", name)
+}
+
+// WithDialect sets the syntax used to load ignore files and patterns,
+// e.g. by AddFile, AddFromFS and AddPatterns.
+// Rules added using AddRules are not affected.
+func WithDialect(dialect Dialect) Option {
+	return optionFunc(func(n *NoGo) {
+		n.dialect = dialect
+	})
+}
+
+// CompileAll does the same as the function CompileAll using the syntax of the dialect.
+// A UTF-8 byte order mark at the start of the data is removed.
+func (d Dialect) CompileAll(prefix string, data []byte) ([]Rule, error) {
+	return d.compileLines(prefix, splitLines(data))
+}
+
+// compileLines compiles the lines of an ignore file.
+func (d Dialect) compileLines(prefix string, lines []string) ([]Rule, error) {
+	switch d {
+	case DialectHelmignore:
+		return helmignoreRules(prefix, lines)
+	case DialectHgignore:
+		return hgignoreRules(prefix, lines)
+	case DialectSparseCone:
+		return sparseConeRules(prefix, lines)
+	}
+
+	rules := make([]Rule, 0)
+	for i, line := range lines {
+		lineRules, err := d.compile(prefix, line)
+		if err != nil {
+			return nil, patternErrorAt(err, "", i+1)
+		}
+		rules = append(rules, lineRules...)
+	}
+	return rules, nil
+}
+
+// compile a single line. It returns no rules if the line doesn't contain any pattern.
+func (d Dialect) compile(prefix string, line string) ([]Rule, error) {
+	if d == DialectSparseCone {
+		return compileSparseCone(prefix, line)
+	}
+	if d == DialectExportIgnore {
+		return compileExportIgnore(prefix, line)
+	}
+	if d != DialectBraces {
+		skip, rule, err := Compile(prefix, line)
+		if err != nil || skip {
+			return nil, err
+		}
+		return []Rule{rule}, nil
+	}
+
+	pattern, negate, skip := cleanPattern(line)
+	if skip {
+		return nil, nil
+	}
+
+	expanded, err := ExpandBraces(pattern)
+	if err != nil {
+		return nil, &PatternError{Pattern: line, Cause: err}
+	}
+
+	rules := make([]Rule, 0, len(expanded))
+	for _, pattern := range expanded {
+		skip, rule, err := Compile(prefix, patternLine(pattern, negate))
+		if err != nil {
+			return nil, err
+		}
+		if !skip {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// patternLine converts a pattern returned by cleanPattern back to a line,
+// which results in the same pattern.
+func patternLine(pattern string, negate bool) string {
+	if strings.HasPrefix(pattern, "#") {
+		pattern = `\` + pattern
+	}
+	if trimTrailingSpaces(pattern) != pattern {
+		pattern = strings.TrimSuffix(pattern, " ") + `\ `
+	}
+	if negate {
+		pattern = "!" + pattern
+	}
+	return pattern
+}
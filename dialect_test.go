@@ -0,0 +1,102 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialect_CompileAll(t *testing.T) {
+	t.Run("gitignore", func(t *testing.T) {
+		rules, err := DialectGitignore.CompileAll("", []byte("{a,b}\n*.js"))
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, "{a,b}", rules[0].Pattern)
+	})
+
+	t.Run("braces", func(t *testing.T) {
+		rules, err := DialectBraces.CompileAll("sub", []byte("# {a,b}\n!{a,b}.js\n\\#{c,d}\n{e,f}\\ \n\n*.txt"))
+		require.NoError(t, err)
+
+		var patterns []string
+		for _, rule := range rules {
+			assert.Equal(t, "sub", rule.Prefix)
+			patterns = append(patterns, rule.Pattern)
+		}
+		assert.Equal(t, []string{"!a.js", "!b.js", `\#c`, `\#d`, `e\ `, `f\ `, "*.txt"}, patterns)
+		assert.True(t, rules[0].Negate)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DialectBraces.CompileAll("", []byte("a\n*(b|c)"))
+		assert.Error(t, err)
+	})
+}
+
+func TestWithDialect(t *testing.T) {
+	fsys := fstest.MapFS{
+		".eslintignore":     {Data: []byte("{dist,coverage}/\n*.min.@(js|css)\n!keep.min.js")},
+		"sub/.eslintignore": {Data: []byte("/{a,b}.js")},
+	}
+
+	tests := map[string]bool{
+		"dist":           true,
+		"coverage":       true,
+		"src":            false,
+		"a.min.js":       true,
+		"a.min.css":      true,
+		"a.min.ts":       false,
+		"keep.min.js":    false,
+		"sub/a.js":       true,
+		"sub/b.js":       true,
+		"sub/c.js":       false,
+		"sub/other/a.js": false,
+	}
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		n := New(WithDialect(DialectBraces), WithBackend(backend))
+		require.NoError(t, n.AddFromFS(fsys, ".eslintignore"))
+		for path, want := range tests {
+			assert.Equal(t, want, n.Match(path, true), "%v: %s", backend, path)
+		}
+	}
+
+	n := New(WithDialect(DialectBraces))
+	require.NoError(t, n.AddPatterns("", []string{"{x,y}"}, WithAnchoring(AnchorPrefix)))
+	assert.True(t, n.Match("x", false))
+	assert.True(t, n.Match("y", false))
+	assert.False(t, n.Match("sub/x", false))
+}
+
+func TestDialect_String(t *testing.T) {
+	assert.Equal(t, "gitignore", DialectGitignore.String())
+	assert.Equal(t, "braces", DialectBraces.String())
+	assert.Equal(t, "helmignore", DialectHelmignore.String())
+	assert.Equal(t, "gcloudignore", DialectGcloudignore.String())
+	assert.Equal(t, "hgignore", DialectHgignore.String())
+	assert.Equal(t, "sparse-cone", DialectSparseCone.String())
+	assert.Equal(t, "export-ignore", DialectExportIgnore.String())
+	assert.Equal(t, "unknown", Dialect(-1).String())
+}
+
+func TestDialect_MarshalText(t *testing.T) {
+	for dialect := DialectGitignore; dialect <= DialectExportIgnore; dialect++ {
+		text, err := dialect.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, dialect.String(), string(text))
+
+		var got Dialect
+		require.NoError(t, got.UnmarshalText(text))
+		assert.Equal(t, dialect, got)
+	}
+
+	_, err := Dialect(-1).MarshalText()
+	assert.Error(t, err)
+
+	d := DialectHelmignore
+	require.NoError(t, d.UnmarshalText(nil))
+	assert.Equal(t, DialectGitignore, d)
+	assert.Error(t, d.UnmarshalText([]byte("dockerignore")))
+}
@@ -0,0 +1,123 @@
+package nogo
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// DirHint tells MatchHint whether a path is a directory.
+type DirHint int
+
+const (
+	// DirUnknown is used if it is not known whether the path is a directory,
+	// e.g. for object storage keys or archive entries without directory markers.
+	DirUnknown DirHint = iota
+	// DirNo means that the path is no directory.
+	DirNo
+	// DirYes means that the path is a directory.
+	DirYes
+)
+
+func (h DirHint) String() string {
+	switch h {
+	case DirNo:
+		return "no"
+	case DirYes:
+		return "yes"
+	default:
+		return "unknown"
+	}
+}
+
+// WithStatFS sets the file system which is used by MatchHint to find out
+// whether a path with DirUnknown is a directory.
+func WithStatFS(fsys fs.FS) Option {
+	return optionFunc(func(n *NoGo) {
+		n.statFS = fsys
+	})
+}
+
+// MatchHint does the same as MatchBecause but the caller doesn't have to know
+// whether the path is a directory. A path ending with a '/' is always a directory
+// (or a `\` if WithWindowsPaths is used).
+//
+// Paths with DirUnknown are resolved this way:
+//   - If a file system is set using WithStatFS and the path exists in it,
+//     it is checked whether the path is a directory.
+//   - Otherwise the path is only ignored if it would be ignored both as file
+//     and as directory. So rules ending with a '/' never ignore such a path,
+//     which is the conservative choice as nothing gets dropped by accident.
+func (n *NoGo) MatchHint(path string, hint DirHint) (match bool, because Result) {
+	if n.windowsPaths {
+		path = strings.ReplaceAll(path, `\`, "/")
+	}
+	if strings.HasSuffix(path, "/") {
+		path = strings.TrimSuffix(path, "/")
+		hint = DirYes
+	}
+
+	if hint == DirUnknown && n.statFS != nil {
+		if info, err := fs.Stat(n.statFS, path); err == nil {
+			hint = DirNo
+			if info.IsDir() {
+				hint = DirYes
+			}
+		}
+	}
+
+	switch hint {
+	case DirYes:
+		return n.MatchBecause(path, true)
+	case DirNo:
+		return n.MatchBecause(path, false)
+	}
+
+	match, because = n.MatchBecause(path, false)
+	if !match {
+		return false, because
+	}
+	return n.MatchBecause(path, true)
+}
+
+// MatchFS does the same as MatchBecause but gets whether the path is a directory
+// from the file system, so the caller doesn't have to stat it.
+// An error of Stat (e.g. fs.ErrNotExist) is returned as it is.
+//
+// As git never tracks the .git directory, it and everything inside of it is
+// always ignored, even if DotGitRule is not used. The Result contains
+// DotGitRule in that case.
+func (n *NoGo) MatchFS(fsys fs.StatFS, path string) (bool, Result, error) {
+	fsPath, err := n.fsPath(path)
+	if err != nil {
+		return false, Result{}, err
+	}
+	fsPath = cleanPath(fsPath)
+
+	info, err := fsys.Stat(fsPath)
+	if err != nil {
+		return false, Result{}, err
+	}
+
+	if i := dotGitIndex(fsPath); i >= 0 {
+		return true, Result{
+			Rule:        DotGitRule,
+			Found:       true,
+			ParentMatch: i+len(".git") < len(fsPath),
+		}, nil
+	}
+
+	match, because := n.MatchBecause(fsPath, info.IsDir())
+	return match, because, nil
+}
+
+// dotGitIndex returns the index of the first ".git" element of the path or -1.
+func dotGitIndex(path string) int {
+	for start := 0; start < len(path); {
+		end := segmentEnd(path, start)
+		if path[start:end] == ".git" {
+			return start
+		}
+		start = end + 1
+	}
+	return -1
+}
@@ -0,0 +1,112 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_MatchHint(t *testing.T) {
+	rules := MustCompileAll("", []byte("build/\n*.log\n/keep\n!keep/"))
+
+	tests := []struct {
+		name string
+		path string
+		hint DirHint
+		want bool
+	}{
+		{name: "dir", path: "build", hint: DirYes, want: true},
+		{name: "file", path: "build", hint: DirNo, want: false},
+		{name: "unknown only dir rule", path: "build", hint: DirUnknown, want: false},
+		{name: "unknown both", path: "a.log", hint: DirUnknown, want: true},
+		{name: "unknown none", path: "a.txt", hint: DirUnknown, want: false},
+		{name: "unknown only file", path: "keep", hint: DirUnknown, want: false},
+		{name: "trailing slash", path: "build/", hint: DirUnknown, want: true},
+		{name: "trailing slash overrides", path: "build/", hint: DirNo, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New()
+			n.AddRules(rules...)
+			match, _ := n.MatchHint(tt.path, tt.hint)
+			assert.Equal(t, tt.want, match)
+		})
+	}
+
+	t.Run("stat fs", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"build/out": {},
+			"keep":      {},
+			"src/build": {},
+		}
+		n := New(WithStatFS(fsys))
+		n.AddRules(rules...)
+
+		for path, want := range map[string]bool{
+			"build":     true,
+			"src/build": false,
+			"keep":      true,
+			// Doesn't exist, so it is resolved conservatively.
+			"other/build": false,
+		} {
+			match, _ := n.MatchHint(path, DirUnknown)
+			assert.Equal(t, want, match, path)
+		}
+	})
+}
+
+func TestDirHint_String(t *testing.T) {
+	assert.Equal(t, "unknown", DirUnknown.String())
+	assert.Equal(t, "no", DirNo.String())
+	assert.Equal(t, "yes", DirYes.String())
+}
+
+func TestNoGo_MatchFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"build/out":    {},
+		"src/build":    {},
+		"a.log":        {},
+		".git/HEAD":    {},
+		"sub/.git":     {},
+		"sub/.gitkeep": {},
+	}
+	n := New()
+	n.AddRules(MustCompileAll("", []byte("build/\n*.log"))...)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: ".", want: false},
+		{path: "build", want: true},
+		{path: "build/out", want: true},
+		{path: "src/build", want: false},
+		{path: "a.log", want: true},
+		{path: ".git", want: true},
+		{path: ".git/HEAD", want: true},
+		{path: "sub/.git", want: true},
+		{path: "sub/.gitkeep", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because, err := n.MatchFS(fsys, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, match, because.Resolve(false) || because.Resolve(true))
+		})
+	}
+
+	t.Run("dot git result", func(t *testing.T) {
+		_, because, err := n.MatchFS(fsys, ".git/HEAD")
+		require.NoError(t, err)
+		assert.Equal(t, Result{Rule: DotGitRule, Found: true, ParentMatch: true}, because)
+	})
+
+	t.Run("not existing", func(t *testing.T) {
+		_, _, err := n.MatchFS(fsys, "missing")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
@@ -0,0 +1,196 @@
+package nogo
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// Usage is the amount and the total size of files.
+type Usage struct {
+	Files int
+	Bytes int64
+}
+
+func (u *Usage) add(other Usage) {
+	u.Files += other.Files
+	u.Bytes += other.Bytes
+}
+
+// DirUsage contains the usage of all files inside of a directory,
+// including the files of all subdirectories.
+type DirUsage struct {
+	Path string
+
+	// Included is the usage of all files which are not ignored.
+	Included Usage
+	// Ignored is the usage of all ignored files, including all files
+	// inside of ignored directories.
+	Ignored Usage
+
+	// Skipped is true if the directory itself is ignored.
+	// All of its files are counted as Ignored then.
+	Skipped bool
+}
+
+// Report is the result of Du.
+type Report struct {
+	// Root is the walked root.
+	Root string
+
+	// Dirs contains all directories in lexical order, including the root.
+	// Directories inside of ignored directories are not listed separately.
+	Dirs []DirUsage
+}
+
+// Total returns the usage of the root, which contains all files.
+func (r Report) Total() DirUsage {
+	total, _ := r.Dir(r.Root)
+	return total
+}
+
+// Dir returns the usage of the directory with the path.
+func (r Report) Dir(path string) (DirUsage, bool) {
+	i := sort.Search(len(r.Dirs), func(i int) bool {
+		return r.Dirs[i].Path >= path
+	})
+	if i < len(r.Dirs) && r.Dirs[i].Path == path {
+		return r.Dirs[i], true
+	}
+	return DirUsage{}, false
+}
+
+// Du walks the file tree using a new NoGo instance which lazily loads all
+// .gitignore files inside of the root, and counts the files and bytes which
+// are ignored and not ignored per directory.
+// See NoGo.Du for details.
+func Du(fsys fs.FS, root string, opts ...WalkOption) (Report, error) {
+	opts = append([]WalkOption{WithIgnoreFile(".gitignore")}, opts...)
+	return New().Du(fsys, root, opts...)
+}
+
+// Du walks the file tree the same way as WalkDir and counts the files and
+// bytes which are ignored and not ignored per directory, e.g. to find out
+// what a build context would contain or to check in CI that big directories
+// are ignored.
+//
+// In contrast to WalkDir, ignored directories are read, too, as their files
+// are counted as Ignored. Directories themselves are not counted as files.
+// Errors while reading the file tree stop Du.
+func (n *NoGo) Du(fsys fs.FS, root string, opts ...WalkOption) (Report, error) {
+	d := &du{
+		fsys: fsys,
+		root: root,
+		dirs: make(map[string]*DirUsage),
+	}
+
+	opts = append(opts, WithPostDir(d.postDir), walkOptionFunc(func(w *walker) {
+		w.skippedFn = d.skipped
+	}))
+	if err := n.WalkDir(fsys, root, d.walk, opts...); err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Root: root, Dirs: make([]DirUsage, 0, len(d.dirs))}
+	for _, dir := range d.dirs {
+		report.Dirs = append(report.Dirs, *dir)
+	}
+	sort.Slice(report.Dirs, func(i, j int) bool {
+		return report.Dirs[i].Path < report.Dirs[j].Path
+	})
+	return report, nil
+}
+
+type du struct {
+	fsys fs.FS
+	root string
+	dirs map[string]*DirUsage
+}
+
+// dir returns the usage of the directory which contains the path.
+// If the root is a file, it is counted for itself.
+func (d *du) dir(name string) *DirUsage {
+	if name != d.root {
+		name = path.Dir(name)
+	}
+
+	dir, ok := d.dirs[name]
+	if !ok {
+		dir = &DirUsage{Path: name}
+		d.dirs[name] = dir
+	}
+	return dir
+}
+
+func (d *du) walk(name string, entry fs.DirEntry, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if entry.IsDir() {
+		d.dirs[name] = &DirUsage{Path: name}
+		return nil
+	}
+
+	usage, err := fileUsage(entry)
+	if err != nil {
+		return err
+	}
+	d.dir(name).Included.add(usage)
+	return nil
+}
+
+// skipped counts an ignored file or all files of an ignored directory.
+func (d *du) skipped(name string, entry fs.DirEntry) error {
+	if !entry.IsDir() {
+		usage, err := fileUsage(entry)
+		if err != nil {
+			return err
+		}
+		d.dir(name).Ignored.add(usage)
+		return nil
+	}
+
+	dir := &DirUsage{Path: name, Skipped: true}
+	err := fs.WalkDir(d.fsys, name, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		usage, err := fileUsage(entry)
+		if err != nil {
+			return err
+		}
+		dir.Ignored.add(usage)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.dirs[name] = dir
+	d.dir(name).Ignored.add(dir.Ignored)
+	return nil
+}
+
+// postDir adds the usage of a directory to its parent,
+// after all of its children were counted.
+func (d *du) postDir(name string, _ fs.DirEntry, _ DirStats) error {
+	if name == d.root {
+		return nil
+	}
+
+	dir := d.dirs[name]
+	parent := d.dir(name)
+	parent.Included.add(dir.Included)
+	parent.Ignored.add(dir.Ignored)
+	return nil
+}
+
+func fileUsage(entry fs.DirEntry) (Usage, error) {
+	info, err := entry.Info()
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{Files: 1, Bytes: info.Size()}, nil
+}
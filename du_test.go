@@ -0,0 +1,81 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDu(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	fsys["build/nested/lib.a"] = &fstest.MapFile{Data: []byte("library")}
+
+	report, err := Du(fsys, ".")
+	require.NoError(t, err)
+
+	assert.Equal(t, Report{
+		Root: ".",
+		Dirs: []DirUsage{
+			{
+				Path:     ".",
+				Included: Usage{Files: 5, Bytes: 13 + 12 + 10 + 6 + 4},
+				Ignored:  Usage{Files: 6, Bytes: 3 + 6 + 7 + 6 + 3 + 3},
+			},
+			{
+				Path:    "build",
+				Ignored: Usage{Files: 2, Bytes: 6 + 7},
+				Skipped: true,
+			},
+			{
+				Path:     "sub",
+				Included: Usage{Files: 3, Bytes: 10 + 6 + 4},
+				Ignored:  Usage{Files: 3, Bytes: 6 + 3 + 3},
+			},
+			{
+				Path:     "sub/deeper",
+				Included: Usage{Files: 1, Bytes: 4},
+				Ignored:  Usage{Files: 2, Bytes: 3 + 3},
+			},
+		},
+	}, report)
+
+	assert.Equal(t, report.Dirs[0], report.Total())
+	sub, ok := report.Dir("sub")
+	assert.True(t, ok)
+	assert.Equal(t, report.Dirs[2], sub)
+	_, ok = report.Dir("missing")
+	assert.False(t, ok)
+}
+
+func TestNoGo_Du(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	t.Run("subdirectory", func(t *testing.T) {
+		report, err := n.Du(fsys, "sub/deeper")
+		require.NoError(t, err)
+		assert.Equal(t, Report{
+			Root: "sub/deeper",
+			Dirs: []DirUsage{
+				{
+					Path:     "sub/deeper",
+					Included: Usage{Files: 1, Bytes: 4},
+					Ignored:  Usage{Files: 2, Bytes: 6},
+				},
+			},
+		}, report)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		report, err := n.Du(fsys, "main.go")
+		require.NoError(t, err)
+		assert.Equal(t, DirUsage{Path: "main.go", Included: Usage{Files: 1, Bytes: 12}}, report.Total())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := n.Du(fsys, "missing")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+package nogo
+
+import "io/fs"
+
+// MatchEntry does the same as MatchBecause but gets whether the path is a
+// directory from the fs.DirEntry, e.g. in the callback of fs.WalkDir:
+//
+//	match, because := n.MatchEntry(path, d)
+//
+// Like git, symlinks are matched as files, as fs.WalkDir does not follow them.
+// A symlink is only matched as directory if the entry already describes the
+// target, i.e. its type contains fs.ModeDir (see WithFollowSymlinks) or its
+// Info is the one of a directory, as file systems which stat the target return it.
+//
+// If d is nil, e.g. for the error of the root in fs.WalkDir, it is handled
+// like DirUnknown by MatchHint.
+func (n *NoGo) MatchEntry(path string, d fs.DirEntry) (match bool, because Result) {
+	if d == nil {
+		return n.MatchHint(path, DirUnknown)
+	}
+	return n.MatchBecause(path, isDirEntry(d))
+}
+
+// isDirEntry checks if the entry is a directory or a symlink whose Info is
+// the one of a directory.
+func isDirEntry(d fs.DirEntry) bool {
+	if d.IsDir() {
+		return true
+	}
+	if d.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+
+	info, err := d.Info()
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,104 @@
+package nogo
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entryInfo is a fs.FileInfo which only has a mode.
+type entryInfo struct {
+	name string
+	mode fs.FileMode
+}
+
+func (i entryInfo) Name() string       { return i.name }
+func (i entryInfo) Size() int64        { return 0 }
+func (i entryInfo) Mode() fs.FileMode  { return i.mode }
+func (i entryInfo) ModTime() time.Time { return time.Time{} }
+func (i entryInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i entryInfo) Sys() interface{}   { return nil }
+
+// targetInfoEntry is a symlink entry whose Info describes the target.
+type targetInfoEntry struct {
+	name   string
+	target fs.FileMode
+}
+
+func (e targetInfoEntry) Name() string      { return e.name }
+func (e targetInfoEntry) IsDir() bool       { return false }
+func (e targetInfoEntry) Type() fs.FileMode { return fs.ModeSymlink }
+func (e targetInfoEntry) Info() (fs.FileInfo, error) {
+	return entryInfo{name: e.name, mode: e.target}, nil
+}
+
+func TestNoGo_MatchEntry(t *testing.T) {
+	n := New(WithRules(MustCompileAll("", []byte("build/\n*.log"))...))
+
+	tests := []struct {
+		name  string
+		entry fs.DirEntry
+		want  bool
+	}{
+		{name: "dir", entry: fs.FileInfoToDirEntry(entryInfo{name: "build", mode: fs.ModeDir}), want: true},
+		{name: "file", entry: fs.FileInfoToDirEntry(entryInfo{name: "build"}), want: false},
+		{name: "symlink", entry: fs.FileInfoToDirEntry(entryInfo{name: "build", mode: fs.ModeSymlink}), want: false},
+		{name: "followed symlink", entry: fs.FileInfoToDirEntry(entryInfo{name: "build", mode: fs.ModeDir | fs.ModeSymlink}), want: true},
+		{name: "symlink with target info", entry: targetInfoEntry{name: "build", target: fs.ModeDir}, want: true},
+		{name: "symlink to file", entry: targetInfoEntry{name: "build"}, want: false},
+		{name: "nil", entry: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, _ := n.MatchEntry("build", tt.entry)
+			assert.Equal(t, tt.want, match)
+		})
+	}
+
+	match, because := n.MatchEntry("debug.log", nil)
+	assert.True(t, match)
+	assert.Equal(t, "*.log", because.Pattern)
+}
+
+func TestNoGo_MatchEntry_WalkDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"build/out":   {},
+		"src/build":   {},
+		"src/main.go": {},
+	}
+	n := New(WithRules(MustCompileAll("", []byte("build/"))...))
+
+	var ignored []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		if match, _ := n.MatchEntry(path, d); match {
+			ignored = append(ignored, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build", "build/out"}, ignored)
+}
+
+func TestNoGo_MatchEntry_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "target"), 0o755))
+	if err := os.Symlink("target", filepath.Join(dir, "build")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+
+	entries, err := fs.ReadDir(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	require.Equal(t, "build", entries[0].Name())
+
+	// Like git, the symlink is no directory.
+	n := New(WithRules(MustCompileAll("", []byte("build/"))...))
+	match, _ := n.MatchEntry("build", entries[0])
+	assert.False(t, match)
+}
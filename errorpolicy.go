@@ -0,0 +1,92 @@
+package nogo
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+)
+
+// ErrorPolicy defines how NoGo.WalkDir handles errors while reading a
+// directory or loading its ignore file.
+type ErrorPolicy int
+
+const (
+	// ErrorReport passes the error to the WalkDirFunc, like fs.WalkDir does it.
+	// The WalkDirFunc decides whether to stop the walk or to skip the directory.
+	ErrorReport ErrorPolicy = iota
+
+	// ErrorAbort stops the walk on the first error and returns it.
+	// The WalkDirFunc is not called with the error.
+	ErrorAbort
+
+	// ErrorSkip skips the directories which can't be read and ignores the error.
+	ErrorSkip
+
+	// ErrorCollect skips the directories which can't be read like ErrorSkip,
+	// but returns all errors joined together after the walk has finished.
+	ErrorCollect
+)
+
+// WithErrorPolicy sets how errors while walking are handled.
+// The default is ErrorReport.
+//
+// ErrorCollect is useful for big scans, e.g. over network mounts, which should
+// not stop on the first unreadable directory but still report all failures.
+// Errors returned by the WalkDirFunc or the PostDirFunc always stop the walk.
+func WithErrorPolicy(policy ErrorPolicy) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.errs = &walkErrors{policy: policy}
+	})
+}
+
+// walkErrors handles the errors while walking according to the ErrorPolicy.
+type walkErrors struct {
+	policy ErrorPolicy
+
+	// mu protects errs, as WalkDirConcurrent handles errors concurrently.
+	mu   sync.Mutex
+	errs []error
+}
+
+// handleErr handles an error of the path which occurred while walking.
+// The result is handled like the result of the WalkDirFunc.
+func (w *walker) handleErr(name string, d fs.DirEntry, err error) error {
+	if w.errs == nil {
+		return w.fn(name, d, err)
+	}
+
+	switch w.errs.policy {
+	case ErrorAbort:
+		return err
+	case ErrorSkip:
+		return nil
+	case ErrorCollect:
+		w.errs.mu.Lock()
+		w.errs.errs = append(w.errs.errs, err)
+		w.errs.mu.Unlock()
+		return nil
+	default:
+		return w.fn(name, d, err)
+	}
+}
+
+// walkErr returns the final error of the walk, which includes all
+// collected errors for ErrorCollect.
+func (w *walker) walkErr(err error) error {
+	if err == fs.SkipDir || err == fs.SkipAll {
+		err = nil
+	}
+	if w.errs == nil {
+		return err
+	}
+
+	w.errs.mu.Lock()
+	defer w.errs.mu.Unlock()
+	if len(w.errs.errs) == 0 {
+		return err
+	}
+	if err == nil {
+		return errors.Join(w.errs.errs...)
+	}
+	return errors.Join(append(w.errs.errs, err)...)
+}
@@ -0,0 +1,108 @@
+package nogo
+
+import (
+	"io/fs"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoGo_WalkDir_errorPolicy(t *testing.T) {
+	fsys := ForbiddenFS{
+		MapFS: fstest.MapFS{
+			"a/file":   {},
+			"b/file":   {},
+			"c/file":   {},
+			"d/file":   {},
+			"root.txt": {},
+		},
+		NotExpected: map[string]struct{}{
+			"b": {},
+			"d": {},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []WalkOption
+		wantPaths []string
+		wantErrs  int
+	}{
+		{
+			name:      "report",
+			wantPaths: []string{".", "a", "a/file", "b", "b (error)", "c", "c/file", "d", "d (error)", "root.txt"},
+		},
+		{
+			name:      "abort",
+			opts:      []WalkOption{WithErrorPolicy(ErrorAbort)},
+			wantPaths: []string{".", "a", "a/file", "b"},
+			wantErrs:  1,
+		},
+		{
+			name:      "skip",
+			opts:      []WalkOption{WithErrorPolicy(ErrorSkip)},
+			wantPaths: []string{".", "a", "a/file", "b", "c", "c/file", "d", "root.txt"},
+		},
+		{
+			name:      "collect",
+			opts:      []WalkOption{WithErrorPolicy(ErrorCollect)},
+			wantPaths: []string{".", "a", "a/file", "b", "c", "c/file", "d", "root.txt"},
+			wantErrs:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					// Continue with the next directory.
+					paths = append(paths, path+" (error)")
+					return nil
+				}
+				paths = append(paths, path)
+				return nil
+			}, tt.opts...)
+
+			assert.Equal(t, tt.wantPaths, paths)
+			assertWalkErrs(t, tt.wantErrs, err)
+		})
+
+		t.Run(tt.name+" concurrent", func(t *testing.T) {
+			if tt.opts == nil || tt.name == "abort" {
+				// The order of the walk is not defined.
+				return
+			}
+
+			var mu sync.Mutex
+			var paths []string
+			err := New().WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+				mu.Lock()
+				defer mu.Unlock()
+				paths = append(paths, path)
+				return err
+			}, tt.opts...)
+
+			sort.Strings(paths)
+			assert.Equal(t, tt.wantPaths, paths)
+			assertWalkErrs(t, tt.wantErrs, err)
+		})
+	}
+}
+
+func assertWalkErrs(t *testing.T, want int, err error) {
+	t.Helper()
+	if want == 0 {
+		assert.NoError(t, err)
+		return
+	}
+
+	assert.ErrorIs(t, err, ErrShouldNotBeReached)
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		assert.Len(t, joined.Unwrap(), want)
+	} else {
+		assert.Equal(t, 1, want)
+	}
+}
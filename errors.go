@@ -0,0 +1,68 @@
+package nogo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPattern is matched by all errors caused by a pattern which can't
+// be compiled. Use errors.As with a *PatternError to get the position.
+var ErrInvalidPattern = errors.New("nogo: invalid pattern")
+
+// PatternError is returned if a pattern can't be compiled.
+// errors.Is(err, ErrInvalidPattern) is true for it.
+type PatternError struct {
+	// File is the ignore file which contains the pattern.
+	// It is empty if the pattern wasn't loaded from a file.
+	File string
+
+	// Line is the line number, starting at 1.
+	// It is 0 if the line is unknown, e.g. for Compile.
+	Line int
+
+	// Pattern is the line which can't be compiled.
+	Pattern string
+
+	// Cause is the underlying error, e.g. of the regexp package.
+	Cause error
+}
+
+func (e *PatternError) Error() string {
+	var b strings.Builder
+	b.WriteString("nogo: ")
+	if e.File != "" {
+		b.WriteString(e.File)
+		b.WriteString(": ")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	fmt.Fprintf(&b, "invalid pattern %q: %v", e.Pattern, e.Cause)
+	return b.String()
+}
+
+// Unwrap returns the Cause.
+func (e *PatternError) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns true for ErrInvalidPattern.
+func (e *PatternError) Is(target error) bool {
+	return target == ErrInvalidPattern
+}
+
+// patternErrorAt sets the file and line of a PatternError if they are not
+// set yet. Other errors are returned unchanged.
+func patternErrorAt(err error, file string, line int) error {
+	var patternErr *PatternError
+	if errors.As(err, &patternErr) {
+		if patternErr.File == "" {
+			patternErr.File = file
+		}
+		if patternErr.Line == 0 {
+			patternErr.Line = line
+		}
+	}
+	return err
+}
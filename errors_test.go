@@ -0,0 +1,111 @@
+package nogo
+
+import (
+	"errors"
+	"io/fs"
+	"regexp/syntax"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() error
+		want PatternError
+		msg  string
+	}{
+		{
+			name: "Compile",
+			err: func() error {
+				_, _, err := Compile("", "a[")
+				return err
+			},
+			want: PatternError{Pattern: "a["},
+			msg:  `nogo: invalid pattern "a[": `,
+		},
+		{
+			name: "CompileAll",
+			err: func() error {
+				_, err := CompileAll("", []byte("*.log\n\nsrc/[z-a]"))
+				return err
+			},
+			want: PatternError{Line: 3, Pattern: "src/[z-a]"},
+			msg:  `nogo: line 3: invalid pattern "src/[z-a]": `,
+		},
+		{
+			name: "AddFile",
+			err: func() error {
+				fsys := fstest.MapFS{
+					"sub/.gitignore": {Data: []byte("*.log\na[")},
+				}
+				return New().AddFile(fsys, "sub/.gitignore")
+			},
+			want: PatternError{File: "sub/.gitignore", Line: 2, Pattern: "a["},
+			msg:  `nogo: sub/.gitignore: line 2: invalid pattern "a[": `,
+		},
+		{
+			name: "AddFromFS",
+			err: func() error {
+				fsys := fstest.MapFS{
+					".gitignore":     {Data: []byte("*.log")},
+					"sub/.gitignore": {Data: []byte("a[")},
+				}
+				return New().AddFromFS(fsys, ".gitignore")
+			},
+			want: PatternError{File: "sub/.gitignore", Line: 1, Pattern: "a["},
+			msg:  `nogo: sub/.gitignore: line 1: invalid pattern "a[": `,
+		},
+		{
+			name: "braces",
+			err: func() error {
+				_, err := DialectBraces.CompileAll("", []byte("*.+(js|ts)"))
+				return err
+			},
+			want: PatternError{Line: 1, Pattern: "*.+(js|ts)"},
+			msg:  `nogo: line 1: invalid pattern "*.+(js|ts)": `,
+		},
+		{
+			name: "hgignore",
+			err: func() error {
+				_, err := DialectHgignore.CompileAll("", []byte("syntax: regexp\n(a"))
+				return err
+			},
+			want: PatternError{Line: 2, Pattern: "(a"},
+			msg:  `nogo: line 2: invalid pattern "(a": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err()
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrInvalidPattern)
+			assert.False(t, errors.Is(err, fs.ErrNotExist))
+			assert.ErrorContains(t, err, tt.msg)
+
+			var patternErr *PatternError
+			require.ErrorAs(t, err, &patternErr)
+			assert.Equal(t, tt.want.File, patternErr.File)
+			assert.Equal(t, tt.want.Line, patternErr.Line)
+			assert.Equal(t, tt.want.Pattern, patternErr.Pattern)
+			assert.Error(t, patternErr.Cause)
+		})
+	}
+}
+
+func TestPatternError_Unwrap(t *testing.T) {
+	_, _, err := Compile("", "a[")
+
+	var syntaxErr *syntax.Error
+	assert.ErrorAs(t, err, &syntaxErr)
+}
+
+func TestPatternError_FileError(t *testing.T) {
+	err := New().AddFile(fstest.MapFS{}, ".gitignore")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.False(t, errors.Is(err, ErrInvalidPattern))
+}
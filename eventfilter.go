@@ -0,0 +1,19 @@
+package nogo
+
+// EventFilter returns a function for file system events, e.g. of fsnotify,
+// which returns false for ignored paths, so their events can be dropped.
+// The paths have to be relative to the root of the rules, like for Match.
+//
+// Events don't tell whether the path is a directory, so the paths are matched
+// like MatchHint with DirUnknown: Use WithStatFS to check existing paths.
+// Otherwise rules ending with a '/' only drop the events of paths inside of
+// the directory, but not the ones of the directory itself.
+//
+// See the package github.com/aligator/nogo/nogofsnotify for a fsnotify
+// watcher which uses it.
+func EventFilter(n *NoGo) func(path string) bool {
+	return func(path string) bool {
+		match, _ := n.MatchHint(path, DirUnknown)
+		return !match
+	}
+}
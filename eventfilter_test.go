@@ -0,0 +1,40 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFilter(t *testing.T) {
+	rules := WithRules(MustCompileAll("", []byte("node_modules/\n*.log\n!keep.log\nbuild/"))...)
+	fsys := fstest.MapFS{
+		"build/out.o":  {},
+		"node_modules": {Data: []byte("a file")},
+	}
+
+	tests := []struct {
+		path       string
+		want       bool
+		wantStatFS bool
+	}{
+		{path: "main.go", want: true, wantStatFS: true},
+		{path: "debug.log", want: false, wantStatFS: false},
+		{path: "keep.log", want: true, wantStatFS: true},
+		{path: "node_modules/react/index.js", want: false, wantStatFS: false},
+		{path: "build/out.o", want: false, wantStatFS: false},
+		// Without a file system it is unknown whether it is a directory.
+		{path: "build", want: true, wantStatFS: false},
+		{path: "node_modules", want: true, wantStatFS: true},
+		{path: "build/", want: false, wantStatFS: false},
+	}
+	filter := EventFilter(New(rules))
+	statFilter := EventFilter(New(rules, WithStatFS(fsys)))
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, filter(tt.path))
+			assert.Equal(t, tt.wantStatFS, statFilter(tt.path))
+		})
+	}
+}
@@ -22,8 +22,8 @@ func main() {
 	// DirFs actually implements StatFS, so we can use it.
 	wdfs := os.DirFS(wd).(fs.StatFS)
 
-	n := nogo.New(nogo.DotGitRule)
-	if err := n.AddFromFS(wdfs, ".gitignore"); err != nil {
+	n, err := nogo.ForFS(wdfs, ".gitignore")
+	if err != nil {
 		panic(err)
 	}
 
@@ -34,20 +34,13 @@ func main() {
 			toSearch = "."
 		}
 
-		info, err := wdfs.Stat(toSearch)
-		if err != nil {
-			panic(err)
-		}
-
-		if info.Name() == ".git" {
-			return
-		}
-
+		// MatchFS stats the path and always ignores the .git directory.
+		match, _, err := n.MatchFS(wdfs, toSearch)
 		if err != nil {
 			panic(err)
 		}
 
-		if n.Match(toSearch, info.IsDir()) {
+		if match {
 			fmt.Printf("./%v\n", toSearch)
 		}
 	}
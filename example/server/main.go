@@ -0,0 +1,32 @@
+// Server serves the ignore rules of the working directory over HTTP.
+//
+//	curl -d '{"paths": ["main.go", "build/"]}' http://localhost:8080/match
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/aligator/nogo"
+	"github.com/aligator/nogo/server"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "the address to listen on")
+	flag.Parse()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	n, err := nogo.ForFS(os.DirFS(wd), ".gitignore", nogo.DotGitRule)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("serving the ignore rules of %s on %s", wd, *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.NewHandler(n)))
+}
@@ -0,0 +1,234 @@
+// Code generated by internal/gendoc from corpus/behavior.txt. DO NOT EDIT.
+
+package nogo_test
+
+import (
+	"fmt"
+
+	"github.com/aligator/nogo"
+)
+
+// This example shows which paths the pattern "*.go" ignores.
+func Example_corpus01() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("*.go"))...)
+
+	fmt.Println("main.go", n.Match("main.go", false))
+	fmt.Println("cmd/main.go", n.Match("cmd/main.go", false))
+	fmt.Println("main.go/", n.Match("main.go", true))
+	fmt.Println("main.goo", n.Match("main.goo", false))
+	fmt.Println("go", n.Match("go", false))
+
+	// Output:
+	// main.go true
+	// cmd/main.go true
+	// main.go/ true
+	// main.goo false
+	// go false
+}
+
+// This example shows which paths the pattern "/build" ignores.
+func Example_corpus02() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("/build"))...)
+
+	fmt.Println("build", n.Match("build", false))
+	fmt.Println("build/", n.Match("build", true))
+	fmt.Println("build/out.txt", n.Match("build/out.txt", false))
+	fmt.Println("cmd/build", n.Match("cmd/build", false))
+
+	// Output:
+	// build true
+	// build/ true
+	// build/out.txt true
+	// cmd/build false
+}
+
+// This example shows which paths the pattern "build/" ignores.
+func Example_corpus03() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("build/"))...)
+
+	fmt.Println("build/", n.Match("build", true))
+	fmt.Println("build", n.Match("build", false))
+	fmt.Println("build/out.txt", n.Match("build/out.txt", false))
+	fmt.Println("cmd/build/", n.Match("cmd/build", true))
+
+	// Output:
+	// build/ true
+	// build false
+	// build/out.txt true
+	// cmd/build/ true
+}
+
+// This example shows which paths the pattern "doc/frotz/" ignores.
+func Example_corpus04() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("doc/frotz/"))...)
+
+	fmt.Println("doc/frotz/", n.Match("doc/frotz", true))
+	fmt.Println("a/doc/frotz/", n.Match("a/doc/frotz", true))
+	fmt.Println("doc/frotz", n.Match("doc/frotz", false))
+
+	// Output:
+	// doc/frotz/ true
+	// a/doc/frotz/ false
+	// doc/frotz false
+}
+
+// This example shows which paths the pattern "**/foo" ignores.
+func Example_corpus05() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("**/foo"))...)
+
+	fmt.Println("foo", n.Match("foo", false))
+	fmt.Println("a/b/foo", n.Match("a/b/foo", false))
+	fmt.Println("a/foo/bar", n.Match("a/foo/bar", false))
+	fmt.Println("afoo", n.Match("afoo", false))
+
+	// Output:
+	// foo true
+	// a/b/foo true
+	// a/foo/bar true
+	// afoo false
+}
+
+// This example shows which paths the pattern "abc/**" ignores.
+func Example_corpus06() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("abc/**"))...)
+
+	fmt.Println("abc/x", n.Match("abc/x", false))
+	fmt.Println("abc/x/y", n.Match("abc/x/y", false))
+	fmt.Println("abc", n.Match("abc", false))
+	fmt.Println("x/abc/y", n.Match("x/abc/y", false))
+
+	// Output:
+	// abc/x true
+	// abc/x/y true
+	// abc false
+	// x/abc/y false
+}
+
+// This example shows which paths the pattern "a/**/b" ignores.
+func Example_corpus07() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("a/**/b"))...)
+
+	fmt.Println("a/b", n.Match("a/b", false))
+	fmt.Println("a/x/b", n.Match("a/x/b", false))
+	fmt.Println("a/x/y/b", n.Match("a/x/y/b", false))
+	fmt.Println("a/x/c", n.Match("a/x/c", false))
+
+	// Output:
+	// a/b true
+	// a/x/b true
+	// a/x/y/b true
+	// a/x/c false
+}
+
+// This example shows which paths the pattern "file[a-z].txt" ignores.
+func Example_corpus08() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("file[a-z].txt"))...)
+
+	fmt.Println("filea.txt", n.Match("filea.txt", false))
+	fmt.Println("fileZ.txt", n.Match("fileZ.txt", false))
+	fmt.Println("file1.txt", n.Match("file1.txt", false))
+	fmt.Println("file/.txt", n.Match("file/.txt", false))
+
+	// Output:
+	// filea.txt true
+	// fileZ.txt false
+	// file1.txt false
+	// file/.txt false
+}
+
+// This example shows which paths the pattern "file[!0-9].txt" ignores.
+func Example_corpus09() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("file[!0-9].txt"))...)
+
+	fmt.Println("filea.txt", n.Match("filea.txt", false))
+	fmt.Println("file1.txt", n.Match("file1.txt", false))
+	fmt.Println("file/.txt", n.Match("file/.txt", false))
+
+	// Output:
+	// filea.txt true
+	// file1.txt false
+	// file/.txt false
+}
+
+// This example shows which paths the pattern "\\#hash" ignores.
+func Example_corpus10() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("\\#hash"))...)
+
+	fmt.Println("#hash", n.Match("#hash", false))
+	fmt.Println("a/#hash", n.Match("a/#hash", false))
+
+	// Output:
+	// #hash true
+	// a/#hash true
+}
+
+// This example shows which paths the pattern "!main.go" ignores.
+func Example_corpus11() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("!main.go"))...)
+
+	fmt.Println("main.go", n.Match("main.go", false))
+	fmt.Println("other.go", n.Match("other.go", false))
+
+	// Output:
+	// main.go false
+	// other.go false
+}
+
+// This example shows which paths the pattern "foo?" ignores.
+func Example_corpus12() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("foo?"))...)
+
+	fmt.Println("foox", n.Match("foox", false))
+	fmt.Println("fooä", n.Match("fooä", false))
+
+	// Output:
+	// foox true
+	// fooä false
+}
+
+// This example shows which paths the pattern "foo??" ignores.
+func Example_corpus13() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("foo??"))...)
+
+	fmt.Println("fooä", n.Match("fooä", false))
+
+	// Output:
+	// fooä true
+}
+
+// This example shows which paths the pattern "file[α-ω].txt" ignores.
+func Example_corpus14() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("file[α-ω].txt"))...)
+
+	fmt.Println("fileβ.txt", n.Match("fileβ.txt", false))
+	fmt.Println("filea.txt", n.Match("filea.txt", false))
+
+	// Output:
+	// fileβ.txt false
+	// filea.txt false
+}
+
+// This example shows which paths the pattern "file[α-ω][α-ω].txt" ignores.
+func Example_corpus15() {
+	n := nogo.New()
+	n.AddRules(nogo.MustCompileAll("", []byte("file[α-ω][α-ω].txt"))...)
+
+	fmt.Println("fileβ.txt", n.Match("fileβ.txt", false))
+
+	// Output:
+	// fileβ.txt true
+}
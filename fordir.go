@@ -0,0 +1,126 @@
+package nogo
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// DirMatcher matches paths relative to a directory inside of a repository.
+// It is created by ForDir.
+type DirMatcher struct {
+	// Root is the absolute path of the repository root.
+	Root string
+
+	// Dir is the directory relative to Root, using '/' as separator.
+	// It is "" if the directory is the root.
+	Dir string
+
+	// NoGo contains the rules of the repository.
+	// It matches paths relative to Root.
+	NoGo *NoGo
+}
+
+var _ Matcher = (*DirMatcher)(nil)
+
+// ForDir discovers the repository which contains dir, like git does it, and
+// returns a matcher for paths relative to dir. E.g. a tool started in a
+// sub folder of a repository can match paths relative to the working directory:
+//
+//	m, err := nogo.ForDir(".")
+//	// ...
+//	err = nogo.WalkDirMatcher(os.DirFS("."), ".", m, fn)
+//
+// The root of the repository is the nearest parent folder (or dir itself)
+// which contains a ".git". Like git, the search stops below the directories
+// listed in $GIT_CEILING_DIRECTORIES. If no repository is found, dir is used
+// as root.
+//
+// The ignore files from the root down to dir are loaded immediately and their
+// errors are returned. All other ignore files are loaded lazily as with WithFS,
+// which is set to the root. DotGitRule is added in front of the options.
+func ForDir(dir string, opts ...Option) (*DirMatcher, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := findRepositoryRoot(abs, gitCeilingDirectories(os.Getenv("GIT_CEILING_DIRECTORIES")))
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return nil, err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		rel = ""
+	}
+
+	opts = append([]Option{DotGitRule}, opts...)
+	opts = append(opts, WithFS(os.DirFS(root)))
+	n := New(opts...)
+	if n.gitDir {
+		if err := n.loadGitDir(n.loadFS, root); err != nil {
+			return nil, err
+		}
+	}
+
+	// Loading the parents of the ignore file of dir loads all ignore files
+	// from the root down to dir.
+	n.lazy.mu.Lock()
+	n.lazy.load(n, path.Join(rel, n.lazy.names[0]))
+	n.lazy.mu.Unlock()
+	if err := n.LazyLoadErr(); err != nil {
+		return nil, err
+	}
+
+	return &DirMatcher{
+		Root: root,
+		Dir:  rel,
+		NoGo: n,
+	}, nil
+}
+
+// Match does the same as MatchBecause but only returns the boolean.
+func (m *DirMatcher) Match(path string, isDir bool) bool {
+	match, _ := m.MatchBecause(path, isDir)
+	return match
+}
+
+// MatchBecause matches the path relative to Dir.
+// The Prefix of the Result is still relative to Root.
+func (m *DirMatcher) MatchBecause(name string, isDir bool) (match bool, because Result) {
+	if m.Dir != "" {
+		name = path.Join(m.Dir, name)
+	}
+	return m.NoGo.MatchBecause(name, isDir)
+}
+
+// findRepositoryRoot returns the nearest parent of dir (or dir itself) which
+// contains a ".git". The parents in ceilings and their parents are not checked.
+// If there is none, dir is returned.
+func findRepositoryRoot(dir string, ceilings []string) string {
+	current := dir
+	for {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current || contains(ceilings, parent) {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// gitCeilingDirectories parses the value of $GIT_CEILING_DIRECTORIES.
+// Like git, relative paths are ignored.
+func gitCeilingDirectories(value string) []string {
+	var ceilings []string
+	for _, ceiling := range filepath.SplitList(value) {
+		if filepath.IsAbs(ceiling) {
+			ceilings = append(ceilings, filepath.Clean(ceiling))
+		}
+	}
+	return ceilings
+}
@@ -0,0 +1,106 @@
+package nogo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newForDirTestRepo creates a repository with ignore files in several folders.
+func newForDirTestRepo(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":              "ref: refs/heads/main",
+		".gitignore":             "*.log\n/build",
+		"src/.gitignore":         "*.tmp\n!keep.log",
+		"src/app/.gitignore":     "/generated",
+		"src/app/lib/.gitignore": "*.bin",
+		"src/app/main.go":        "",
+	}
+	for name, content := range files {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+	}
+	return root
+}
+
+func TestForDir(t *testing.T) {
+	t.Setenv("GIT_CEILING_DIRECTORIES", "")
+	root := newForDirTestRepo(t)
+
+	m, err := ForDir(filepath.Join(root, "src", "app"))
+	require.NoError(t, err)
+	assert.Equal(t, root, m.Root)
+	assert.Equal(t, "src/app", m.Dir)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "main.go", want: false},
+		{path: "debug.log", want: true},
+		{path: "keep.log", want: false},
+		{path: "cache.tmp", want: true},
+		{path: "generated", isDir: true, want: true},
+		{path: "build", isDir: true, want: false},
+		{path: "lib/data.bin", want: true},
+		{path: "other/data.bin", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestForDir_Root(t *testing.T) {
+	t.Setenv("GIT_CEILING_DIRECTORIES", "")
+	root := newForDirTestRepo(t)
+
+	m, err := ForDir(root)
+	require.NoError(t, err)
+	assert.Equal(t, "", m.Dir)
+	assert.True(t, m.Match(".git", true))
+	assert.True(t, m.Match("build", true))
+	assert.True(t, m.Match("src/a.tmp", false))
+}
+
+func TestForDir_Ceiling(t *testing.T) {
+	root := newForDirTestRepo(t)
+	t.Setenv("GIT_CEILING_DIRECTORIES", root)
+
+	dir := filepath.Join(root, "src", "app")
+	m, err := ForDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, m.Root)
+	assert.Equal(t, "", m.Dir)
+	assert.False(t, m.Match("debug.log", false))
+	assert.True(t, m.Match("generated", true))
+}
+
+func TestForDir_Error(t *testing.T) {
+	t.Setenv("GIT_CEILING_DIRECTORIES", "")
+	root := newForDirTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", ".gitignore"), []byte("a["), 0o644))
+
+	_, err := ForDir(filepath.Join(root, "src", "app"))
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func TestGitCeilingDirectories(t *testing.T) {
+	value := filepath.Join(string(filepath.Separator), "a", "b") + string(os.PathListSeparator) +
+		"relative" + string(os.PathListSeparator) +
+		filepath.Join(string(filepath.Separator), "c") + string(filepath.Separator)
+
+	assert.Equal(t, []string{
+		filepath.Join(string(filepath.Separator), "a", "b"),
+		filepath.Join(string(filepath.Separator), "c"),
+	}, gitCeilingDirectories(value))
+	assert.Nil(t, gitCeilingDirectories(""))
+}
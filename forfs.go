@@ -0,0 +1,168 @@
+package nogo
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ForFS creates a NoGo instance with the options and loads all ignore files
+// with the ignoreFileName inside of the fsys, like AddFromFS does it.
+// Rules are Options, too, so additional rules can be passed directly:
+//
+//	n, err := nogo.ForFS(fsys, ".gitignore", nogo.DotGitRule, nogo.WithIgnoreCase())
+//
+// Use WithLazyLoading to load the ignore files only when they are needed.
+// The fsys and ignoreFileName override WithFS and WithIgnoreFileNames.
+func ForFS(fsys fs.FS, ignoreFileName string, opts ...Option) (*NoGo, error) {
+	opts = append(opts, WithFS(fsys), WithIgnoreFileNames(ignoreFileName))
+
+	n := &NoGo{}
+	for _, opt := range opts {
+		opt.apply(n)
+	}
+	if n.gitDir {
+		if err := n.loadGitDir(fsys, ""); err != nil {
+			return nil, err
+		}
+	}
+	if n.lazyLoading {
+		n.initLazyLoading()
+		return n, nil
+	}
+
+	if err := n.addFromFS(fsys, ignoreFileName); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// WithLazyLoading lets ForFS load the ignore files of a directory when a path
+// inside of it is matched the first time, instead of loading all of them at once.
+// This is much faster for big file systems if only a few paths get matched.
+// New always loads the ignore files of WithFS lazily.
+//
+// As the rules may change with each match, all matches are synchronized using
+// a mutex. Errors while loading an ignore file can't be returned by the match
+// methods, so the directory is matched without it. Use LazyLoadErr to get them,
+// or WithStrictIO to ignore the directory instead.
+func WithLazyLoading() Option {
+	return optionFunc(func(n *NoGo) {
+		n.lazyLoading = true
+	})
+}
+
+// LazyLoadErr returns the errors which occurred while loading ignore files
+// with WithLazyLoading, or nil if there were none.
+func (n *NoGo) LazyLoadErr() error {
+	if n.lazy == nil {
+		return nil
+	}
+
+	n.lazy.mu.Lock()
+	defer n.lazy.mu.Unlock()
+	return errors.Join(n.lazy.errs...)
+}
+
+// initLazyLoading enables the lazy loading of the ignore files of WithFS.
+func (n *NoGo) initLazyLoading() {
+	if n.loadFS == nil {
+		return
+	}
+
+	names := n.ignoreFileNames
+	if len(names) == 0 {
+		names = []string{".gitignore"}
+	}
+	n.lazy = &lazyLoader{
+		fsys:   n.loadFS,
+		names:  names,
+		loaded: make(map[string]bool),
+	}
+}
+
+// lazyLoader loads the ignore files of WithFS when they are needed.
+type lazyLoader struct {
+	fsys  fs.FS
+	names []string
+
+	// mu synchronizes all matches, as the rules may change while matching.
+	mu sync.Mutex
+	// loaded contains all directories which were already checked.
+	loaded map[string]bool
+	errs   []error
+}
+
+// lock loads the ignore files of all parents of the path and locks the
+// loader until unlock is called. It does nothing if lazy loading is not used.
+func (n *NoGo) lock(path string) {
+	if n.lazy == nil {
+		return
+	}
+
+	n.lazy.mu.Lock()
+	if path, err := n.fsPath(path); err == nil {
+		n.lazy.load(n, path)
+	}
+}
+
+// unlock releases the lock of lock.
+func (n *NoGo) unlock() {
+	if n.lazy != nil {
+		n.lazy.mu.Unlock()
+	}
+}
+
+// load loads the ignore files of all parents of the path, the same way as
+// AddFromFS: Ignore files in ignored directories and ignored ignore files
+// are not loaded.
+// For allow lists all of them are loaded, as everything is excluded until
+// the rules which include something are loaded.
+func (l *lazyLoader) load(n *NoGo, name string) {
+	dir := "."
+	segments := strings.Split(name, "/")
+	for i := 0; i < len(segments); i++ {
+		if i > 0 {
+			dir = path.Join(dir, segments[i-1])
+			if l.ignored(n, dir, true) {
+				return
+			}
+		}
+
+		// The case may differ if WithIgnoreCase is used.
+		key, _ := n.normalize(dir)
+		if l.loaded[key] {
+			continue
+		}
+		l.loaded[key] = true
+
+		for _, ignoreFileName := range l.names {
+			file := path.Join(dir, ignoreFileName)
+			if l.ignored(n, file, false) {
+				continue
+			}
+			if err := n.AddFile(l.fsys, file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				l.errs = append(l.errs, err)
+				if n.strictIO && isIOError(err) {
+					n.ignoreUnreadable(dir, file)
+				}
+			}
+		}
+	}
+}
+
+// ignored checks if the path is ignored. It is always false for allow lists.
+func (l *lazyLoader) ignored(n *NoGo, path string, isDir bool) bool {
+	if n.allowList {
+		return false
+	}
+
+	normalized, ok := n.normalize(path)
+	if !ok {
+		return false
+	}
+	match, _ := n.matchRules(normalized, isDir, false)
+	return match
+}
@@ -0,0 +1,163 @@
+package nogo
+
+import (
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFS counts how often each file was opened.
+type countingFS struct {
+	fstest.MapFS
+
+	mu     sync.Mutex
+	opened map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.mu.Lock()
+	c.opened[name]++
+	c.mu.Unlock()
+	return c.MapFS.Open(name)
+}
+
+func newForFSTestFS() *countingFS {
+	return &countingFS{
+		MapFS: fstest.MapFS{
+			".gitignore":               {Data: []byte("*.log\n/build\nignored.gitignore")},
+			"main.go":                  {},
+			"debug.log":                {},
+			".git/config":              {},
+			"build/.gitignore":         {Data: []byte("!*.log")},
+			"build/a.log":              {},
+			"sub/.gitignore":           {Data: []byte("!keep.log\n/out")},
+			"sub/keep.log":             {},
+			"sub/out/file":             {},
+			"Other/.gitignore":         {Data: []byte("*.txt")},
+			"Other/a.txt":              {},
+			"other2/ignored.gitignore": {Data: []byte("*")},
+		},
+		opened: make(map[string]int),
+	}
+}
+
+func TestForFS(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: ".git", isDir: true, want: true},
+		{path: ".git/config", want: true},
+		{path: "main.go", want: false},
+		{path: "debug.log", want: true},
+		{path: "build/a.log", want: true},
+		{path: "sub/keep.log", want: false},
+		{path: "sub/other.log", want: true},
+		{path: "sub/out", isDir: true, want: true},
+		{path: "sub/out/file", want: true},
+		{path: "Other/a.txt", want: true},
+		{path: "a.txt", want: false},
+	}
+
+	for _, lazy := range []bool{false, true} {
+		opts := []Option{DotGitRule}
+		if lazy {
+			opts = append(opts, WithLazyLoading())
+		}
+
+		n, err := ForFS(newForFSTestFS(), ".gitignore", opts...)
+		require.NoError(t, err)
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir), "%v: %s", lazy, tt.path)
+
+			match, _ := n.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, match, "%v: %s", lazy, tt.path)
+
+			suffix := ""
+			if tt.isDir {
+				suffix = "/"
+			}
+			matches, _ := n.MatchAll([]string{tt.path + suffix})
+			assert.Equal(t, tt.want, matches[0], "%v: %s", lazy, tt.path)
+		}
+		assert.NoError(t, n.LazyLoadErr())
+	}
+
+	t.Run("error", func(t *testing.T) {
+		fsys := newForFSTestFS()
+		fsys.MapFS["sub/.gitignore"].Mode = fs.ModeDir
+
+		_, err := ForFS(fsys, ".gitignore")
+		assert.Error(t, err)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		n, err := ForFS(newForFSTestFS(), ".gitignore", WithIgnoreCase(), WithDialect(DialectBraces))
+		require.NoError(t, err)
+		assert.True(t, n.Match("DEBUG.LOG", false))
+		assert.True(t, n.Match("other/A.TXT", false))
+	})
+}
+
+func TestWithLazyLoading(t *testing.T) {
+	fsys := newForFSTestFS()
+	n, err := ForFS(fsys, ".gitignore", WithLazyLoading())
+	require.NoError(t, err)
+	assert.Empty(t, fsys.opened)
+
+	assert.False(t, n.Match("main.go", false))
+	assert.Equal(t, map[string]int{".gitignore": 1}, fsys.opened)
+
+	assert.False(t, n.Match("sub/keep.log", false))
+	assert.True(t, n.Match("sub/out/file", false))
+	assert.Equal(t, map[string]int{".gitignore": 1, "sub/.gitignore": 1}, fsys.opened)
+
+	// Ignore files inside of ignored directories and ignored ignore files are not loaded.
+	assert.True(t, n.Match("build/a.log", false))
+	assert.False(t, n.Match("other2/file", false))
+	assert.NotContains(t, fsys.opened, "build/.gitignore")
+	assert.NotContains(t, fsys.opened, "other2/ignored.gitignore")
+
+	t.Run("errors", func(t *testing.T) {
+		fsys := newForFSTestFS()
+		fsys.MapFS["sub/.gitignore"].Mode = fs.ModeDir
+		n, err := ForFS(fsys, ".gitignore", WithLazyLoading())
+		require.NoError(t, err)
+
+		assert.True(t, n.Match("sub/a.log", false))
+		assert.Error(t, n.LazyLoadErr())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		fsys := newForFSTestFS()
+		n, err := ForFS(fsys, ".gitignore", WithLazyLoading(), WithCache(100))
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.False(t, n.Match("sub/keep.log", false))
+				assert.True(t, n.Match("Other/a.txt", false))
+			}()
+		}
+		wg.Wait()
+
+		for name, count := range fsys.opened {
+			assert.Equal(t, 1, count, name)
+		}
+	})
+
+	t.Run("New", func(t *testing.T) {
+		n := New(WithLazyLoading())
+		assert.Nil(t, n.lazy)
+		assert.NoError(t, n.LazyLoadErr())
+	})
+}
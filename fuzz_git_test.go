@@ -0,0 +1,43 @@
+//go:build gitfuzz
+
+package nogo
+
+import (
+	"testing"
+
+	"github.com/aligator/nogo/internal/refmatch"
+	"github.com/stretchr/testify/assert"
+)
+
+// FuzzGit compares the decisions of nogo and of the reference implementation
+// with "git check-ignore". It starts git for each input, so it is slow and
+// only built with the gitfuzz build tag:
+//
+//	go test -tags gitfuzz -run XXX -fuzz FuzzGit
+func FuzzGit(f *testing.F) {
+	for _, c := range append(Corpus(), Regressions()...) {
+		f.Add(c.Pattern, c.Path, c.IsDir)
+	}
+
+	repo := newGitRepo(f)
+	f.Fuzz(func(t *testing.T, pattern string, path string, isDir bool) {
+		if !validFuzzLine(pattern) || !validGitFuzzPath(path) {
+			t.Skip()
+		}
+
+		want, ok := repo.ignored(t, pattern, path, isDir)
+		if !ok {
+			t.Skip()
+		}
+
+		// The reference implementation has no known divergences.
+		assert.Equal(t, want, refmatch.Ignored(pattern, path, isDir), "reference: pattern %q, path %q, isDir %v", pattern, path, isDir)
+
+		rules, err := CompileAll("", []byte(pattern))
+		if err != nil {
+			return
+		}
+		got := New(WithRules(rules...)).Match(path, isDir)
+		assert.Equal(t, want, got, "nogo: pattern %q, path %q, isDir %v", pattern, path, isDir)
+	})
+}
@@ -0,0 +1,122 @@
+package nogo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aligator/nogo/internal/refmatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzReference compares the decisions of nogo for a single pattern with the
+// reference implementation of internal/refmatch. The behavior and regression
+// corpora are used as seed. Divergences found by the fuzzer are minimized and
+// added to corpus/regressions.txt.
+func FuzzReference(f *testing.F) {
+	for _, c := range append(Corpus(), Regressions()...) {
+		f.Add(c.Pattern, c.Path, c.IsDir)
+	}
+
+	f.Fuzz(func(t *testing.T, pattern string, path string, isDir bool) {
+		if !validFuzzLine(pattern) || !validFuzzPath(path) {
+			t.Skip()
+		}
+
+		rules, err := CompileAll("", []byte(pattern))
+		if err != nil {
+			t.Skip()
+		}
+		want := refmatch.Ignored(pattern, path, isDir)
+		for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+			got := New(WithRules(rules...), WithBackend(backend)).Match(path, isDir)
+			assert.Equal(t, want, got, "%v: pattern %q, path %q, isDir %v", backend, pattern, path, isDir)
+		}
+	})
+}
+
+// validFuzzLine accepts single lines without NUL.
+func validFuzzLine(pattern string) bool {
+	return !strings.ContainsAny(pattern, "\x00\r\n")
+}
+
+// validFuzzPath accepts relative paths without empty, "." and ".." segments.
+func validFuzzPath(path string) bool {
+	if path == "" || strings.ContainsAny(path, "\x00\r\n") {
+		return false
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// validGitFuzzPath additionally rejects paths which can't be checked
+// using git, e.g. paths inside of .git or starting with the pathspec magic ':'.
+func validGitFuzzPath(path string) bool {
+	if !validFuzzPath(path) || strings.HasPrefix(path, ":") {
+		return false
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] < ' ' || path[i] > '~' || path[i] == '\\' {
+			return false
+		}
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".git" {
+			return false
+		}
+	}
+	return true
+}
+
+// gitRepo is a temporary git repository used to check patterns using git check-ignore.
+type gitRepo struct {
+	git string
+	dir string
+}
+
+// newGitRepo initializes a new repository. It skips the test if git is not installed.
+func newGitRepo(f *testing.F) *gitRepo {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		f.Skip("git is not installed")
+	}
+	dir := f.TempDir()
+	if out, err := exec.Command(git, "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		f.Skip("git init failed:", string(out))
+	}
+	return &gitRepo{git: git, dir: dir}
+}
+
+// ignored writes the pattern into the .gitignore of the repository and checks
+// if git ignores the path. Directories are created for that, as git checks
+// the file system to know if a path is a directory.
+// ok is false if git can't check the path.
+func (r *gitRepo) ignored(t *testing.T, pattern, path string, isDir bool) (ignored bool, ok bool) {
+	require.NoError(t, os.WriteFile(filepath.Join(r.dir, ".gitignore"), []byte(pattern+"\n"), 0o644))
+	if isDir {
+		full := filepath.Join(r.dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(full, 0o755); err != nil {
+			return false, false
+		}
+		defer os.RemoveAll(filepath.Join(r.dir, strings.SplitN(path, "/", 2)[0]))
+	}
+
+	cmd := exec.Command(r.git, "-C", r.dir,
+		"-c", "core.excludesFile=",
+		"-c", "core.ignoreCase=false",
+		"check-ignore", "--no-index", "-q", "--", path)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		return false, false
+	}
+	return err == nil, true
+}
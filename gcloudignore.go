@@ -0,0 +1,44 @@
+package nogo
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// gcloudIncludeDirective includes the rules of another file in a .gcloudignore file.
+const gcloudIncludeDirective = "#!include:"
+
+// resolveIncludes replaces the include directives of the ignore file with the
+// content of the included files, if the dialect supports them.
+func (d Dialect) resolveIncludes(fsys fs.FS, name string, data []byte) ([]byte, error) {
+	if d != DialectGcloudignore || !bytes.Contains(data, []byte(gcloudIncludeDirective)) {
+		return data, nil
+	}
+
+	var result bytes.Buffer
+	for _, line := range splitLines(data) {
+		if !strings.HasPrefix(line, gcloudIncludeDirective) {
+			result.WriteString(line + "\n")
+			continue
+		}
+
+		// Like gcloud, the includes of included files are not resolved.
+		included := line[len(gcloudIncludeDirective):]
+		if strings.Contains(included, "/") {
+			return nil, fmt.Errorf("gcloudignore: %s: may only include files in the same directory", line)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(path.Dir(name), included))
+		if err != nil {
+			// Not wrapped, as a missing ignore file is no error for AddFromFS,
+			// but a missing included file is.
+			return nil, fmt.Errorf("gcloudignore: %s: %v", line, err)
+		}
+		result.Write(bytes.TrimPrefix(content, utf8BOM))
+		result.WriteString("\n")
+	}
+	return result.Bytes(), nil
+}
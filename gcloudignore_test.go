@@ -0,0 +1,55 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialectGcloudignore(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gcloudignore":     {Data: []byte("/.gcloudignore\n#!include:.gitignore\n!keep.log\n")},
+		".gitignore":        {Data: []byte("*.log\r\n#!include:other\n/build\n")},
+		"other":             {Data: []byte("other.txt")},
+		"sub/.gcloudignore": {Data: []byte("#!include:.ignore")},
+		"sub/.ignore":       {Data: []byte("/tmp")},
+	}
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		n := New(WithDialect(DialectGcloudignore), WithBackend(backend))
+		require.NoError(t, n.AddFromFS(fsys, ".gcloudignore"))
+
+		for path, want := range map[string]bool{
+			".gcloudignore": true,
+			"a.log":         true,
+			"keep.log":      false,
+			"build":         true,
+			"sub/build":     false,
+			// Includes of included files are not resolved.
+			"other.txt": false,
+			"sub/tmp":   true,
+			"tmp":       false,
+		} {
+			assert.Equal(t, want, n.Match(path, false), "%v: %s", backend, path)
+		}
+	}
+
+	t.Run("other dialects", func(t *testing.T) {
+		n := New()
+		require.NoError(t, n.AddFile(fsys, ".gcloudignore"))
+		assert.False(t, n.Match("a.log", false))
+	})
+
+	t.Run("invalid includes", func(t *testing.T) {
+		for _, data := range []string{"#!include:missing", "#!include:sub/.ignore"} {
+			fsys := fstest.MapFS{
+				".gcloudignore": {Data: []byte(data)},
+				"sub/.ignore":   {Data: []byte("a")},
+			}
+			n := New(WithDialect(DialectGcloudignore))
+			assert.Error(t, n.AddFromFS(fsys, ".gcloudignore"), data)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package nogo
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// exportIgnoreAttribute is the attribute of .gitattributes files which
+// excludes paths from archives created by "git archive".
+const exportIgnoreAttribute = "export-ignore"
+
+// ForArchive creates a Matcher which ignores everything excluded from an
+// archive of the fsys, like "git archive" does it for a working tree: paths
+// ignored by .gitignore files and paths with the export-ignore attribute of
+// .gitattributes files. See DialectExportIgnore for the attributes.
+//
+// The options are used for both, except that the .gitattributes files are
+// always loaded using DialectExportIgnore.
+func ForArchive(fsys fs.FS, opts ...Option) (Matcher, error) {
+	ignore, err := ForFS(fsys, ".gitignore", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	attributeOpts := append(append([]Option{}, opts...), WithDialect(DialectExportIgnore))
+	exportIgnore, err := ForFS(fsys, ".gitattributes", attributeOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return Union(ignore, exportIgnore), nil
+}
+
+// compileExportIgnore compiles a single line of a .gitattributes file.
+// A path is ignored if the export-ignore attribute is set. Unsetting it,
+// setting it to a value or making it unspecified results in a negated rule.
+// Lines without the attribute don't result in any rule.
+func compileExportIgnore(prefix string, line string) ([]Rule, error) {
+	pattern, attributes, ok, err := splitAttributesLine(line)
+	if err != nil {
+		return nil, &PatternError{Pattern: line, Cause: err}
+	}
+	// Git ignores negative patterns in .gitattributes files with a warning.
+	if !ok || strings.HasPrefix(pattern, "!") {
+		return nil, nil
+	}
+
+	set, found := false, false
+	for _, attribute := range attributes {
+		switch attribute {
+		case exportIgnoreAttribute:
+			set, found = true, true
+		case "-" + exportIgnoreAttribute, "!" + exportIgnoreAttribute:
+			set, found = false, true
+		default:
+			if strings.HasPrefix(attribute, exportIgnoreAttribute+"=") {
+				set, found = false, true
+			}
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	skip, rule, err := Compile(prefix, patternLine(pattern, !set))
+	if err != nil || skip {
+		return nil, err
+	}
+	return []Rule{rule}, nil
+}
+
+// splitAttributesLine splits a line of a .gitattributes file into the pattern
+// and the attributes. ok is false for empty lines, comments and macro
+// definitions. Like in git, the pattern may be quoted using C-style quoting.
+func splitAttributesLine(line string) (pattern string, attributes []string, ok bool, err error) {
+	line = strings.TrimLeft(line, " \t\r\n")
+	if line == "" || line[0] == '#' || strings.HasPrefix(line, "[attr]") {
+		return "", nil, false, nil
+	}
+
+	rest := ""
+	if line[0] == '"' {
+		end := quotedEnd(line)
+		if end < 0 {
+			return "", nil, false, strconv.ErrSyntax
+		}
+		pattern, err = strconv.Unquote(line[:end+1])
+		if err != nil {
+			return "", nil, false, err
+		}
+		rest = line[end+1:]
+	} else {
+		end := strings.IndexAny(line, " \t\r\n")
+		if end < 0 {
+			end = len(line)
+		}
+		pattern, rest = line[:end], line[end:]
+	}
+	return pattern, strings.Fields(rest), pattern != "", nil
+}
+
+// quotedEnd returns the index of the quote which closes the quoted string at
+// the start of s, or -1 if it isn't closed.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialectExportIgnore(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "export-ignore",
+			data: "# comment\n*.go text eol=lf\n/testdata export-ignore\n.github/ export-ignore\n*.bin binary export-ignore\n",
+			want: map[string]bool{
+				"main.go":          false,
+				"testdata":         true,
+				"testdata/a.txt":   true,
+				"x/testdata":       false,
+				".github/ci.yml":   true,
+				"img/logo.bin":     true,
+				"img/logo.png":     false,
+				"docs/testdata.go": false,
+			},
+		},
+		{
+			name: "unset, unspecified and values",
+			data: "*.txt export-ignore\nkeep.txt -export-ignore\nkeep2.txt !export-ignore\nvalue.txt export-ignore=yes\n",
+			want: map[string]bool{
+				"a.txt":     true,
+				"keep.txt":  false,
+				"keep2.txt": false,
+				"value.txt": false,
+			},
+		},
+		{
+			name: "last attribute of the line wins",
+			data: "a.txt export-ignore -export-ignore\nb.txt -export-ignore export-ignore\n",
+			want: map[string]bool{
+				"a.txt": false,
+				"b.txt": true,
+			},
+		},
+		{
+			name: "quoted pattern",
+			data: "\"with space.txt\" export-ignore\n\"\\303\\244.txt\"\texport-ignore\n",
+			want: map[string]bool{
+				"with space.txt": true,
+				"with":           false,
+				"ä.txt":          true,
+			},
+		},
+		{
+			name: "macros and negative patterns are skipped",
+			data: "[attr]hidden export-ignore\n*.txt export-ignore\n!a.txt -export-ignore\n",
+			want: map[string]bool{
+				"a.txt":  true,
+				"hidden": false,
+			},
+		},
+		{
+			name:    "unclosed quote",
+			data:    "\"a.txt export-ignore\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := DialectExportIgnore.CompileAll("", []byte(tt.data))
+			if tt.wantErr {
+				var patternErr *PatternError
+				assert.ErrorAs(t, err, &patternErr)
+				return
+			}
+			require.NoError(t, err)
+
+			n := New()
+			n.AddRules(rules...)
+
+			for path, want := range tt.want {
+				assert.Equal(t, want, n.Match(path, path == "testdata" || path == "x/testdata"), path)
+			}
+		})
+	}
+}
+
+func TestForArchive(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":          {Data: []byte("*.log\n")},
+		".gitattributes":      {Data: []byte("/tests export-ignore\n*.md export-ignore\n")},
+		"README.md":           {},
+		"main.go":             {},
+		"debug.log":           {},
+		"tests/main_test.go":  {},
+		"docs/.gitattributes": {Data: []byte("*.md -export-ignore\n")},
+		"docs/guide.md":       {},
+		"docs/old.log":        {},
+	}
+
+	m, err := ForArchive(fsys)
+	require.NoError(t, err)
+
+	var paths []string
+	err = WalkDirMatcher(fsys, ".", m, func(path string, d fs.DirEntry, err error) error {
+		paths = append(paths, path)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitattributes", ".gitignore", "docs", "docs/.gitattributes", "docs/guide.md", "main.go"}, paths)
+}
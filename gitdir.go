@@ -0,0 +1,185 @@
+package nogo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithGitDir lets ForFS and ForDir find the git directory of the repository
+// and load the "info/exclude" file of it. Its rules apply to the whole
+// repository and have a lower precedence than all ignore files, like in git.
+// If the git directory is inside of the file system but not named ".git",
+// e.g. for a bare repository checked out using "gitdir: .bare", it is
+// ignored as well.
+//
+// See ResolveGitDir for how the git directory is found. Worktrees share the
+// "info/exclude" of the main repository, which is found using their "commondir"
+// file. Paths outside of the file system are read from the OS file system if
+// they are absolute, or if the root of the file system is known, as for ForDir.
+func WithGitDir() Option {
+	return optionFunc(func(n *NoGo) {
+		n.gitDir = true
+	})
+}
+
+// ResolveGitDir returns the git directory of the repository at the root of fsys:
+//   - The value of $GIT_DIR if it is a relative path inside of fsys.
+//   - The directory ".git".
+//   - The directory a ".git" file points to using a "gitdir: <path>" line,
+//     as used by submodules, worktrees and bare repositories with a separate
+//     work tree.
+//
+// The result is a path of fsys or, if it points outside of it, an absolute
+// path of the OS file system. If there is no git directory, the error
+// wraps fs.ErrNotExist.
+func ResolveGitDir(fsys fs.FS) (string, error) {
+	location, err := gitDirResolver{fsys: fsys}.gitDir()
+	return location.path, err
+}
+
+// gitLocation is a path inside of the fs.FS, or of the OS file system if os is set.
+type gitLocation struct {
+	path string
+	os   bool
+}
+
+// gitDirResolver finds the git directory of a repository.
+type gitDirResolver struct {
+	fsys fs.FS
+
+	// root is the OS path of the root of fsys, or "" if it is not known.
+	root string
+}
+
+// gitDir resolves the git directory as described by ResolveGitDir.
+func (r gitDirResolver) gitDir() (gitLocation, error) {
+	if gitDir := gitDirPath(os.Getenv("GIT_DIR")); gitDir != "" {
+		return gitLocation{path: gitDir}, nil
+	}
+
+	dotGit := gitLocation{path: ".git"}
+	info, err := r.stat(dotGit)
+	if err != nil {
+		return gitLocation{}, err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	data, err := r.readFile(dotGit)
+	if err != nil {
+		return gitLocation{}, err
+	}
+	line := strings.TrimSpace(string(bytes.SplitN(data, []byte("\n"), 2)[0]))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return gitLocation{}, fmt.Errorf("invalid .git file: %q", line)
+	}
+	return r.resolve(gitLocation{path: "."}, strings.TrimSpace(strings.TrimPrefix(line, "gitdir:")))
+}
+
+// commonDir returns the directory which contains the files shared by all
+// worktrees, such as "info/exclude". It is the git directory itself, except
+// for worktrees which point to it using a "commondir" file.
+func (r gitDirResolver) commonDir(gitDir gitLocation) (gitLocation, error) {
+	commondir, err := r.resolve(gitDir, "commondir")
+	if err != nil {
+		return gitLocation{}, err
+	}
+	data, err := r.readFile(commondir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return gitDir, nil
+	}
+	if err != nil {
+		return gitLocation{}, err
+	}
+	return r.resolve(gitDir, strings.TrimSpace(string(data)))
+}
+
+// resolve returns the location of p relative to base.
+func (r gitDirResolver) resolve(base gitLocation, p string) (gitLocation, error) {
+	if filepath.IsAbs(p) {
+		return gitLocation{path: filepath.Clean(p), os: true}, nil
+	}
+	if base.os {
+		return gitLocation{path: filepath.Join(base.path, p), os: true}, nil
+	}
+
+	joined := path.Join(base.path, filepath.ToSlash(p))
+	if fs.ValidPath(joined) {
+		return gitLocation{path: joined}, nil
+	}
+	if r.root == "" {
+		return gitLocation{}, fmt.Errorf("the git directory %q is outside of the file system", p)
+	}
+	return gitLocation{path: filepath.Join(r.root, filepath.FromSlash(joined)), os: true}, nil
+}
+
+func (r gitDirResolver) stat(l gitLocation) (fs.FileInfo, error) {
+	if l.os {
+		return os.Stat(l.path)
+	}
+	return fs.Stat(r.fsys, l.path)
+}
+
+func (r gitDirResolver) readFile(l gitLocation) ([]byte, error) {
+	if l.os {
+		return os.ReadFile(l.path)
+	}
+	return fs.ReadFile(r.fsys, l.path)
+}
+
+// loadGitDir loads the "info/exclude" of the git directory as described by
+// WithGitDir. It does nothing if there is no git directory.
+func (n *NoGo) loadGitDir(fsys fs.FS, root string) error {
+	start := time.Now()
+	r := gitDirResolver{fsys: fsys, root: root}
+	gitDir, err := r.gitDir()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var rules []Rule
+	if !gitDir.os && gitDir.path != ".git" {
+		_, rule, err := Compile("", "/"+escapeGlob(gitDir.path)+"/")
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	common, err := r.commonDir(gitDir)
+	if err != nil {
+		return err
+	}
+	exclude, err := r.resolve(common, "info/exclude")
+	if err != nil {
+		return err
+	}
+	data, err := r.readFile(exclude)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	excludeRules, err := n.dialect.CompileAll("", data)
+	if err != nil {
+		return patternErrorAt(err, exclude.path, 0)
+	}
+	rules = append(rules, excludeRules...)
+
+	// The rules have the lowest precedence, even if ignore files of the root
+	// were already loaded.
+	n.groups = append([]group{{rules: rules, source: exclude.path}}, n.groups...)
+	n.logRules(n.groups[0])
+	n.loaded(exclude.path, start)
+	n.changed()
+	return nil
+}
@@ -0,0 +1,177 @@
+package nogo
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveGitDir(t *testing.T) {
+	abs := t.TempDir()
+
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		gitDir  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "directory",
+			fsys: fstest.MapFS{".git/HEAD": {}},
+			want: ".git",
+		},
+		{
+			name: "bare repository",
+			fsys: fstest.MapFS{".git": {Data: []byte("gitdir: .bare\n")}, ".bare/HEAD": {}},
+			want: ".bare",
+		},
+		{
+			name: "absolute",
+			fsys: fstest.MapFS{".git": {Data: []byte("gitdir: " + abs + "\n")}},
+			want: abs,
+		},
+		{
+			name: "GIT_DIR",
+			fsys: fstest.MapFS{"repo.git/HEAD": {}},
+			// "../" is resolved by Clean.
+			gitDir: "repo.git/x/..",
+			want:   "repo.git",
+		},
+		{
+			name:    "outside",
+			fsys:    fstest.MapFS{".git": {Data: []byte("gitdir: ../.git/modules/sub\n")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			fsys:    fstest.MapFS{".git": {Data: []byte("something else\n")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_DIR", tt.gitDir)
+			got, err := ResolveGitDir(tt.fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		t.Setenv("GIT_DIR", "")
+		_, err := ResolveGitDir(fstest.MapFS{})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestWithGitDir(t *testing.T) {
+	t.Setenv("GIT_DIR", "")
+
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		ignored []string
+		kept    []string
+	}{
+		{
+			name: "directory",
+			fsys: fstest.MapFS{
+				".git/info/exclude": {Data: []byte("*.log\n/local\n")},
+				".gitignore":        {Data: []byte("!keep.log\n")},
+			},
+			ignored: []string{"debug.log", "local", "sub/debug.log"},
+			kept:    []string{"keep.log", "sub/local", "main.go"},
+		},
+		{
+			name: "bare repository",
+			fsys: fstest.MapFS{
+				".git":               {Data: []byte("gitdir: .bare\n")},
+				".bare/info/exclude": {Data: []byte("*.log\n")},
+			},
+			ignored: []string{"debug.log", ".bare/HEAD"},
+			kept:    []string{"main.go"},
+		},
+		{
+			name: "worktree",
+			fsys: fstest.MapFS{
+				".git":                             {Data: []byte("gitdir: main/.git/worktrees/wt\n")},
+				"main/.git/worktrees/wt/commondir": {Data: []byte("../..\n")},
+				"main/.git/info/exclude":           {Data: []byte("*.log\n")},
+			},
+			ignored: []string{"debug.log"},
+			kept:    []string{"main.go"},
+		},
+		{
+			name: "no exclude file",
+			fsys: fstest.MapFS{
+				".git/HEAD": {},
+			},
+			kept: []string{"main.go"},
+		},
+		{
+			name: "no repository",
+			fsys: fstest.MapFS{},
+			kept: []string{"main.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, lazy := range []bool{false, true} {
+				opts := []Option{WithGitDir()}
+				if lazy {
+					opts = append(opts, WithLazyLoading())
+				}
+				n, err := ForFS(tt.fsys, ".gitignore", opts...)
+				require.NoError(t, err)
+
+				for _, path := range tt.ignored {
+					assert.True(t, n.Match(path, false), "%s should be ignored (lazy %v)", path, lazy)
+				}
+				for _, path := range tt.kept {
+					assert.False(t, n.Match(path, false), "%s should not be ignored (lazy %v)", path, lazy)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		fsys := fstest.MapFS{".git/info/exclude": {Data: []byte("[a-z\n")}}
+		_, err := ForFS(fsys, ".gitignore", WithGitDir())
+		var patternErr *PatternError
+		require.ErrorAs(t, err, &patternErr)
+		assert.Equal(t, ".git/info/exclude", patternErr.File)
+	})
+}
+
+func TestForDir_WithGitDir(t *testing.T) {
+	t.Setenv("GIT_DIR", "")
+	t.Setenv("GIT_CEILING_DIRECTORIES", "")
+
+	// A submodule points to the git directory inside of the parent repository.
+	root := t.TempDir()
+	writeTestFile(t, root, ".git/info/exclude", "*.parent\n")
+	writeTestFile(t, root, ".git/modules/sub/info/exclude", "*.log\n")
+	writeTestFile(t, root, "sub/.git", "gitdir: ../.git/modules/sub\n")
+
+	m, err := ForDir(filepath.Join(root, "sub"), WithGitDir())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "sub"), m.Root)
+	assert.True(t, m.Match("debug.log", false))
+	assert.False(t, m.Match("a.parent", false))
+}
+
+func writeTestFile(t *testing.T, root, name, content string) {
+	full := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+	require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+}
@@ -0,0 +1,287 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// Backend defines how the rules are evaluated.
+type Backend int
+
+const (
+	// RegexpBackend evaluates the Regexp of each rule.
+	// This is the default.
+	RegexpBackend Backend = iota
+
+	// GlobBackend evaluates the patterns directly, segment by segment,
+	// using the primitives of Rule.Matchers.
+	// Rules which have no Pattern are still evaluated using their Regexp.
+	GlobBackend
+)
+
+func (b Backend) String() string {
+	switch b {
+	case RegexpBackend:
+		return "Regexp"
+	case GlobBackend:
+		return "Glob"
+	}
+	return "Unknown"
+}
+
+// WithBackend selects how the rules are evaluated.
+func WithBackend(backend Backend) Option {
+	return optionFunc(func(n *NoGo) {
+		n.backend = backend
+		n.changed()
+	})
+}
+
+// glob is a rule prepared for the GlobBackend.
+type glob struct {
+	prefix string
+
+	// segments contains the primitives of each path segment of the pattern.
+	segments [][]Primitive
+}
+
+// newGlob prepares the rule for the GlobBackend.
+// It returns nil if the rule has no pattern.
+func newGlob(rule Rule) *glob {
+	primitives := rule.Matchers()
+	if primitives == nil {
+		return nil
+	}
+
+	g := &glob{}
+	if rule.Prefix != "" {
+		g.prefix = strings.TrimSuffix(rule.Prefix, "/") + "/"
+		// Skip the prefix and its separator.
+		primitives = primitives[2:]
+	}
+
+	segment := []Primitive{}
+	for _, p := range primitives {
+		if p.Kind == PrimitiveSeparator {
+			g.segments = append(g.segments, segment)
+			segment = []Primitive{}
+			continue
+		}
+		segment = append(segment, p)
+	}
+	g.segments = append(g.segments, segment)
+
+	return g
+}
+
+// newGlobs prepares all rules of the groups for the GlobBackend.
+func newGlobs(groups []group) [][]*glob {
+	globs := make([][]*glob, len(groups))
+	for i, g := range groups {
+		globs[i] = make([]*glob, len(g.rules))
+		for j, rule := range g.rules {
+			globs[i][j] = newGlob(rule)
+		}
+	}
+	return globs
+}
+
+// matchRule evaluates the rule using the glob if it is not nil, else using the regexps.
+func matchRule(rule Rule, g *glob, path string) Result {
+	if g == nil {
+		return rule.MatchPath(path)
+	}
+
+	return Result{
+		Found: g.match(path),
+		Rule:  rule,
+	}
+}
+
+// match the path against the glob.
+func (g *glob) match(path string) bool {
+	if !strings.HasPrefix(path, g.prefix) {
+		return false
+	}
+
+	return matchSegments(g.segments, path[len(g.prefix):], false)
+}
+
+// matchSegments matches the remaining segments of the path.
+// end is true if there are no segments left.
+// It does not split the path to avoid allocations.
+func matchSegments(segments [][]Primitive, path string, end bool) bool {
+	if len(segments) == 0 {
+		return end
+	}
+
+	segment := segments[0]
+	if len(segment) == 1 && segment[0].Kind == PrimitiveDoubleStar {
+		// A trailing "/**" matches everything inside.
+		if len(segments) == 1 {
+			return !end
+		}
+
+		// Any other "**" matches zero or more directories.
+		for {
+			if matchSegments(segments[1:], path, end) {
+				return true
+			}
+			if end {
+				return false
+			}
+			_, path, end = nextSegment(path)
+		}
+	}
+
+	if end {
+		return false
+	}
+
+	name, rest, restEnd := nextSegment(path)
+	if !matchSegment(segment, name) {
+		return false
+	}
+	return matchSegments(segments[1:], rest, restEnd)
+}
+
+// nextSegment splits the first segment from the path.
+func nextSegment(path string) (name string, rest string, end bool) {
+	i := strings.IndexByte(path, '/')
+	if i < 0 {
+		return path, "", true
+	}
+	return path[:i], path[i+1:], false
+}
+
+// matchSegment matches a single segment of a path which contains no '/'.
+//
+// Like wildmatch and path.Match, only the position of the last '*' is
+// remembered. If the primitives after it don't match, the '*' consumes one
+// more byte and they are tried again. An earlier '*' never has to consume
+// more, as the last one can take over its bytes. So the running time is
+// at most quadratic, even for patterns with many '*'.
+func matchSegment(primitives []Primitive, name string) bool {
+	pi, ni := 0, 0
+	star, starName := -1, 0
+	for {
+		if pi < len(primitives) {
+			p := primitives[pi]
+			switch p.Kind {
+			case PrimitiveStar, PrimitiveDoubleStar:
+				star, starName = pi, ni
+				pi++
+				continue
+			case PrimitiveLiteral:
+				if strings.HasPrefix(name[ni:], p.Value) {
+					pi, ni = pi+1, ni+len(p.Value)
+					continue
+				}
+			case PrimitiveQuestionMark:
+				// Like git, a '?' matches a single byte, not a whole UTF-8 character.
+				if ni < len(name) {
+					pi, ni = pi+1, ni+1
+					continue
+				}
+			case PrimitiveRange:
+				if ni < len(name) && matchRange(p.Value, name[ni]) != p.Negated {
+					pi, ni = pi+1, ni+1
+					continue
+				}
+			}
+		} else if ni == len(name) {
+			return true
+		}
+
+		// Let the last '*' consume one more byte and try again.
+		if star < 0 || starName >= len(name) {
+			return false
+		}
+		starName++
+		pi, ni = star+1, starName
+	}
+}
+
+// matchRange checks if the byte is in the fnmatch range (e.g. "a-zA-Z_").
+// A '/' is never in a range.
+//
+// Like git, ranges work on bytes. So a range containing non-ASCII characters
+// contains each byte of their UTF-8 encoding.
+func matchRange(value string, c byte) bool {
+	if c == '/' {
+		return false
+	}
+
+	for i := 0; i < len(value); {
+		if end := classEnd(value, i); end >= 0 {
+			if matchClass(value[i+2:end-1], c) {
+				return true
+			}
+			i = end + 1
+			continue
+		}
+
+		start, size := nextRangeChar(value[i:])
+		i += size
+
+		// A '-' between two characters defines a range.
+		end := start
+		if i+1 < len(value) && value[i] == '-' {
+			end, size = nextRangeChar(value[i+1:])
+			i += 1 + size
+		}
+
+		if start <= c && c <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// matchClass checks if the byte is in the POSIX character class with the name
+// (e.g. "digit"), like the classes of Go regexps. Only ASCII bytes are in a class.
+func matchClass(name string, c byte) bool {
+	isLower := 'a' <= c && c <= 'z'
+	isUpper := 'A' <= c && c <= 'Z'
+	isDigit := '0' <= c && c <= '9'
+	isPunct := '!' <= c && c <= '~' && !isLower && !isUpper && !isDigit
+
+	switch name {
+	case "alnum":
+		return isLower || isUpper || isDigit
+	case "alpha":
+		return isLower || isUpper
+	case "ascii":
+		return c < 0x80
+	case "blank":
+		return c == ' ' || c == '\t'
+	case "cntrl":
+		return c < ' ' || c == 0x7f
+	case "digit":
+		return isDigit
+	case "graph":
+		return '!' <= c && c <= '~'
+	case "lower":
+		return isLower
+	case "print":
+		return ' ' <= c && c <= '~'
+	case "punct":
+		return isPunct
+	case "space":
+		return c == ' ' || '\t' <= c && c <= '\r'
+	case "upper":
+		return isUpper
+	case "word":
+		return isLower || isUpper || isDigit || c == '_'
+	case "xdigit":
+		return isDigit || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+	}
+	return false
+}
+
+// nextRangeChar decodes the first, maybe escaped, byte of a range.
+func nextRangeChar(value string) (c byte, size int) {
+	if value[0] == '\\' && len(value) > 1 {
+		return value[1], 2
+	}
+	return value[0], 1
+}
@@ -0,0 +1,139 @@
+package nogo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlob_match(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "*.go", path: "main.go", want: true},
+		{pattern: "*.go", path: "a/b/main.go", want: true},
+		{pattern: "*.go", path: "main.goo", want: false},
+		{pattern: "/build", path: "build", want: true},
+		{pattern: "/build", path: "a/build", want: false},
+		{prefix: "a/folder", pattern: "/aFile", path: "a/folder/aFile", want: true},
+		{prefix: "a/folder", pattern: "/aFile", path: "a/folder/sub/aFile", want: false},
+		{prefix: "a/folder", pattern: "aFile", path: "a/folder/sub/aFile", want: true},
+		{prefix: "a/folder", pattern: "aFile", path: "a/folderaFile", want: false},
+		{prefix: "a/folder", pattern: "aFile", path: "a/folder", want: false},
+		{pattern: "a/**/b", path: "a/b", want: true},
+		{pattern: "a/**/b", path: "a/x/y/b", want: true},
+		{pattern: "a/**/b", path: "ab/x/b", want: false},
+		{pattern: "abc/**", path: "abc/x/y", want: true},
+		{pattern: "abc/**", path: "abc", want: false},
+		{pattern: "foo?", path: "foox", want: true},
+		{pattern: "foo?", path: "fooä", want: false},
+		{pattern: "foo??", path: "fooä", want: true},
+		{pattern: "foo?", path: "foo", want: false},
+		{pattern: "foo?", path: "fooxy", want: false},
+		{pattern: "file[a-z].txt", path: "filef.txt", want: true},
+		{pattern: "file[a-z].txt", path: "fileF.txt", want: false},
+		{pattern: "file[!a-z].txt", path: "fileF.txt", want: true},
+		{pattern: "file[!a-z].txt", path: "filef.txt", want: false},
+		{pattern: "file[-_].txt", path: "file_.txt", want: true},
+		{pattern: `file[\]].txt`, path: "file].txt", want: true},
+		{pattern: "file[α-ω].txt", path: "fileβ.txt", want: false},
+		{pattern: "file[α-ω][α-ω].txt", path: "fileβ.txt", want: true},
+		{pattern: "a*b*c", path: "aXXbYYc", want: true},
+		{pattern: "a*b*c", path: "aXXbYY", want: false},
+		{pattern: "ä*", path: "äöü", want: true},
+		{pattern: "*a*b", path: "xaab", want: true},
+		{pattern: "*ab*ab", path: "abxabab", want: true},
+		{pattern: "a*", path: "a", want: true},
+		{pattern: "*?", path: "", want: false},
+		{pattern: "**b", path: "ab", want: true},
+		{pattern: "*[0-9]x", path: "a1x", want: true},
+		{pattern: "*[0-9]x", path: "a1y", want: false},
+		{pattern: "[[:digit:]]x", path: "1x", want: true},
+		{pattern: "[[:digit:]]x", path: "ax", want: false},
+		{pattern: "[![:alpha:]_]", path: "1", want: true},
+		{pattern: "[![:alpha:]_]", path: "_", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.path, func(t *testing.T) {
+			_, rule, err := Compile(tt.prefix, tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, newGlob(rule).match(tt.path))
+		})
+	}
+}
+
+func TestGlob_match_manyStars(t *testing.T) {
+	_, rule, err := Compile("", strings.Repeat("*a", 10)+"*b")
+	require.NoError(t, err)
+	g := newGlob(rule)
+	path := strings.Repeat("a", 40)
+
+	done := make(chan bool)
+	go func() {
+		done <- g.match(path)
+	}()
+	select {
+	case match := <-done:
+		assert.False(t, match)
+	case <-time.After(5 * time.Second):
+		t.Fatal("matching took too long")
+	}
+}
+
+func BenchmarkGlob_match_manyStars(b *testing.B) {
+	_, rule, err := Compile("", strings.Repeat("*a", 8)+"*b")
+	require.NoError(b, err)
+	g := newGlob(rule)
+	path := strings.Repeat("a", 40)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.match(path)
+	}
+}
+
+func TestNoGo_GlobBackend(t *testing.T) {
+	t.Run("same results as the regexp backend", func(t *testing.T) {
+		n := New(WithBackend(GlobBackend))
+		n.groups = TestFSGroups
+		n.changed()
+
+		for path, tt := range TestFSData {
+			gotMatch, gotBecause := n.MatchBecause(path, tt.isDir)
+			wantMatch, wantBecause := (&NoGo{groups: TestFSGroups}).MatchBecause(path, tt.isDir)
+			assert.Equal(t, wantMatch, gotMatch, path)
+			assert.Equal(t, wantBecause, gotBecause, path)
+		}
+	})
+
+	t.Run("corpus", func(t *testing.T) {
+		for _, c := range Corpus() {
+			n := New(WithBackend(GlobBackend))
+			n.AddRules(MustCompileAll("", []byte(c.Pattern))...)
+			assert.Equal(t, c.Ignored, n.Match(c.Path, c.IsDir), c.Pattern+"|"+c.Path)
+		}
+	})
+
+	t.Run("compiled", func(t *testing.T) {
+		n := New(WithBackend(GlobBackend))
+		n.AddRules(MustCompileAll("", []byte("*.go\n!main.go"))...)
+		n.Compile()
+
+		assert.True(t, n.Match("a/b.go", false))
+		assert.False(t, n.Match("a/main.go", false))
+	})
+
+	t.Run("rules without pattern use the regexp", func(t *testing.T) {
+		n := New(WithBackend(GlobBackend))
+		n.groups = []group{{rules: []Rule{{Regexp: TestFSGroups[0].rules[0].Regexp}}}}
+		n.changed()
+
+		assert.True(t, n.Match("a/globallyIgnored", false))
+	})
+}
@@ -0,0 +1,40 @@
+package nogo
+
+import (
+	"io/fs"
+)
+
+// Glob returns all files of the fsys which are matched by the patterns,
+// in lexical order. It is the inverse of ignoring: the patterns use the
+// gitignore syntax and decide which files are listed, like the pathspecs of git:
+//
+//	files, err := nogo.Glob(fsys, "src/**/*.go", "!src/**/*_test.go")
+//
+// The same as for NewAllowList, a pattern which matches a directory lists all
+// files inside of it and directories are only read if the literal start of a
+// pattern may match anything inside of them. So "src/**/*.go" doesn't read
+// any directory outside of "src".
+//
+// Directories are not listed themselves.
+// Invalid patterns result in a *PatternError.
+func Glob(fsys fs.FS, patterns ...string) ([]string, error) {
+	n := NewAllowList()
+	if err := n.AddPatterns("", patterns); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
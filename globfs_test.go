@@ -0,0 +1,100 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var globTestFiles = map[string]string{
+	"go.mod":                 "",
+	"README.md":              "",
+	"src/main.go":            "",
+	"src/main_test.go":       "",
+	"src/app/app.go":         "",
+	"src/app/app_test.go":    "",
+	"src/app/README.md":      "",
+	"build/out/main":         "",
+	"build/out/main.go":      "",
+	"docs/guide/index.md":    "",
+	"vendor/lib/lib.go":      "",
+	"vendor/lib/lib_test.go": "",
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name:     "double star",
+			patterns: []string{"src/**/*.go"},
+			want:     []string{"src/app/app.go", "src/app/app_test.go", "src/main.go", "src/main_test.go"},
+		},
+		{
+			name:     "negation",
+			patterns: []string{"src/**/*.go", "!*_test.go"},
+			want:     []string{"src/app/app.go", "src/main.go"},
+		},
+		{
+			name:     "unanchored",
+			patterns: []string{"*.md"},
+			want:     []string{"README.md", "docs/guide/index.md", "src/app/README.md"},
+		},
+		{
+			name:     "anchored",
+			patterns: []string{"/README.md"},
+			want:     []string{"README.md"},
+		},
+		{
+			name:     "directory",
+			patterns: []string{"build/"},
+			want:     []string{"build/out/main", "build/out/main.go"},
+		},
+		{
+			name:     "several",
+			patterns: []string{"go.mod", "vendor/**/lib.go"},
+			want:     []string{"go.mod", "vendor/lib/lib.go"},
+		},
+		{
+			name:     "nothing",
+			patterns: []string{"*.rs"},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Glob(newTestMapFS(globTestFiles), tt.patterns...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, files)
+		})
+	}
+}
+
+func TestGlob_Prune(t *testing.T) {
+	fsys := ForbiddenFS{
+		MapFS: newTestMapFS(globTestFiles),
+		NotExpected: map[string]struct{}{
+			"build":  {},
+			"docs":   {},
+			"vendor": {},
+		},
+	}
+	fsys.MapFS["build"] = &fstest.MapFile{Mode: fs.ModeDir}
+	fsys.MapFS["docs"] = &fstest.MapFile{Mode: fs.ModeDir}
+	fsys.MapFS["vendor"] = &fstest.MapFile{Mode: fs.ModeDir}
+
+	files, err := Glob(fsys, "src/**/*.go", "/go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "src/app/app.go", "src/app/app_test.go", "src/main.go", "src/main_test.go"}, files)
+}
+
+func TestGlob_InvalidPattern(t *testing.T) {
+	_, err := Glob(newTestMapFS(globTestFiles), "src/[a")
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
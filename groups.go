@@ -0,0 +1,197 @@
+package nogo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// GroupInfo describes a group of rules, e.g. the rules of one ignore file.
+type GroupInfo struct {
+	// Prefix is the folder the rules are relative to.
+	// It is "" for the root.
+	Prefix string
+
+	// Source is the ignore file the rules were loaded from, e.g. by AddFile
+	// or AddBundle. It is "" for rules added by AddRules or AddPatterns.
+	Source string
+
+	// Rules contains the rules of the group in the order they were added.
+	Rules []Rule
+
+	// Tag is the tag of the rules if they were added by AddRulesTagged.
+	Tag string
+}
+
+// Groups returns all groups of rules in the order they are checked,
+// which is sorted by the depth of the prefix.
+// Each ignore file is one group and each rule added by AddRules is an
+// own group with the Prefix of the rule.
+//
+// The returned rules are a copy, so modifying them doesn't change NoGo.
+func (n *NoGo) Groups() []GroupInfo {
+	groups := make([]GroupInfo, len(n.groups))
+	for i, g := range n.groups {
+		groups[i] = GroupInfo{
+			Prefix: g.prefix,
+			Source: g.source,
+			Rules:  append([]Rule(nil), g.rules...),
+			Tag:    g.tag,
+		}
+	}
+	return groups
+}
+
+// RuleInfo is a rule together with the ignore file it was loaded from.
+type RuleInfo struct {
+	Rule
+
+	// Source is the same as GroupInfo.Source.
+	Source string
+}
+
+// Rules returns all rules in the order they are checked.
+// A later rule takes precedence over an earlier one.
+func (n *NoGo) Rules() []RuleInfo {
+	return n.rulesFor(func(g group) bool { return true })
+}
+
+// RulesFor returns the rules which are checked for the entries of dir, which
+// are the rules of dir and all of its parents, e.g. to show the effective
+// ignore rules of a directory. Use "" for the root.
+func (n *NoGo) RulesFor(dir string) []RuleInfo {
+	dir = n.groupPrefix(dir)
+	if dir == "." {
+		dir = ""
+	}
+	return n.rulesFor(func(g group) bool {
+		return isPathInside(dir, g.prefix)
+	})
+}
+
+func (n *NoGo) rulesFor(include func(g group) bool) []RuleInfo {
+	rules := make([]RuleInfo, 0)
+	for _, g := range n.groups {
+		if !include(g) {
+			continue
+		}
+		for _, rule := range g.rules {
+			rules = append(rules, RuleInfo{Rule: rule, Source: g.source})
+		}
+	}
+	return rules
+}
+
+// RemoveGroup removes all groups with the prefix, e.g. after the ignore file
+// of that folder was deleted. It returns false if there was no such group.
+//
+// Note that this also removes the rules added by AddRules with the same
+// prefix, e.g. DotGitRule for the prefix "".
+func (n *NoGo) RemoveGroup(prefix string) bool {
+	prefix = n.groupPrefix(prefix)
+
+	groups := n.groups[:0]
+	for _, g := range n.groups {
+		if g.prefix != prefix {
+			groups = append(groups, g)
+		}
+	}
+
+	if len(groups) == len(n.groups) {
+		return false
+	}
+
+	// Clear the removed groups so that the garbage collector can free them.
+	for i := len(groups); i < len(n.groups); i++ {
+		n.groups[i] = group{}
+	}
+	n.groups = groups
+	n.changed()
+	return true
+}
+
+// ReplaceGroup replaces all groups with the prefix by a single group with
+// the given rules, e.g. after the ignore file of that folder was changed.
+// The rules should be compiled with the same prefix (see CompileAll).
+//
+// The new group is checked at the position of the first replaced group,
+// so the precedence of the rules doesn't change. If there is no group with
+// the prefix, the new group is added like by AddFile.
+//
+// Like RemoveGroup, this also replaces the rules added by AddRules with the
+// same prefix.
+func (n *NoGo) ReplaceGroup(prefix string, rules []Rule) {
+	prefix = n.groupPrefix(prefix)
+	replacement := group{
+		prefix: prefix,
+		rules:  make([]Rule, len(rules)),
+	}
+	for i, rule := range rules {
+		replacement.rules[i] = byteRegexps(rule)
+	}
+
+	groups := make([]group, 0, len(n.groups)+1)
+	replaced := false
+	for _, g := range n.groups {
+		if g.prefix != prefix {
+			groups = append(groups, g)
+		} else if !replaced {
+			// The rules are most likely loaded from the same file again.
+			replacement.source = g.source
+			n.loaded(g.source, time.Now())
+			groups = append(groups, replacement)
+			n.logRules(replacement)
+			replaced = true
+		}
+	}
+	n.groups = groups
+	if !replaced {
+		n.addGroup(replacement)
+	}
+	n.changed()
+}
+
+// addGroup inserts the group after all groups with a prefix which is not
+// deeper, so the rules of deeper folders always take precedence, independent
+// of the order in which they were added. It returns the index of the group.
+//
+// changed has to be called afterwards.
+func (n *NoGo) addGroup(g group) int {
+	g.prefix = strings.TrimSuffix(g.prefix, "/")
+	depth := prefixDepth(g.prefix)
+	i := len(n.groups)
+	for i > 0 && prefixDepth(n.groups[i-1].prefix) > depth {
+		i--
+	}
+
+	n.groups = append(n.groups, group{})
+	copy(n.groups[i+1:], n.groups[i:])
+	n.groups[i] = g
+	n.logRules(g)
+	return i
+}
+
+// sortGroups sorts the groups by the depth of their prefix.
+// Groups with the same depth keep their order.
+func sortGroups(groups []group) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return prefixDepth(groups[i].prefix) < prefixDepth(groups[j].prefix)
+	})
+}
+
+// prefixDepth returns the number of folders of the prefix.
+func prefixDepth(prefix string) int {
+	if prefix == "" {
+		return 0
+	}
+	return strings.Count(prefix, "/") + 1
+}
+
+// groupPrefix converts the prefix to the form used by the groups.
+func (n *NoGo) groupPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if n.ignoreCase {
+		prefix = lowerASCIIString(prefix)
+	}
+	return prefix
+}
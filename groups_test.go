@@ -0,0 +1,242 @@
+package nogo
+
+import (
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGroupsTestNoGo(t *testing.T) *NoGo {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n/build")},
+		"sub/.gitignore": {Data: []byte("!keep.log\n/out")},
+		"sub/a/b.txt":    {},
+	}
+
+	n := New(DotGitRule)
+	require.NoError(t, n.AddFromFS(fsys, ".gitignore"))
+	return n
+}
+
+func groupPrefixes(n *NoGo) []string {
+	var prefixes []string
+	for _, g := range n.Groups() {
+		prefixes = append(prefixes, g.Prefix)
+	}
+	return prefixes
+}
+
+func TestNoGo_Groups(t *testing.T) {
+	n := newGroupsTestNoGo(t)
+
+	groups := n.Groups()
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"", "", "sub"}, groupPrefixes(n))
+	assert.Equal(t, []Rule{DotGitRule}, groups[0].Rules)
+	require.Len(t, groups[2].Rules, 2)
+	assert.Equal(t, "!keep.log", groups[2].Rules[0].Pattern)
+	assert.Equal(t, []string{"", ".gitignore", "sub/.gitignore"}, []string{groups[0].Source, groups[1].Source, groups[2].Source})
+
+	// The rules are a copy.
+	groups[2].Rules[0] = MustCompileAll("sub", []byte("keep.log"))[0]
+	assert.False(t, n.Match("sub/keep.log", false))
+}
+
+func TestNoGo_Rules(t *testing.T) {
+	n := newGroupsTestNoGo(t)
+
+	var patterns, sources []string
+	for _, rule := range n.Rules() {
+		patterns = append(patterns, rule.Pattern)
+		sources = append(sources, rule.Source)
+	}
+	assert.Equal(t, []string{".git", "*.log", "/build", "!keep.log", "/out"}, patterns)
+	assert.Equal(t, []string{"", ".gitignore", ".gitignore", "sub/.gitignore", "sub/.gitignore"}, sources)
+}
+
+func TestNoGo_RulesFor(t *testing.T) {
+	n := newGroupsTestNoGo(t)
+	n.AddRules(MustCompileAll("subway", []byte("*.tmp"))...)
+
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		{dir: "", want: []string{".git", "*.log", "/build"}},
+		{dir: ".", want: []string{".git", "*.log", "/build"}},
+		{dir: "sub", want: []string{".git", "*.log", "/build", "!keep.log", "/out"}},
+		{dir: "/sub/a/", want: []string{".git", "*.log", "/build", "!keep.log", "/out"}},
+		{dir: "subway", want: []string{".git", "*.log", "/build", "*.tmp"}},
+		{dir: "other", want: []string{".git", "*.log", "/build"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			var patterns []string
+			for _, rule := range n.RulesFor(tt.dir) {
+				patterns = append(patterns, rule.Pattern)
+			}
+			assert.Equal(t, tt.want, patterns)
+		})
+	}
+}
+
+func TestNoGo_RemoveGroup(t *testing.T) {
+	n := newGroupsTestNoGo(t)
+	require.False(t, n.Match("sub/keep.log", false))
+
+	assert.True(t, n.RemoveGroup("/sub/"))
+	assert.Equal(t, []string{"", ""}, groupPrefixes(n))
+	assert.True(t, n.Match("sub/keep.log", false))
+	assert.False(t, n.Match("sub/out", true))
+
+	assert.False(t, n.RemoveGroup("sub"))
+	assert.False(t, n.RemoveGroup("other"))
+
+	// All groups of the prefix are removed, including DotGitRule.
+	assert.True(t, n.RemoveGroup(""))
+	assert.Empty(t, n.Groups())
+	assert.False(t, n.Match(".git", true))
+	assert.False(t, n.Match("a.log", false))
+}
+
+func TestNoGo_ReplaceGroup(t *testing.T) {
+	n := New(WithCache(10))
+	n.AddRules(MustCompileAll("", []byte("*.log"))...)
+	n.ReplaceGroup("sub", MustCompileAll("sub", []byte("!keep.log\n/out")))
+	n.AddRules(MustCompileAll("other", []byte("/x"))...)
+
+	assert.False(t, n.Match("sub/keep.log", false))
+	assert.True(t, n.Match("sub/out", true))
+
+	// The replaced group keeps its position, so a later negation still wins.
+	n.ReplaceGroup("", MustCompileAll("", []byte("*.txt")))
+	assert.Equal(t, []string{"", "sub", "other"}, groupPrefixes(n))
+	assert.True(t, n.Match("a.txt", false))
+	assert.True(t, n.Match("sub/a.txt", false))
+	assert.False(t, n.Match("sub/keep.log", false))
+	assert.False(t, n.Match("a.log", false))
+
+	n.ReplaceGroup("sub", nil)
+	assert.Equal(t, []string{"", "sub", "other"}, groupPrefixes(n))
+	assert.False(t, n.Match("sub/out", true))
+
+	t.Run("source is kept", func(t *testing.T) {
+		n := newGroupsTestNoGo(t)
+		n.ReplaceGroup("sub", MustCompileAll("sub", []byte("/out")))
+		assert.Equal(t, "sub/.gitignore", n.Groups()[2].Source)
+	})
+
+	t.Run("rules are not modified", func(t *testing.T) {
+		rules := MustCompileAll("", []byte("README.md"))
+		n := New(WithIgnoreCase())
+		n.ReplaceGroup("", rules)
+		assert.True(t, n.Match("readme.MD", false))
+		assert.Equal(t, "README.md", rules[0].Pattern)
+	})
+}
+
+func TestNoGo_addGroup(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":       {Data: []byte("*.log\n/build")},
+		"sub/.gitignore":   {Data: []byte("!keep.log\nout")},
+		"sub/a/.gitignore": {Data: []byte("!out")},
+		"other/.gitignore": {Data: []byte("*.txt")},
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "a.log", want: true},
+		{path: "sub/keep.log", want: false},
+		{path: "sub/a/keep.log", want: false},
+		{path: "keep.log", want: true},
+		{path: "sub/out", want: true},
+		{path: "sub/a/out", want: false},
+		{path: "other/a.txt", want: true},
+	}
+
+	orders := [][]string{
+		{".gitignore", "sub/.gitignore", "sub/a/.gitignore", "other/.gitignore"},
+		{"sub/a/.gitignore", "sub/.gitignore", "other/.gitignore", ".gitignore"},
+		{"other/.gitignore", "sub/a/.gitignore", ".gitignore", "sub/.gitignore"},
+	}
+	for _, order := range orders {
+		n := New()
+		for _, file := range order {
+			require.NoError(t, n.AddFile(fsys, file))
+		}
+
+		prefixes := groupPrefixes(n)
+		assert.Equal(t, "", prefixes[0], order)
+		assert.Equal(t, "sub/a", prefixes[3], order)
+
+		for _, tt := range tests {
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir), "%v: %s", order, tt.path)
+		}
+	}
+
+	t.Run("same prefix keeps the order", func(t *testing.T) {
+		n := New()
+		n.AddRules(MustCompileAll("sub", []byte("*.log"))...)
+		n.AddRules(MustCompileAll("", []byte("/x"))...)
+		n.AddRules(MustCompileAll("sub", []byte("!keep.log"))...)
+
+		assert.Equal(t, []string{"", "sub", "sub"}, groupPrefixes(n))
+		assert.False(t, n.Match("sub/keep.log", false))
+		assert.True(t, n.Match("sub/a.log", false))
+	})
+
+	t.Run("unmarshal sorts the groups", func(t *testing.T) {
+		unsorted := New()
+		unsorted.groups = []group{
+			{prefix: "sub", rules: MustCompileAll("sub", []byte("!keep.log"))},
+			{prefix: "", rules: MustCompileAll("", []byte("*.log"))},
+		}
+		data, err := unsorted.MarshalBinary()
+		require.NoError(t, err)
+
+		n := New()
+		require.NoError(t, n.UnmarshalBinary(data))
+		assert.Equal(t, []string{"", "sub"}, groupPrefixes(n))
+		assert.False(t, n.Match("sub/keep.log", false))
+	})
+}
+
+func TestNoGo_prefixSegments(t *testing.T) {
+	// The regexp doesn't contain the prefix, so only the prefix decides
+	// which paths the rule applies to.
+	custom := Rule{Prefix: "aFolder", Regexp: []*regexp.Regexp{regexp.MustCompile(`file$`)}}
+	slash := Rule{Prefix: "bFolder/", Regexp: []*regexp.Regexp{regexp.MustCompile(`file$`)}}
+
+	tests := map[string]bool{
+		"aFolder/file":          true,
+		"aFolder/sub/file":      true,
+		"aFolderOther/file":     false,
+		"aFolder.old/file":      false,
+		"bFolder/file":          true,
+		"bFolderOther/file":     false,
+		"cFolder/aFolder/file":  false,
+		"aFolderOther/sub/file": false,
+	}
+
+	for _, compile := range []bool{false, true} {
+		n := New()
+		n.AddRules(custom, slash)
+		if compile {
+			n.Compile()
+		}
+		for path, want := range tests {
+			assert.Equal(t, want, n.Match(path, false), "compiled %v: %v", compile, path)
+		}
+	}
+
+	assert.False(t, custom.MatchPath("aFolderOther/file").Found)
+	assert.True(t, custom.MatchPath("aFolder/file").Found)
+	assert.True(t, slash.MatchPath("bFolder/file").Found)
+}
@@ -0,0 +1,51 @@
+package nogo
+
+import "strings"
+
+// groupTree indexes the groups by the folders of their prefix, so that
+// matching a path only checks the groups of its parent folders instead of
+// all groups. This makes a big difference for repositories with thousands
+// of nested ignore files.
+//
+// Each node is a folder and contains the indices of the groups with exactly
+// that prefix in ascending order. As the groups are sorted by the depth of
+// their prefix, walking down the tree visits the groups in the same order as
+// n.groups.
+type groupTree struct {
+	groups   []int
+	children map[string]*groupTree
+}
+
+// newGroupTree builds the tree for the groups.
+func newGroupTree(groups []group) *groupTree {
+	root := &groupTree{}
+	for i, g := range groups {
+		root.add(g.prefix, i)
+	}
+	return root
+}
+
+// add the index of a group with the prefix.
+func (t *groupTree) add(prefix string, index int) {
+	node := t
+	if prefix != "" {
+		for _, name := range strings.Split(prefix, "/") {
+			child, ok := node.children[name]
+			if !ok {
+				if node.children == nil {
+					node.children = make(map[string]*groupTree)
+				}
+				child = &groupTree{}
+				node.children[name] = child
+			}
+			node = child
+		}
+	}
+	node.groups = append(node.groups, index)
+}
+
+// child returns the node of the folder with the name, or nil if there are
+// no groups inside of it.
+func (t *groupTree) child(name string) *groupTree {
+	return t.children[name]
+}
@@ -0,0 +1,66 @@
+package nogo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupTree(t *testing.T) {
+	groups := []group{
+		{prefix: ""},
+		{prefix: "a"},
+		{prefix: "b"},
+		{prefix: "a"},
+		{prefix: "a/b"},
+		{prefix: "ab"},
+	}
+	tree := newGroupTree(groups)
+
+	assert.Equal(t, []int{0}, tree.groups)
+	assert.Equal(t, []int{1, 3}, tree.child("a").groups)
+	assert.Equal(t, []int{4}, tree.child("a").child("b").groups)
+	assert.Equal(t, []int{5}, tree.child("ab").groups)
+	assert.Nil(t, tree.child("c"))
+}
+
+func TestNoGo_groupTree(t *testing.T) {
+	n := New()
+	for i := 0; i < 20; i++ {
+		dir := fmt.Sprintf("dir%d", i)
+		n.AddRules(MustCompileAll(dir, []byte("*.log\n!keep.log\n"))...)
+		n.AddRules(MustCompileAll(dir+"/sub", []byte("keep.log\n"))...)
+	}
+	n.AddRules(MustCompileAll("", []byte("*.tmp\n"))...)
+
+	// Without the tree, all groups are checked.
+	linear := &NoGo{groups: n.groups}
+
+	paths := []string{
+		"a.tmp",
+		"dir3/a.log",
+		"dir3/keep.log",
+		"dir3/sub/keep.log",
+		"dir3/sub/deeper/keep.log",
+		"dir30/a.log",
+		"dir3x/sub/keep.log",
+		"other/a.log",
+		"dir19/sub",
+	}
+	for _, path := range paths {
+		match, because := n.MatchBecause(path, false)
+		wantMatch, wantBecause := linear.MatchBecause(path, false)
+		assert.Equal(t, wantMatch, match, path)
+		assert.Equal(t, wantBecause, because, path)
+	}
+
+	if raceEnabled {
+		// The race detector allocates by itself.
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		n.Match("dir3/sub/deeper/keep.log", false)
+	})
+	assert.Zero(t, allocs)
+}
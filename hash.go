@@ -0,0 +1,43 @@
+package nogo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// Hash returns a deterministic digest of all loaded rules, the tags disabled
+// by DisableTag and all options which change the decisions (e.g. the Backend).
+// Options which only change the performance, such as the cache, are not included.
+//
+// The digest is the same for equal rules which are loaded in the same order,
+// so it can be used e.g. in cache keys of build systems to detect changes of
+// the ignore files. It also changes if the BehaviorVersion of nogo changes.
+func (n *NoGo) Hash() string {
+	var buf bytes.Buffer
+	writeString(&buf, BehaviorVersion)
+
+	writeUvarint(&buf, uint64(n.backend))
+	writeBool(&buf, n.stripStreams)
+	writeBool(&buf, n.allowList)
+	writeBool(&buf, n.ignoreCase)
+	writeBool(&buf, n.windowsPaths)
+	writeBool(&buf, n.validatePaths)
+	writeBool(&buf, n.submodules != nil)
+
+	writeGroups(&buf, n.groups)
+
+	tags := make([]string, 0, len(n.disabledTags))
+	for tag := range n.disabledTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	writeUvarint(&buf, uint64(len(tags)))
+	for _, tag := range tags {
+		writeString(&buf, tag)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoGo_Hash(t *testing.T) {
+	newNoGo := func(data string, opts ...Option) *NoGo {
+		n := New(opts...)
+		n.AddRules(MustCompileAll("", []byte(data))...)
+		return n
+	}
+
+	hash := newNoGo("*.go\n/build").Hash()
+	assert.Len(t, hash, 64)
+
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, hash, newNoGo("*.go\n/build").Hash())
+		assert.Equal(t, hash, newNoGo("*.go\n\n# comment\n/build").Hash(), "comments are no rules")
+		assert.Equal(t, hash, newNoGo("*.go\n/build", WithCache(10)).Hash(), "the cache does not change the decisions")
+	})
+
+	t.Run("different", func(t *testing.T) {
+		assert.NotEqual(t, hash, New().Hash())
+		assert.NotEqual(t, hash, newNoGo("/build\n*.go").Hash(), "the order is important")
+		assert.NotEqual(t, hash, newNoGo("*.go\n/build/").Hash())
+		assert.NotEqual(t, hash, newNoGo("*.go\n!/build").Hash())
+		assert.NotEqual(t, hash, newNoGo("*.go\n/build", WithBackend(GlobBackend)).Hash())
+		assert.NotEqual(t, hash, newNoGo("*.go\n/build", WithWindowsStreams()).Hash())
+		assert.NotEqual(t, hash, newNoGo("*.go\n/build", WithValidatePaths()).Hash())
+		assert.NotEqual(t, hash, newNoGo("*.go\n/build", WithSubmodules()).Hash())
+
+		withPrefix := New()
+		withPrefix.AddRules(MustCompileAll("sub", []byte("*.go\n/build"))...)
+		assert.NotEqual(t, hash, withPrefix.Hash())
+	})
+
+	t.Run("changes when rules are added", func(t *testing.T) {
+		n := newNoGo("*.go\n/build")
+		n.AddRules(DotGitRule)
+		assert.NotEqual(t, hash, n.Hash())
+	})
+
+	t.Run("disabled tags", func(t *testing.T) {
+		n := newNoGo("*.go\n/build")
+		n.AddRulesTagged("defaults", MustCompileAll("", []byte("*.log"))...)
+		n.AddRulesTagged("editor", MustCompileAll("", []byte("*.swp"))...)
+		enabled := n.Hash()
+
+		n.DisableTag("defaults")
+		disabled := n.Hash()
+		assert.NotEqual(t, enabled, disabled)
+
+		n.DisableTag("editor")
+		assert.NotEqual(t, disabled, n.Hash())
+
+		other := newNoGo("*.go\n/build")
+		other.AddRulesTagged("defaults", MustCompileAll("", []byte("*.log"))...)
+		other.AddRulesTagged("editor", MustCompileAll("", []byte("*.swp"))...)
+		other.DisableTag("editor")
+		other.DisableTag("defaults")
+		assert.Equal(t, n.Hash(), other.Hash(), "the order of disabling is not important")
+
+		n.EnableTag("defaults")
+		n.EnableTag("editor")
+		assert.Equal(t, enabled, n.Hash())
+	})
+}
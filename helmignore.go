@@ -0,0 +1,92 @@
+package nogo
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// helmignoreRules converts the lines of a .helmignore file to rules which
+// behave the same way as Helm evaluates them:
+//   - Leading and trailing spaces are removed.
+//   - "**" is not supported and results in an error.
+//   - Ranges are negated using '^' instead of '!'.
+//   - A negated pattern ignores everything which does NOT match it.
+//     A path is ignored if any pattern matches it or any negated pattern does not
+//     match it, so the order of the patterns doesn't matter.
+//
+// The negation is built using a "*" rule in front of a negated rule which
+// only matches paths matching all negated patterns. For several negated
+// patterns, it contains the regexps of all of them and has no pattern.
+func helmignoreRules(prefix string, lines []string) ([]Rule, error) {
+	var patterns []string
+	var negated []string
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if strings.Contains(line, "**") {
+			return nil, &PatternError{Line: i + 1, Pattern: line, Cause: errors.New("helmignore: double-star (**) syntax is not supported")}
+		}
+		line = strings.ReplaceAll(line, "[^", "[!")
+
+		if line[0] == '!' {
+			negated = append(negated, line)
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	rules := make([]Rule, 0, len(patterns)+2)
+	if len(negated) > 0 {
+		// Everything which doesn't match the negated patterns is ignored.
+		_, all, err := Compile(prefix, "*")
+		if err != nil {
+			return nil, err
+		}
+		included, err := helmignoreNegation(prefix, negated)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, all, included)
+	}
+
+	for _, pattern := range patterns {
+		skip, rule, err := Compile(prefix, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if !skip {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// helmignoreNegation builds a negated rule which matches the paths matching
+// all of the negated patterns. As Helm ignores directories for negated
+// patterns ending with a '/', it only matches folders if any of them does.
+func helmignoreNegation(prefix string, negated []string) (Rule, error) {
+	var included Rule
+	for i, pattern := range negated {
+		skip, rule, err := Compile(prefix, pattern)
+		if err != nil {
+			return Rule{}, err
+		}
+		if skip {
+			// A negated pattern without glob doesn't match anything.
+			rule = Rule{Regexp: []*regexp.Regexp{neverMatchReg}, Prefix: prefix, Negate: true}
+		}
+		if i == 0 {
+			included = rule
+			continue
+		}
+
+		included.Pattern = ""
+		included.Regexp = append(included.Regexp, rule.Regexp...)
+		included.OnlyFolder = included.OnlyFolder || rule.OnlyFolder
+	}
+	return included, nil
+}
@@ -0,0 +1,114 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmignoreRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "helm default",
+			data: "# Patterns to ignore when building packages.\n.DS_Store\n  .git/  \n*.swp\n*.tmp\n.vscode/\n/ci\nsecrets/*.yaml\n",
+			want: map[string]bool{
+				".DS_Store":           true,
+				"templates/.DS_Store": true,
+				"a.swp":               true,
+				"ci":                  true,
+				"templates/ci":        false,
+				"secrets/a.yaml":      true,
+				"x/secrets/a.yaml":    false,
+				"values.yaml":         false,
+			},
+		},
+		{
+			name: "ranges",
+			data: "file[^0-9]",
+			want: map[string]bool{
+				"filea": true,
+				"file1": false,
+			},
+		},
+		{
+			name: "negation ignores everything else",
+			data: "!*.yaml\nbad.yaml",
+			want: map[string]bool{
+				"values.yaml": false,
+				"bad.yaml":    true,
+				"README.md":   true,
+			},
+		},
+		{
+			name:    "double star",
+			data:    "**/*.tmp",
+			wantErr: true,
+		},
+		{
+			// A path has to match all negated patterns to not be ignored.
+			name: "several negations",
+			data: "!*.yaml\n!values*\nvalues-secret.yaml",
+			want: map[string]bool{
+				"values.yaml":        false,
+				"values-dev.yaml":    false,
+				"values-secret.yaml": true,
+				"chart.yaml":         true,
+				"values.txt":         true,
+				"values/a.yaml":      true,
+				"README.md":          true,
+			},
+		},
+		{
+			name: "negations in any order",
+			data: "!values*\n!*.yaml",
+			want: map[string]bool{
+				"values.yaml": false,
+				"chart.yaml":  true,
+				"values.txt":  true,
+			},
+		},
+		{
+			// Helm ignores all files for a negated pattern ending with a '/'.
+			name: "negated folder",
+			data: "!*.yaml\n!templates/",
+			want: map[string]bool{
+				"values.yaml":    true,
+				"templates.yaml": true,
+			},
+		},
+		{
+			// No path matches both patterns, so everything is ignored.
+			name: "disjoint negations",
+			data: "!*.yaml\n!*.tpl",
+			want: map[string]bool{
+				"a.yaml": true,
+				"a.tpl":  true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := DialectHelmignore.CompileAll("", []byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+				n := New(WithBackend(backend))
+				n.AddRules(rules...)
+
+				for path, want := range tt.want {
+					assert.Equal(t, want, n.Match(path, false), "%v: %s", backend, path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package nogo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hgCommentRegexp finds comments which are not escaped by a backslash.
+var hgCommentRegexp = regexp.MustCompile(`((?:^|[^\\])(?:\\\\)*)#.*`)
+
+// hgSyntaxes maps the names of the syntax lines and prefixes to the actual syntax.
+var hgSyntaxes = map[string]string{
+	"re":       "relre",
+	"regexp":   "relre",
+	"relre":    "relre",
+	"glob":     "relglob",
+	"relglob":  "relglob",
+	"rootglob": "rootglob",
+}
+
+// hgignoreRules converts the lines of a .hgignore file to rules.
+// The patterns are converted to regexps the same way as Mercurial does it,
+// so the rules have no Pattern.
+func hgignoreRules(prefix string, lines []string) ([]Rule, error) {
+	syntax := "relre"
+	var rules []Rule
+	for i, line := range lines {
+		if strings.Contains(line, "#") {
+			if m := hgCommentRegexp.FindStringSubmatchIndex(line); m != nil {
+				line = line[:m[3]]
+			}
+			line = strings.ReplaceAll(line, `\#`, "#")
+		}
+		line = strings.TrimRight(line, " \t\r\n\v\f")
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "syntax:") {
+			name := strings.TrimSpace(line[len("syntax:"):])
+			s, ok := hgSyntaxes[name]
+			if !ok {
+				return nil, fmt.Errorf("hgignore: unsupported syntax %q", name)
+			}
+			syntax = s
+			continue
+		}
+
+		lineSyntax := syntax
+		if i := strings.IndexByte(line, ':'); i > 0 {
+			if s, ok := hgSyntaxes[line[:i]]; ok {
+				lineSyntax = s
+				line = line[i+1:]
+			} else if line[:i] == "include" || line[:i] == "subinclude" {
+				return nil, fmt.Errorf("hgignore: %s is not supported", line[:i])
+			}
+		}
+
+		rule, err := hgRule(prefix, lineSyntax, line)
+		if err != nil {
+			return nil, &PatternError{Line: i + 1, Pattern: lines[i], Cause: err}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// hgRule compiles a single pattern. The regexps are matched from the start
+// of the path, like Mercurial does it.
+func hgRule(prefix string, syntax string, pattern string) (Rule, error) {
+	var expr string
+	switch syntax {
+	case "relre":
+		if strings.HasPrefix(pattern, "^") {
+			expr = pattern[1:]
+		} else {
+			expr = ".*" + pattern
+		}
+	case "relglob":
+		expr = "(?:|.*/)" + hgGlobRegexp(pattern) + "(?:/|$)"
+	case "rootglob":
+		expr = hgGlobRegexp(pattern) + "(?:/|$)"
+	}
+
+	if prefix != "" {
+		expr = regexp.QuoteMeta(strings.TrimSuffix(prefix, "/")+"/") + "(?:" + expr + ")"
+	}
+
+	reg, err := regexp.Compile(ByteRunes("^(?:" + expr + ")"))
+	if err != nil {
+		return Rule{}, fmt.Errorf("hgignore: %w", err)
+	}
+
+	return Rule{
+		Regexp: []*regexp.Regexp{reg},
+		Prefix: prefix,
+	}, nil
+}
+
+// hgGlobRegexp converts a glob of Mercurial to a regexp.
+// In contrast to gitignore, "?" also matches a '/', "**" matches anything
+// and "{a,b}" matches one of the alternatives.
+func hgGlobRegexp(glob string) string {
+	var b strings.Builder
+	group := 0
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case c == '?':
+			b.WriteString(".")
+		case c == '[':
+			j := i + 1
+			if j < len(glob) && (glob[j] == '!' || glob[j] == ']') {
+				j++
+			}
+			for j < len(glob) && glob[j] != ']' {
+				j++
+			}
+			if j >= len(glob) {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := strings.ReplaceAll(glob[i+1:j], `\`, `\\`)
+			i = j
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			} else if strings.HasPrefix(class, "^") {
+				class = `\` + class
+			}
+			b.WriteString("[" + class + "]")
+		case c == '{':
+			group++
+			b.WriteString("(?:")
+		case c == '}' && group > 0:
+			group--
+			b.WriteString(")")
+		case c == ',' && group > 0:
+			b.WriteString("|")
+		case c == '\\':
+			i++
+			if i < len(glob) {
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			} else {
+				b.WriteString(`\\`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHgGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{glob: "*.c", want: `[^/]*\.c`},
+		{glob: "a/**/b", want: `a/(?:.*/)?b`},
+		{glob: "a**", want: `a.*`},
+		{glob: "a?c", want: `a.c`},
+		{glob: "[!ab]", want: `[^ab]`},
+		{glob: "[^ab]", want: `[\^ab]`},
+		{glob: "[]a]", want: `[]a]`},
+		{glob: "[a", want: `\[a`},
+		{glob: "{a,b{c,d}}", want: `(?:a|b(?:c|d))`},
+		{glob: "a,b}", want: `a,b\}`},
+		{glob: `\*x\`, want: `\*x\\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			assert.Equal(t, tt.want, hgGlobRegexp(tt.glob))
+		})
+	}
+}
+
+func TestHgignoreRules(t *testing.T) {
+	data := `# The default syntax is regexp.
+\.orig$
+^build/
+foo\#bar # a comment
+
+syntax: glob
+*.pyc
+docs/_build
+rootglob:/dist
+re:^tmp\d+$
+glob:x?z
+
+syntax: rootglob
+out
+`
+
+	tests := map[string]bool{
+		"a.orig":        true,
+		"sub/a.orig":    true,
+		"a.origx":       false,
+		"build/a":       true,
+		"sub/build/a":   false,
+		"foo#bar":       true,
+		"x/foo#bar":     true,
+		"a.pyc":         true,
+		"sub/a.pyc":     true,
+		"a.pyc/inner":   true,
+		"docs/_build":   true,
+		"x/docs/_build": true,
+		"/dist":         false,
+		"tmp12":         true,
+		"x/tmp12":       false,
+		"x/z":           true,
+		"xyz":           true,
+		"out":           true,
+		"sub/out":       false,
+		"outer":         false,
+		"main.go":       false,
+	}
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		n := New(WithBackend(backend))
+		rules, err := DialectHgignore.CompileAll("", []byte(data))
+		require.NoError(t, err)
+		n.AddRules(rules...)
+
+		for path, want := range tests {
+			assert.Equal(t, want, n.Match(path, false), "%v: %s", backend, path)
+		}
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		rules, err := DialectHgignore.CompileAll("sub", []byte("^build\nglob:*.o"))
+		require.NoError(t, err)
+		n := New()
+		n.AddRules(rules...)
+
+		assert.True(t, n.Match("sub/build", false))
+		assert.True(t, n.Match("sub/a/b.o", false))
+		assert.False(t, n.Match("build", false))
+		assert.False(t, n.Match("b.o", false))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, data := range []string{"syntax: unknown", "include:other", "re:(?=a)", "glob:{a"} {
+			_, err := DialectHgignore.CompileAll("", []byte(data))
+			assert.Error(t, err, data)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// WithIgnoreCase enables case-insensitive matching, the same as git does it
+// if core.ignoreCase is set (e.g. on macOS and Windows).
+// Like git, only ASCII letters are folded.
+//
+// The patterns and prefixes of all rules are converted to lower case,
+// so Result.Pattern contains the converted pattern. Rules without a Pattern (e.g. of the
+// DialectHgignore or NewRule) are matched against the lower case path as they are.
+func WithIgnoreCase() Option {
+	return optionFunc(func(n *NoGo) {
+		n.ignoreCase = true
+		n.changed()
+	})
+}
+
+// foldGroups converts the rules of all groups which are not converted yet.
+func (n *NoGo) foldGroups() {
+	for gi := range n.groups {
+		g := &n.groups[gi]
+		if g.folded {
+			continue
+		}
+		g.prefix = lowerASCIIString(g.prefix)
+
+		for ri, rule := range g.rules {
+			prefix := lowerASCIIString(rule.Prefix)
+			folded := foldPattern(rule.Pattern)
+			if prefix == rule.Prefix && folded == rule.Pattern {
+				continue
+			}
+
+			// Folding only changes letters and adds letters to ranges, so the
+			// folded pattern compiles like the original one. Rules without a
+			// pattern (e.g. of NewRule) are skipped by Compile and kept as
+			// they are.
+			skip, foldedRule, err := Compile(prefix, folded)
+			if err == nil && !skip {
+				g.rules[ri] = foldedRule
+			}
+		}
+		g.folded = true
+	}
+}
+
+// foldPattern converts the pattern so that it matches the lower case
+// variant of all paths it matches case-insensitively.
+// Letters are converted to lower case and ranges get the lower case variant
+// of all upper case letters they contain.
+func foldPattern(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteByte(c)
+			b.WriteByte(lowerASCII(pattern[i]))
+		case c == '[':
+			end := rangeEnd(pattern, i+1)
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteString(foldRange(pattern[i : end+1]))
+			i = end
+		default:
+			b.WriteByte(lowerASCII(c))
+		}
+	}
+	return b.String()
+}
+
+// foldRange adds all lower case letters to the range (e.g. "[A-C]")
+// whose upper case variant is part of it.
+// Like git with WM_CASEFOLD, the class "[:upper:]" matches lower case letters,
+// too, so it is replaced by "[:alpha:]".
+func foldRange(r string) string {
+	content := r[1 : len(r)-1]
+	items := strings.TrimPrefix(content, "!")
+
+	var b strings.Builder
+	b.WriteString(content[:len(content)-len(items)])
+
+	var set [256]bool
+	for i := 0; i < len(items); i++ {
+		if end := classEnd(items, i); end >= 0 {
+			class := items[i : end+1]
+			if class == "[:upper:]" {
+				class = "[:alpha:]"
+			}
+			b.WriteString(class)
+			i = end
+			continue
+		}
+
+		start := i
+		lo := items[i]
+		if lo == '\\' && i+1 < len(items) {
+			i++
+			lo = items[i]
+		}
+
+		hi := lo
+		if i+2 < len(items) && items[i+1] == '-' {
+			i += 2
+			hi = items[i]
+			if hi == '\\' && i+1 < len(items) {
+				i++
+				hi = items[i]
+			}
+		}
+		b.WriteString(items[start : i+1])
+
+		for c := int(lo); c <= int(hi); c++ {
+			set[c] = true
+		}
+	}
+
+	for c := byte('A'); c <= 'Z'; c++ {
+		if set[c] && !set[lowerASCII(c)] {
+			b.WriteByte(lowerASCII(c))
+		}
+	}
+	return "[" + b.String() + "]"
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// lowerASCIIString converts all ASCII letters of s to lower case.
+func lowerASCIIString(s string) string {
+	i := 0
+	for i < len(s) && (s[i] < 'A' || s[i] > 'Z') {
+		i++
+	}
+	if i == len(s) {
+		return s
+	}
+
+	b := []byte(s)
+	for ; i < len(b); i++ {
+		b[i] = lowerASCII(b[i])
+	}
+	return string(b)
+}
@@ -0,0 +1,133 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "main.go", want: "main.go"},
+		{pattern: "README.MD", want: "readme.md"},
+		{pattern: `\A*`, want: `\a*`},
+		{pattern: "[A-C]x", want: "[A-Cabc]x"},
+		{pattern: "[a-zA-Z]", want: "[a-zA-Z]"},
+		{pattern: "[!X]", want: "[!Xx]"},
+		{pattern: `[\]Q]`, want: `[\]Qq]`},
+		{pattern: "[Ab", want: "[ab"},
+		{pattern: "[[:upper:]]*", want: "[[:alpha:]]*"},
+		{pattern: "[![:upper:]X]", want: "[![:alpha:]Xx]"},
+		{pattern: "[[:lower:][:digit:]]", want: "[[:lower:][:digit:]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := foldPattern(tt.pattern)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, foldPattern(got), "not idempotent")
+		})
+	}
+}
+
+func TestWithIgnoreCase(t *testing.T) {
+	rules := MustCompileAll("", []byte("README.md\n/Build/\n*.LOG\n!keep.log\n[A-C]*.txt\n[!X]y\nnode_modules/"))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "readme.md", want: true},
+		{path: "README.MD", want: true},
+		{path: "sub/ReadMe.md", want: true},
+		{path: "BUILD", isDir: true, want: true},
+		{path: "build/sub", isDir: true, want: true},
+		{path: "build", want: false},
+		{path: "a.log", want: true},
+		{path: "KEEP.LOG", want: false},
+		{path: "a.txt", want: true},
+		{path: "B.txt", want: true},
+		{path: "d.txt", want: false},
+		{path: "xy", want: false},
+		{path: "Xy", want: false},
+		{path: "ay", want: true},
+		{path: "Node_Modules", isDir: true, want: true},
+		{path: "main.go", want: false},
+	}
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		for _, compile := range []bool{false, true} {
+			n := New(WithBackend(backend), WithIgnoreCase())
+			n.AddRules(rules...)
+			if compile {
+				n.Compile()
+			}
+
+			for _, tt := range tests {
+				assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir), "%v %v: %s", backend, compile, tt.path)
+
+				match, _ := n.MatchBecause(tt.path, tt.isDir)
+				assert.Equal(t, tt.want, match, "%v %v: %s", backend, compile, tt.path)
+			}
+		}
+	}
+
+	t.Run("without option", func(t *testing.T) {
+		n := New()
+		n.AddRules(rules...)
+		assert.True(t, n.Match("README.md", false))
+		assert.False(t, n.Match("readme.md", false))
+	})
+
+	t.Run("option after rules", func(t *testing.T) {
+		n := New(rules[0])
+		WithIgnoreCase().apply(n)
+		assert.True(t, n.Match("readme.MD", false))
+	})
+
+	t.Run("terminal rules", func(t *testing.T) {
+		n := New(WithIgnoreCase())
+		n.AddRules(MustCompileAll("", []byte("Node_Modules\n*.EXE"))...)
+		require.NotNil(t, n.terminals)
+		assert.True(t, n.Match("src/node_modules/a.js", false))
+		assert.True(t, n.Match("A.exe", false))
+		assert.False(t, n.Match("a.exe.txt", false))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		n := New(WithIgnoreCase())
+		n.AddRules(MustCompileAll("Sub/Dir", []byte("*.txt"))...)
+		assert.True(t, n.Match("sub/dir/a.txt", false))
+		assert.True(t, n.Match("SUB/Dir/A.TXT", false))
+		assert.Equal(t, "sub/dir", n.Groups()[0].Prefix)
+
+		assert.True(t, n.RemoveGroup("Sub/Dir"))
+		assert.False(t, n.Match("sub/dir/a.txt", false))
+	})
+
+	t.Run("character classes", func(t *testing.T) {
+		for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+			n := New(WithBackend(backend), WithIgnoreCase())
+			n.AddRules(MustCompileAll("", []byte("[[:upper:]]*.md\n[[:lower:]]*.txt\n[![:upper:]]*.go"))...)
+			assert.True(t, n.Match("README.md", false), backend)
+			assert.True(t, n.Match("readme.md", false), backend)
+			assert.True(t, n.Match("A.TXT", false), backend)
+			assert.True(t, n.Match("a.txt", false), backend)
+			assert.False(t, n.Match("1.md", false), backend)
+			assert.False(t, n.Match("Main.go", false), backend)
+			assert.True(t, n.Match("1.go", false), backend)
+		}
+	})
+
+	t.Run("regexp only rules", func(t *testing.T) {
+		rules, err := DialectHgignore.CompileAll("", []byte(`\.orig$`))
+		require.NoError(t, err)
+		n := New(WithIgnoreCase())
+		n.AddRules(rules...)
+		assert.True(t, n.Match("A.ORIG", false))
+	})
+}
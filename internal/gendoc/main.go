@@ -0,0 +1,138 @@
+// Gendoc generates the behavior documentation of nogo from the embedded corpus.
+//
+// It writes BEHAVIOR.md, which contains the full behavior matrix, and
+// example_corpus_test.go, which contains one testable example per pattern.
+// As the examples are run by go test, the documentation can't get out of sync
+// with the actual behavior of the matcher.
+//
+// Run it using go generate in the root of the repository.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+
+	"github.com/aligator/nogo"
+)
+
+const header = "Code generated by internal/gendoc from corpus/behavior.txt. DO NOT EDIT."
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gendoc:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cases := nogo.Corpus()
+	matrix, err := nogo.NewBehaviorMatrix(cases)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile("BEHAVIOR.md", markdown(matrix), 0644); err != nil {
+		return err
+	}
+
+	examples, err := examples(cases)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile("example_corpus_test.go", examples, 0644)
+}
+
+// markdown renders the matrix as table with one row per path and
+// one column per pattern.
+func markdown(matrix nogo.BehaviorMatrix) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<!-- %s -->\n\n", header)
+	fmt.Fprintln(&b, "# Behavior")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "This matrix shows for each pattern of the [corpus](corpus/behavior.txt)")
+	fmt.Fprintln(&b, "if nogo ignores a path (✓) or not (✗).")
+	fmt.Fprintln(&b, "Each pattern is the only rule of an ignore file in the root folder.")
+	fmt.Fprintln(&b, "Paths ending with a '/' are directories.")
+	fmt.Fprintln(&b)
+
+	fmt.Fprint(&b, "| path |")
+	for _, pattern := range matrix.Patterns {
+		fmt.Fprintf(&b, " `%s` |", strings.ReplaceAll(pattern, "|", `\|`))
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprint(&b, "|---|")
+	for range matrix.Patterns {
+		fmt.Fprint(&b, ":---:|")
+	}
+	fmt.Fprintln(&b)
+
+	for j, path := range matrix.Paths {
+		fmt.Fprintf(&b, "| `%s` |", path)
+		for i := range matrix.Patterns {
+			if matrix.Ignored[i][j] {
+				fmt.Fprint(&b, " ✓ |")
+			} else {
+				fmt.Fprint(&b, " ✗ |")
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	return b.Bytes()
+}
+
+// examples renders one example function for each pattern of the corpus.
+func examples(cases []nogo.CorpusCase) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\n", header)
+	fmt.Fprintln(&b, "package nogo_test")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `import (`)
+	fmt.Fprintln(&b, `	"fmt"`)
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `	"github.com/aligator/nogo"`)
+	fmt.Fprintln(&b, `)`)
+
+	var byPattern [][]nogo.CorpusCase
+	for _, c := range cases {
+		if len(byPattern) == 0 || byPattern[len(byPattern)-1][0].Pattern != c.Pattern {
+			byPattern = append(byPattern, nil)
+		}
+		byPattern[len(byPattern)-1] = append(byPattern[len(byPattern)-1], c)
+	}
+
+	for i, group := range byPattern {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// This example shows which paths the pattern %q ignores.\n", group[0].Pattern)
+		fmt.Fprintf(&b, "func Example_corpus%02d() {\n", i+1)
+		fmt.Fprintf(&b, "n := nogo.New()\n")
+		fmt.Fprintf(&b, "n.AddRules(nogo.MustCompileAll(\"\", []byte(%q))...)\n\n", group[0].Pattern)
+
+		var output strings.Builder
+		for _, c := range group {
+			ignored, err := c.Check()
+			if err != nil {
+				return nil, err
+			}
+
+			path := c.Path
+			if c.IsDir {
+				path += "/"
+			}
+
+			fmt.Fprintf(&b, "fmt.Println(%q, n.Match(%q, %v))\n", path, c.Path, c.IsDir)
+			fmt.Fprintf(&output, "// %s %v\n", path, ignored)
+		}
+
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "// Output:")
+		fmt.Fprint(&b, output.String())
+		fmt.Fprintln(&b, "}")
+	}
+
+	return format.Source(b.Bytes())
+}
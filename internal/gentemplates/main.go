@@ -0,0 +1,60 @@
+// Gentemplates downloads the .gitignore templates embedded by nogo.Template
+// from https://github.com/github/gitignore into the templates folder.
+//
+// Run it using go generate in the root of the repository.
+// Add the name of a template to names to embed it, too.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const baseURL = "https://raw.githubusercontent.com/github/gitignore/main/"
+
+// names contains the templates to embed.
+var names = []string{
+	"C",
+	"Go",
+	"Java",
+	"Node",
+	"Python",
+	"Rust",
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gentemplates:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	for _, name := range names {
+		data, err := download(baseURL + name + ".gitignore")
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join("templates", name+".gitignore"), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
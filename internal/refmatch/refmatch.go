@@ -0,0 +1,269 @@
+// Package refmatch is a reference implementation of the gitignore matching,
+// which is used by the fuzz tests of nogo to cross-check its decisions.
+//
+// It is written straight from the gitignore documentation and the
+// behavior of git, without any optimizations or regexps, so it is easy to
+// verify. It only supports a single pattern in the root folder.
+package refmatch
+
+import "strings"
+
+// Ignored checks if the path is ignored by a .gitignore file in the root
+// folder which only contains the pattern. Parent folders of the path are
+// checked, too, as nothing inside of an ignored folder can be included.
+func Ignored(pattern, path string, isDir bool) bool {
+	glob, negate, dirOnly, anchored, ok := clean(pattern)
+	if !ok || negate {
+		// A single negated pattern can't ignore anything.
+		return false
+	}
+
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		last := i == len(segments)-1
+		if dirOnly && last && !isDir {
+			continue
+		}
+
+		name := strings.Join(segments[:i+1], "/")
+		if !anchored {
+			name = segments[i]
+		}
+		if Match(glob, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// clean removes everything from the line which is not part of the glob.
+// ok is false if the line doesn't contain a pattern.
+func clean(line string) (glob string, negate, dirOnly, anchored, ok bool) {
+	if line == "" || line[0] == '#' {
+		return "", false, false, false, false
+	}
+
+	// Trailing spaces are removed, except if they are escaped.
+	// A backslash escapes any character, including another backslash.
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			if end == len(line) {
+				end = i
+			}
+		case '\\':
+			i++
+			end = len(line)
+		default:
+			end = len(line)
+		}
+	}
+	line = line[:end]
+
+	if line != "" && line[0] == '!' {
+		negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return "", false, false, false, false
+	}
+
+	// A slash at the start or in the middle anchors the pattern to the root.
+	if strings.Contains(line, "/") {
+		anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	return line, negate, dirOnly, anchored, true
+}
+
+// Match checks if the glob matches the whole name.
+// Wildcards never match a '/', except for "**" as whole segment.
+func Match(glob, name string) bool {
+	return match(glob, 0, name, 0)
+}
+
+func match(p string, pi int, s string, si int) bool {
+	for pi < len(p) {
+		switch c := p[pi]; c {
+		case '*':
+			stars := pi
+			for stars < len(p) && p[stars] == '*' {
+				stars++
+			}
+			// Two or more stars as whole segment match across folders.
+			if stars-pi >= 2 && (pi == 0 || p[pi-1] == '/') && (stars == len(p) || p[stars] == '/') {
+				if stars == len(p) {
+					// A trailing "**" matches everything.
+					return true
+				}
+
+				// "**/" matches zero or more folders.
+				rest := stars + 1
+				if match(p, rest, s, si) {
+					return true
+				}
+				for i := si; i < len(s); i++ {
+					if s[i] == '/' && match(p, rest, s, i+1) {
+						return true
+					}
+				}
+				return false
+			}
+
+			// Any other star matches anything but a '/'.
+			for pi < len(p) && p[pi] == '*' {
+				pi++
+			}
+			for i := si; ; i++ {
+				if match(p, pi, s, i) {
+					return true
+				}
+				if i == len(s) || s[i] == '/' {
+					return false
+				}
+			}
+		case '?':
+			if si == len(s) || s[si] == '/' {
+				return false
+			}
+			pi++
+			si++
+		case '[':
+			if si == len(s) || s[si] == '/' {
+				return false
+			}
+			matched, end, ok := matchClass(p, pi, s[si])
+			if !ok || !matched {
+				// An unclosed range never matches.
+				return false
+			}
+			pi = end + 1
+			si++
+		case '\\':
+			if pi+1 == len(p) {
+				// A trailing backslash never matches.
+				return false
+			}
+			if si == len(s) || s[si] != p[pi+1] {
+				return false
+			}
+			pi += 2
+			si++
+		default:
+			if si == len(s) || s[si] != c {
+				return false
+			}
+			pi++
+			si++
+		}
+	}
+	return si == len(s)
+}
+
+// matchClass checks if the range starting at p[start] matches c.
+// end is the index of the closing ']'. ok is false if the range is not closed.
+func matchClass(p string, start int, c byte) (matched bool, end int, ok bool) {
+	i := start + 1
+	negate := false
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		negate = true
+		i++
+	}
+
+	// A ']' directly at the start is a normal character.
+	first := true
+	for {
+		if i >= len(p) {
+			return false, 0, false
+		}
+		if p[i] == ']' && !first {
+			break
+		}
+		first = false
+
+		// A class ends at the next ']'. If there is no ':' before it,
+		// the '[' is a normal character.
+		if strings.HasPrefix(p[i:], "[:") {
+			if length := strings.IndexByte(p[i+2:], ']'); length > 0 && p[i+2+length-1] == ':' {
+				class := p[i+2 : i+2+length-1]
+				known, inClass := matchPosixClass(class, c)
+				if !known {
+					return false, 0, false
+				}
+				matched = matched || inClass
+				i += 2 + length + 1
+				continue
+			}
+		}
+
+		lo, next, ok := classChar(p, i)
+		if !ok {
+			return false, 0, false
+		}
+		i = next
+
+		if i+1 < len(p) && p[i] == '-' && p[i+1] != ']' {
+			hi, next, ok := classChar(p, i+1)
+			if !ok {
+				return false, 0, false
+			}
+			i = next
+			matched = matched || lo <= c && c <= hi
+			continue
+		}
+		matched = matched || c == lo
+	}
+	return matched != negate, i, true
+}
+
+// classChar returns the possibly escaped character at p[i] and the index after it.
+func classChar(p string, i int) (c byte, next int, ok bool) {
+	if p[i] == '\\' {
+		i++
+		if i >= len(p) {
+			return 0, 0, false
+		}
+	}
+	return p[i], i + 1, true
+}
+
+// matchPosixClass checks if c is part of the class, e.g. "alpha".
+// known is false for unknown classes.
+func matchPosixClass(class string, c byte) (known, matched bool) {
+	isUpper := 'A' <= c && c <= 'Z'
+	isLower := 'a' <= c && c <= 'z'
+	isDigit := '0' <= c && c <= '9'
+	switch class {
+	case "alnum":
+		return true, isUpper || isLower || isDigit
+	case "alpha":
+		return true, isUpper || isLower
+	case "blank":
+		return true, c == ' ' || c == '\t'
+	case "cntrl":
+		return true, c < ' ' || c == 0x7f
+	case "digit":
+		return true, isDigit
+	case "graph":
+		return true, c > ' ' && c < 0x7f
+	case "lower":
+		return true, isLower
+	case "print":
+		return true, c >= ' ' && c < 0x7f
+	case "punct":
+		return true, c > ' ' && c < 0x7f && !isUpper && !isLower && !isDigit
+	case "space":
+		return true, c == ' ' || '\t' <= c && c <= '\r'
+	case "upper":
+		return true, isUpper
+	case "xdigit":
+		return true, isDigit || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,71 @@
+package refmatch_test
+
+import (
+	"testing"
+
+	"github.com/aligator/nogo"
+	"github.com/aligator/nogo/internal/refmatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnored_Corpus(t *testing.T) {
+	for _, c := range append(nogo.Corpus(), nogo.Regressions()...) {
+		assert.Equal(t, c.Ignored, refmatch.Ignored(c.Pattern, c.Path, c.IsDir), "pattern %q, path %q, isDir %v", c.Pattern, c.Path, c.IsDir)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		glob string
+		name string
+		want bool
+	}{
+		{glob: "a?c", name: "abc", want: true},
+		{glob: "a?c", name: "ac", want: false},
+		{glob: "a?c", name: "a/c", want: false},
+		{glob: "a*", name: "a/b", want: false},
+		{glob: "a/**/b", name: "a/b", want: true},
+		{glob: "a/**/b", name: "a/x/y/b", want: true},
+		{glob: "a/**/b", name: "ax/b", want: false},
+		{glob: "**/b", name: "x/b", want: true},
+		{glob: "a/**", name: "a/x/y", want: true},
+		{glob: "a**b", name: "ax/b", want: false},
+		{glob: "a/***/b", name: "a/x/y/b", want: true},
+		{glob: "***/b", name: "b", want: true},
+		{glob: "[]a]", name: "]", want: true},
+		{glob: "[!a-c]", name: "d", want: true},
+		{glob: "[^a-c]", name: "b", want: false},
+		{glob: "[a-c", name: "a", want: false},
+		{glob: "[[:digit:]]", name: "1", want: true},
+		{glob: "[[:nope:]]", name: "1", want: false},
+		{glob: "[[:a]", name: "a", want: true},
+		{glob: "[[:a]b:]]", name: "a", want: false},
+		{glob: `\*`, name: "*", want: true},
+		{glob: `\*`, name: "a", want: false},
+		{glob: `a\`, name: "a", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, refmatch.Match(tt.glob, tt.name))
+		})
+	}
+}
+
+func TestIgnored_TrailingSpaces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "a  ", path: "a", want: true},
+		{pattern: `a\ \ `, path: "a  ", want: true},
+		{pattern: `a \ `, path: "a  ", want: true},
+		{pattern: `a\\ `, path: `a\`, want: true},
+		{pattern: `a\\ `, path: `a\ `, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, refmatch.Ignored(tt.pattern, tt.path, false))
+		})
+	}
+}
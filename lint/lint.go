@@ -0,0 +1,246 @@
+// Package lint analyses gitignore files and reports lines which most likely
+// don't do what they are meant to do, e.g. patterns which never match or
+// rules which are made redundant by other rules.
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/aligator/nogo"
+)
+
+// Severity describes how likely an issue is a mistake.
+type Severity int
+
+const (
+	// SeverityInfo is used for lines which can be removed without changing anything.
+	SeverityInfo Severity = iota
+	// SeverityWarning is used for lines which most likely don't do what they are meant to do.
+	SeverityWarning
+	// SeverityError is used for lines which can never match anything.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Codes of the issues.
+const (
+	// CodeNeverMatches means that the pattern can never match anything,
+	// e.g. because of an unclosed range like "[a-z".
+	CodeNeverMatches = "never-matches"
+	// CodeRedundant means that the rule is a duplicate or another rule
+	// already matches every path it matches, see nogo.OptimizeReport.
+	CodeRedundant = "redundant"
+	// CodeIneffectiveNegation means that a negated rule tries to re-include a path
+	// inside of an excluded folder, which is not possible.
+	CodeIneffectiveNegation = "ineffective-negation"
+	// CodeTrailingSpace means that the line ends with whitespace, which is
+	// either removed or, for tabs, part of the pattern.
+	CodeTrailingSpace = "trailing-space"
+	// CodeTrailingBackslash means that the line ends with a backslash which doesn't escape anything.
+	CodeTrailingBackslash = "trailing-backslash"
+)
+
+// Issue is a single finding of Lint.
+type Issue struct {
+	// Line is the line number, starting at 1.
+	Line     int
+	Pattern  string
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s: %s (%s)", i.Line, i.Severity, i.Message, i.Code)
+}
+
+// neverMatch is the class nogo uses for patterns which can never match, e.g. "a//b" or "[/]".
+const neverMatch = `[^\x00-\x{10ffff}]`
+
+// Lint analyses the content of an ignore file in the folder prefix and
+// returns all issues ordered by line.
+func Lint(prefix string, data []byte) []Issue {
+	var issues []Issue
+	var rules []nogo.Rule
+	ruleLines := make(map[*regexp.Regexp]int)
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	for i, line := range strings.Split(string(data), "\n") {
+		number := i + 1
+		line = strings.TrimSuffix(line, "\r")
+		issue := func(severity Severity, code, format string, args ...interface{}) {
+			issues = append(issues, Issue{
+				Line:     number,
+				Pattern:  line,
+				Severity: severity,
+				Code:     code,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		regexSrc, meta, err := nogo.Translate(line)
+		if meta.Skip {
+			continue
+		}
+		issues = append(issues, whitespaceIssues(number, line)...)
+		var syntaxErr *syntax.Error
+		switch {
+		case errors.As(err, &syntaxErr) && syntaxErr.Code == syntax.ErrMissingBracket:
+			issue(SeverityError, CodeNeverMatches, "unclosed range, the pattern never matches")
+			continue
+		case err != nil:
+			issue(SeverityError, CodeNeverMatches, "%v", err)
+			continue
+		case strings.Contains(regexSrc, neverMatch):
+			issue(SeverityError, CodeNeverMatches, "the pattern never matches any path")
+			continue
+		}
+
+		_, rule, err := nogo.Compile(prefix, line)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, rule)
+		ruleLines[rule.Regexp[0]] = number
+	}
+
+	_, removed := nogo.OptimizeReport(rules)
+	for _, r := range removed {
+		var message string
+		switch r.Reason {
+		case nogo.ReasonDuplicate:
+			message = "duplicate of line %d"
+		case nogo.ReasonShadowed:
+			message = "line %d matches every path this rule matches"
+		default:
+			message = "line %d already excludes the folder"
+		}
+		issues = append(issues, Issue{
+			Line:     ruleLines[r.Rule.Regexp[0]],
+			Pattern:  r.Rule.Pattern,
+			Severity: SeverityInfo,
+			Code:     CodeRedundant,
+			Message:  fmt.Sprintf(message, ruleLines[r.By.Regexp[0]]),
+		})
+	}
+
+	n := nogo.New(nogo.WithRules(rules...))
+	for _, rule := range rules {
+		if !rule.Negate {
+			continue
+		}
+		for _, parent := range literalParents(prefix, rule.Pattern) {
+			if match, because := n.MatchBecause(parent, true); match {
+				issues = append(issues, Issue{
+					Line:     ruleLines[rule.Regexp[0]],
+					Pattern:  rule.Pattern,
+					Severity: SeverityWarning,
+					Code:     CodeIneffectiveNegation,
+					Message: fmt.Sprintf("the folder %q is excluded by line %d, so nothing inside of it can be re-included",
+						parent, ruleLines[because.Regexp[0]]),
+				})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// LintFile reads the ignore file from fsys and lints it using its folder as prefix.
+func LintFile(fsys fs.FS, name string) ([]Issue, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := path.Dir(name)
+	if prefix == "." {
+		prefix = ""
+	}
+	return Lint(prefix, data), nil
+}
+
+// whitespaceIssues checks the end of a line which contains a pattern.
+func whitespaceIssues(number int, line string) []Issue {
+	trimmed := strings.TrimRight(line, " ")
+	escaped := strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(line)
+
+	var issues []Issue
+	switch {
+	case len(trimmed) < len(line) && !escaped:
+		issues = append(issues, Issue{
+			Line:     number,
+			Pattern:  line,
+			Severity: SeverityWarning,
+			Code:     CodeTrailingSpace,
+			Message:  `trailing spaces are removed, escape them using "\ " if they are part of the name`,
+		})
+	case strings.HasSuffix(line, "\t"):
+		issues = append(issues, Issue{
+			Line:     number,
+			Pattern:  line,
+			Severity: SeverityWarning,
+			Code:     CodeTrailingSpace,
+			Message:  "trailing tabs are part of the pattern",
+		})
+	}
+
+	if !escaped && strings.HasSuffix(trimmed, `\`) && !strings.HasSuffix(trimmed, `\\`) {
+		issues = append(issues, Issue{
+			Line:     number,
+			Pattern:  line,
+			Severity: SeverityWarning,
+			Code:     CodeTrailingBackslash,
+			Message:  "trailing backslash doesn't escape anything",
+		})
+	}
+	return issues
+}
+
+// literalParents returns the folders which contain all paths a negated
+// pattern can match, e.g. "foo" and "foo/bar" for "!foo/bar/*.txt".
+// Only leading segments without wildcards are used, so unanchored patterns
+// don't have any parents.
+func literalParents(prefix string, pattern string) []string {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "!")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		return nil
+	}
+
+	segments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	var parents []string
+	parent := strings.Trim(prefix, "/")
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == "" || strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		parent = path.Join(parent, segment)
+		parents = append(parents, parent)
+	}
+	return parents
+}
@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Issue
+	}{
+		{
+			name: "no issues",
+			data: "# comment\n\n*.log\n!keep.log\n/build/\n",
+		},
+		{
+			name: "never matches",
+			data: "file[a-z\na//b\n[/]\n",
+			want: []Issue{
+				{Line: 1, Pattern: "file[a-z", Severity: SeverityError, Code: CodeNeverMatches, Message: "unclosed range, the pattern never matches"},
+				{Line: 2, Pattern: "a//b", Severity: SeverityError, Code: CodeNeverMatches, Message: "the pattern never matches any path"},
+				{Line: 3, Pattern: "[/]", Severity: SeverityError, Code: CodeNeverMatches, Message: "the pattern never matches any path"},
+			},
+		},
+		{
+			name: "redundant",
+			data: "debug.log\n*.log\nbuild/\nbuild/\n/out\n/out/**\n",
+			want: []Issue{
+				{Line: 1, Pattern: "debug.log", Severity: SeverityInfo, Code: CodeRedundant, Message: "line 2 matches every path this rule matches"},
+				{Line: 3, Pattern: "build/", Severity: SeverityInfo, Code: CodeRedundant, Message: "duplicate of line 4"},
+				{Line: 6, Pattern: "/out/**", Severity: SeverityInfo, Code: CodeRedundant, Message: "line 5 already excludes the folder"},
+			},
+		},
+		{
+			name: "ineffective negation",
+			data: "vendor/\n!vendor/keep/*.go\nlogs/*\n!logs/keep\n",
+			want: []Issue{
+				{Line: 2, Pattern: "!vendor/keep/*.go", Severity: SeverityWarning, Code: CodeIneffectiveNegation, Message: `the folder "vendor" is excluded by line 1, so nothing inside of it can be re-included`},
+			},
+		},
+		{
+			name: "trailing whitespace",
+			data: "a.txt  \nb.txt\\ \nc.txt\t\nd\\\ne\\\\\n",
+			want: []Issue{
+				{Line: 1, Pattern: "a.txt  ", Severity: SeverityWarning, Code: CodeTrailingSpace, Message: `trailing spaces are removed, escape them using "\ " if they are part of the name`},
+				{Line: 3, Pattern: "c.txt\t", Severity: SeverityWarning, Code: CodeTrailingSpace, Message: "trailing tabs are part of the pattern"},
+				{Line: 4, Pattern: "d\\", Severity: SeverityWarning, Code: CodeTrailingBackslash, Message: "trailing backslash doesn't escape anything"},
+				{Line: 4, Pattern: "d\\", Severity: SeverityError, Code: CodeNeverMatches, Message: "the pattern never matches any path"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lint("", []byte(tt.data)))
+		})
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sub/.gitignore": {Data: []byte("/build\n/build\n")},
+	}
+
+	issues, err := LintFile(fsys, "sub/.gitignore")
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 1, issues[0].Line)
+	assert.Equal(t, "line 1: info: duplicate of line 2 (redundant)", issues[0].String())
+
+	_, err = LintFile(fsys, "missing/.gitignore")
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// Logger is used by WithLogger to trace how rules are compiled and how paths
+// are matched. The arguments are alternating keys and values.
+// It is implemented by *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// WithLogger logs each added rule with the regexps it was compiled to and
+// each match with the rules which matched the path and the decision.
+// This helps to debug why a path is or isn't ignored.
+//
+// As it logs a lot, it should only be used for debugging.
+func WithLogger(logger Logger) Option {
+	return optionFunc(func(n *NoGo) {
+		n.logger = logger
+	})
+}
+
+// logRules logs the compiled rules of a group.
+func (n *NoGo) logRules(g group) {
+	if n.logger == nil {
+		return
+	}
+
+	for _, rule := range g.rules {
+		regexps := make([]string, len(rule.Regexp))
+		for i, r := range rule.Regexp {
+			regexps[i] = r.String()
+		}
+
+		n.logger.Debug("nogo: rule added",
+			"prefix", g.prefix,
+			"pattern", rule.Pattern,
+			"negate", rule.Negate,
+			"onlyFolder", rule.OnlyFolder,
+			"regexp", strings.Join(regexps, " && "),
+		)
+	}
+}
+
+// logRuleMatch logs a rule which matched the path.
+func (n *NoGo) logRuleMatch(path string, isDir bool, because Result) {
+	n.logger.Debug("nogo: rule matched",
+		"path", path,
+		"isDir", isDir,
+		"prefix", because.Prefix,
+		"pattern", because.Pattern,
+		"negate", because.Negate,
+	)
+}
+
+// logMatch logs the decision for a path.
+func (n *NoGo) logMatch(path string, isDir bool, match bool, because Result, cached bool) {
+	if n.logger == nil {
+		return
+	}
+
+	n.logger.Debug("nogo: match",
+		"path", path,
+		"isDir", isDir,
+		"ignored", match,
+		"prefix", because.Prefix,
+		"pattern", because.Pattern,
+		"parentMatch", because.ParentMatch,
+		"cached", cached,
+	)
+}
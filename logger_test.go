@@ -0,0 +1,64 @@
+package nogo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) {
+	for _, arg := range args {
+		msg += fmt.Sprintf(" %v", arg)
+	}
+	l.messages = append(l.messages, msg)
+}
+
+func TestWithLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "compile and match",
+			paths: []string{"build/out.txt", "main.go"},
+			want: []string{
+				"nogo: rule added prefix  pattern build negate false onlyFolder false regexp ^(.*/)?build$",
+				"nogo: rule matched path build isDir true prefix  pattern build negate false",
+				"nogo: match path build/out.txt isDir false ignored true prefix  pattern build parentMatch true cached false",
+				"nogo: match path main.go isDir false ignored false prefix  pattern  parentMatch false cached false",
+			},
+		},
+		{
+			name:  "cached",
+			opts:  []Option{WithCache(10)},
+			paths: []string{"build", "build"},
+			want: []string{
+				"nogo: rule added prefix  pattern build negate false onlyFolder false regexp ^(.*/)?build$",
+				"nogo: rule matched path build isDir false prefix  pattern build negate false",
+				"nogo: match path build isDir false ignored true prefix  pattern build parentMatch false cached false",
+				"nogo: match path build isDir false ignored true prefix  pattern build parentMatch false cached true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			n := New(append(tt.opts, WithLogger(logger))...)
+			require.NoError(t, n.AddPatterns("", []string{"build"}))
+
+			for _, path := range tt.paths {
+				n.Match(path, false)
+			}
+			assert.Equal(t, tt.want, logger.messages)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package nogo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ManifestEntry is a single file of a Manifest.
+type ManifestEntry struct {
+	Path string
+	// Hash is the hex encoded SHA-256 of the content of the file.
+	// For symlinks it is the SHA-256 of the target, if the fs.FS implements ReadLinkFS.
+	Hash string
+}
+
+// Manifest contains all files which are not ignored with their hashes,
+// in lexical order.
+type Manifest []ManifestEntry
+
+// WriteTo writes one line per file in the format of sha256sum,
+// so the manifest can be checked using "sha256sum -c".
+func (m Manifest) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, entry := range m {
+		n, err := fmt.Fprintf(w, "%s  %s\n", entry.Hash, entry.Path)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Hash returns the hex encoded SHA-256 of the output of WriteTo.
+// It changes whenever a file is added, removed, renamed or changed,
+// so it can be used as cache key of the files.
+func (m Manifest) Hash() string {
+	h := sha256.New()
+	_, _ = m.WriteTo(h)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BuildManifest walks the file tree using a new NoGo instance which lazily
+// loads all .gitignore files inside of the root and hashes all files which
+// are not ignored. See NoGo.BuildManifest for details.
+func BuildManifest(fsys fs.FS, root string, workers int, opts ...WalkOption) (Manifest, error) {
+	opts = append([]WalkOption{WithIgnoreFile(".gitignore")}, opts...)
+	return New().BuildManifest(fsys, root, workers, opts...)
+}
+
+// BuildManifest walks the file tree the same way as WalkDir and returns the
+// SHA-256 of all files which are not ignored. The result is deterministic,
+// as it doesn't depend on the order in which the files get hashed.
+//
+// The files are hashed by a pool of workers.
+// If workers <= 0, runtime.GOMAXPROCS(0) workers are used.
+// The first error stops the hashing and is returned.
+func (n *NoGo) BuildManifest(fsys fs.FS, root string, workers int, opts ...WalkOption) (Manifest, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var manifest Manifest
+	var types []fs.FileMode
+	err := n.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		manifest = append(manifest, ManifestEntry{Path: path})
+		types = append(types, d.Type())
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		failed   atomic.Bool
+		firstErr error
+		errOnce  sync.Once
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if failed.Load() {
+					continue
+				}
+
+				hash, err := hashFile(fsys, manifest[i].Path, types[i])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						failed.Store(true)
+					})
+					continue
+				}
+				manifest[i].Hash = hash
+			}
+		}()
+	}
+
+	for i := range manifest {
+		if failed.Load() {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return manifest, nil
+}
+
+// hashFile returns the hex encoded SHA-256 of the file.
+func hashFile(fsys fs.FS, name string, mode fs.FileMode) (string, error) {
+	h := sha256.New()
+	if linkFS, ok := fsys.(ReadLinkFS); ok && mode&fs.ModeSymlink != 0 {
+		target, err := linkFS.ReadLink(name)
+		if err != nil {
+			return "", err
+		}
+		_, _ = io.WriteString(h, target)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+package nogo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBuildManifest(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+
+	want := Manifest{
+		{Path: ".gitignore", Hash: sha256Hex("*.log\n/build\n")},
+		{Path: "main.go", Hash: sha256Hex("package main")},
+		{Path: "sub/.gitignore", Hash: sha256Hex("secret.txt")},
+		{Path: "sub/deeper/keep.md", Hash: sha256Hex("keep")},
+		{Path: "sub/public.txt", Hash: sha256Hex("public")},
+	}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		got, err := BuildManifest(fsys, ".", workers)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestNoGo_BuildManifest(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	got, err := n.BuildManifest(fsys, "sub/deeper", 2)
+	require.NoError(t, err)
+	assert.Equal(t, Manifest{
+		{Path: "sub/deeper/keep.md", Hash: sha256Hex("keep")},
+	}, got)
+
+	_, err = n.BuildManifest(ForbiddenFS{fsys, map[string]struct{}{"sub/public.txt": {}}}, ".", 2)
+	assert.ErrorIs(t, err, ErrShouldNotBeReached)
+}
+
+func TestBuildManifest_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0o644))
+	if err := os.Symlink("file.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+
+	got, err := BuildManifest(readLinkDirFS{FS: os.DirFS(dir), dir: dir}, ".", 1)
+	require.NoError(t, err)
+	assert.Equal(t, Manifest{
+		{Path: "file.txt", Hash: sha256Hex("content")},
+		{Path: "link", Hash: sha256Hex("file.txt")},
+	}, got)
+
+	// Without ReadLinkFS the content of the target is hashed.
+	got, err = BuildManifest(struct{ fs.FS }{os.DirFS(dir)}, ".", 1)
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex("content"), got[1].Hash)
+}
+
+func TestManifest_WriteTo(t *testing.T) {
+	m := Manifest{
+		{Path: "a.txt", Hash: sha256Hex("a")},
+		{Path: "dir/b.txt", Hash: sha256Hex("b")},
+	}
+
+	var buf bytes.Buffer
+	written, err := m.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+	assert.Equal(t, sha256Hex("a")+"  a.txt\n"+sha256Hex("b")+"  dir/b.txt\n", buf.String())
+
+	assert.Equal(t, sha256Hex(buf.String()), m.Hash())
+}
+
+func TestManifest_Hash(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("a")},
+	}
+	before, err := BuildManifest(fsys, ".", 1)
+	require.NoError(t, err)
+
+	fsys["a.txt"].Data = []byte("changed")
+	after, err := BuildManifest(fsys, ".", 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Hash(), after.Hash())
+
+	fsys["a.txt"].Data = []byte("a")
+	fsys["ignored.log"] = &fstest.MapFile{Data: []byte("log")}
+	fsys[".gitignore"] = &fstest.MapFile{Data: []byte("*.log\n.gitignore\n")}
+	withIgnored, err := BuildManifest(fsys, ".", 1)
+	require.NoError(t, err)
+	assert.Equal(t, before.Hash(), withIgnored.Hash())
+}
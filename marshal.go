@@ -0,0 +1,219 @@
+package nogo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// marshalMagic is at the start of all data created by MarshalBinary.
+const marshalMagic = "NOGO"
+
+// marshalVersion has to be increased whenever the format of MarshalBinary
+// or the way rules are compiled to regexps changes.
+// Data with another version is rejected by UnmarshalBinary.
+const marshalVersion = 4
+
+// ErrIncompatibleVersion is returned by UnmarshalBinary if the data was
+// created by an incompatible version of nogo.
+// Stale caches should just be re-created in this case.
+var ErrIncompatibleVersion = errors.New("nogo: incompatible version of marshaled rules")
+
+// MarshalBinary encodes all loaded rules including the compiled regexps.
+// It can be used to persist the rules, e.g. to a cache file,
+// so that they can be loaded without reading and compiling all ignore files again.
+//
+// Only the rules are encoded, options such as WithCache are not.
+func (n *NoGo) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(marshalMagic)
+	writeUvarint(&buf, marshalVersion)
+	writeGroups(&buf, n.groups)
+	return buf.Bytes(), nil
+}
+
+func writeGroups(buf *bytes.Buffer, groups []group) {
+	writeUvarint(buf, uint64(len(groups)))
+	for _, g := range groups {
+		writeString(buf, g.prefix)
+		writeString(buf, g.source)
+		writeString(buf, g.tag)
+		writeUvarint(buf, uint64(len(g.rules)))
+		for _, rule := range g.rules {
+			writeString(buf, rule.Prefix)
+			writeString(buf, rule.Pattern)
+			writeBool(buf, rule.Negate)
+			writeBool(buf, rule.OnlyFolder)
+			writeUvarint(buf, uint64(len(rule.Regexp)))
+			for _, reg := range rule.Regexp {
+				writeString(buf, reg.String())
+			}
+		}
+	}
+}
+
+// UnmarshalBinary replaces all rules by the rules encoded by MarshalBinary.
+// The options of the NoGo instance are kept.
+//
+// If the data was created by an incompatible version of nogo,
+// ErrIncompatibleVersion is returned.
+func (n *NoGo) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(marshalMagic)) {
+		return errors.New("nogo: invalid marshaled rules")
+	}
+	r := bytes.NewReader(data[len(marshalMagic):])
+
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return unmarshalError(err)
+	}
+	if version != marshalVersion {
+		return fmt.Errorf("%w: got version %d, expected %d", ErrIncompatibleVersion, version, marshalVersion)
+	}
+
+	groupCount, err := readLength(r)
+	if err != nil {
+		return unmarshalError(err)
+	}
+
+	groups := make([]group, 0, groupCount)
+	for i := 0; i < groupCount; i++ {
+		var g group
+		if g.prefix, err = readString(r); err != nil {
+			return unmarshalError(err)
+		}
+		if g.source, err = readString(r); err != nil {
+			return unmarshalError(err)
+		}
+		if g.tag, err = readString(r); err != nil {
+			return unmarshalError(err)
+		}
+
+		ruleCount, err := readLength(r)
+		if err != nil {
+			return unmarshalError(err)
+		}
+
+		g.rules = make([]Rule, 0, ruleCount)
+		for j := 0; j < ruleCount; j++ {
+			rule, err := readRule(r)
+			if err != nil {
+				return unmarshalError(err)
+			}
+			g.rules = append(g.rules, rule)
+		}
+		groups = append(groups, g)
+	}
+
+	if r.Len() != 0 {
+		return unmarshalError(errors.New("unexpected data at the end"))
+	}
+
+	// Older versions didn't sort the groups.
+	sortGroups(groups)
+	n.groups = groups
+	n.changed()
+	return nil
+}
+
+func readRule(r *bytes.Reader) (rule Rule, err error) {
+	if rule.Prefix, err = readString(r); err != nil {
+		return Rule{}, err
+	}
+	if rule.Pattern, err = readString(r); err != nil {
+		return Rule{}, err
+	}
+	if rule.Negate, err = readBool(r); err != nil {
+		return Rule{}, err
+	}
+	if rule.OnlyFolder, err = readBool(r); err != nil {
+		return Rule{}, err
+	}
+
+	regexpCount, err := readLength(r)
+	if err != nil {
+		return Rule{}, err
+	}
+	rule.Regexp = make([]*regexp.Regexp, 0, regexpCount)
+	for i := 0; i < regexpCount; i++ {
+		expr, err := readString(r)
+		if err != nil {
+			return Rule{}, err
+		}
+		reg, err := regexp.Compile(expr)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Regexp = append(rule.Regexp, reg)
+	}
+
+	return rule, nil
+}
+
+func unmarshalError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("nogo: invalid marshaled rules: %w", err)
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func writeBool(buf *bytes.Buffer, b bool) {
+	if b {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+}
+
+// readLength reads a length and checks that it is not bigger than the remaining data,
+// so that invalid data can't cause huge allocations.
+func readLength(r *bytes.Reader) (int, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(r.Len()) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(length), nil
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readLength(r)
+	if err != nil {
+		return "", err
+	}
+	s := make([]byte, length)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+func readBool(r *bytes.Reader) (bool, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid bool %d", b)
+	}
+}
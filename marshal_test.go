@@ -0,0 +1,116 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_MarshalBinary(t *testing.T) {
+	n := &NoGo{groups: TestFSGroups}
+	data, err := n.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded := New(WithCache(100))
+	require.NoError(t, loaded.UnmarshalBinary(data))
+	assert.NotNil(t, loaded.cache, "options must be kept")
+	require.Len(t, loaded.groups, len(TestFSGroups))
+
+	for i, g := range TestFSGroups {
+		assert.Equal(t, g.prefix, loaded.groups[i].prefix)
+		assert.Equal(t, g.source, loaded.groups[i].source)
+		require.Len(t, loaded.groups[i].rules, len(g.rules))
+		for j, rule := range g.rules {
+			got := loaded.groups[i].rules[j]
+			assert.Equal(t, rule.Prefix, got.Prefix)
+			assert.Equal(t, rule.Pattern, got.Pattern)
+			assert.Equal(t, rule.Negate, got.Negate)
+			assert.Equal(t, rule.OnlyFolder, got.OnlyFolder)
+			assert.Equal(t, len(rule.Regexp), len(got.Regexp))
+			for k, reg := range rule.Regexp {
+				assert.Equal(t, reg.String(), got.Regexp[k].String())
+			}
+		}
+	}
+
+	for path, tt := range TestFSData {
+		assert.Equal(t, n.Match(path, tt.isDir), loaded.Match(path, tt.isDir), path)
+	}
+
+	// Marshaling the loaded rules again must result in the same data.
+	again, err := loaded.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestNoGo_UnmarshalBinary(t *testing.T) {
+	valid, err := (&NoGo{groups: TestFSGroups}).MarshalBinary()
+	require.NoError(t, err)
+	header := marshalMagic + string(rune(marshalVersion))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "empty",
+			data:    []byte{},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid magic",
+			data:    append([]byte("OGON"), valid[4:]...),
+			wantErr: assert.Error,
+		},
+		{
+			name: "other version",
+			data: append([]byte(marshalMagic+string(rune(marshalVersion+1))), valid[5:]...),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrIncompatibleVersion, i...)
+			},
+		},
+		{
+			name:    "truncated",
+			data:    valid[:len(valid)-3],
+			wantErr: assert.Error,
+		},
+		{
+			name:    "additional data",
+			data:    append(append([]byte{}, valid...), 0),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "huge length",
+			data:    []byte(header + "\xff\xff\xff\xff\x0f"),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid regexp",
+			data:    []byte(header + "\x01\x00\x01\x00\x00\x00\x00\x01\x01("),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "no rules",
+			data:    []byte(header + "\x00"),
+			wantErr: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New(DotGitRule)
+			err := n.UnmarshalBinary(tt.data)
+			if !tt.wantErr(t, err) {
+				return
+			}
+
+			if err != nil {
+				// The rules must not be changed on error.
+				assert.Len(t, n.groups, 1)
+			} else {
+				assert.Empty(t, n.groups)
+			}
+		})
+	}
+}
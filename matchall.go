@@ -0,0 +1,89 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// matchAllEntry is the result of matchLast for a single path.
+type matchAllEntry struct {
+	because Result
+	found   bool
+}
+
+// MatchAll does the same as MatchBecause for many paths at once.
+// Paths ending with a '/' are directories (or a `\` if WithWindowsPaths is used).
+//
+// The rules for each parent folder are only checked once, even if it is the
+// parent of many paths. So it is much faster than calling MatchBecause for
+// each path if they are in the same folders.
+//
+// The matches and results are in the same order as the paths. The matches
+// are the same as Match returns, also for allow lists (see NewAllowList),
+// where they are true for excluded paths. As for MatchBecause, the results of
+// allow lists only describe the matched rule, so Result.Resolve must not be
+// used to get if a path of an allow list is excluded.
+//
+// If WithValidatePaths is used, invalid paths are never ignored, the same way
+// as with Match.
+func (n *NoGo) MatchAll(paths []string) (matches []bool, results []Result) {
+	matches = make([]bool, len(paths))
+	results = make([]Result, len(paths))
+	checked := make(map[cacheKey]matchAllEntry)
+
+	for i, path := range paths {
+		if n.windowsPaths {
+			path = strings.ReplaceAll(path, `\`, "/")
+		}
+		isDir := strings.HasSuffix(path, "/")
+		path = strings.TrimSuffix(path, "/")
+
+		matches[i], results[i] = n.matchAllPath(path, isDir, checked)
+	}
+
+	return matches, results
+}
+
+// matchAllPath matches a single path of MatchAll.
+// The checked map contains the results of the parents which were already checked.
+func (n *NoGo) matchAllPath(path string, isDir bool, checked map[cacheKey]matchAllEntry) (bool, Result) {
+	n.lock(path)
+	defer n.unlock()
+
+	path, ok := n.normalize(path)
+	if !ok {
+		return false, Result{}
+	}
+
+	path = cleanPath(path)
+
+	var because Result
+	end := segmentEnd(path, 0)
+	for {
+		// All parents are directories.
+		last := end == len(path)
+		key := cacheKey{path: path[:end], isDir: isDir || !last}
+		entry, ok := checked[key]
+		if !ok {
+			entry.because, entry.found = n.matchLast(key.path, key.isDir)
+			checked[key] = entry
+		}
+
+		if entry.found {
+			because = entry.because
+			because.ParentMatch = !last
+			if n.parentExcluded(because, last) {
+				break
+			}
+		}
+
+		if last {
+			break
+		}
+		end = segmentEnd(path, end+1)
+	}
+
+	if n.allowList {
+		return n.excluded(path, isDir, because), because
+	}
+	return because.Resolve(isDir), because
+}
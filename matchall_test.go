@@ -0,0 +1,80 @@
+package nogo
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_MatchAll(t *testing.T) {
+	var paths []string
+	for path, tt := range TestFSData {
+		if tt.isDir {
+			path += "/"
+		}
+		paths = append(paths, path)
+	}
+	// Make sure that the order does not matter.
+	sort.Strings(paths)
+
+	for _, compile := range []bool{false, true} {
+		n := &NoGo{groups: TestFSGroups}
+		if compile {
+			n.Compile()
+		}
+
+		matches, results := n.MatchAll(paths)
+		require.Len(t, matches, len(paths))
+		require.Len(t, results, len(paths))
+
+		for i, path := range paths {
+			path = strings.TrimSuffix(path, "/")
+			tt := TestFSData[path]
+
+			wantMatch, wantBecause := n.MatchBecause(path, tt.isDir)
+			assert.Equal(t, wantBecause, results[i], path)
+			assert.Equal(t, wantMatch, matches[i], path)
+			assert.Equal(t, wantMatch, results[i].Resolve(tt.isDir), path)
+		}
+	}
+}
+
+func TestNoGo_MatchAll_Directories(t *testing.T) {
+	n := New()
+	n.AddRules(MustCompileAll("", []byte("build/\n!build/keep"))...)
+
+	matches, results := n.MatchAll([]string{"build/", "build", "a/build/", "src/build/sub/"})
+	assert.Equal(t, []bool{true, false, true, true}, matches)
+	assert.True(t, results[0].Resolve(true))
+	assert.False(t, results[1].Resolve(false))
+	assert.True(t, results[2].Resolve(true))
+	assert.True(t, results[3].ParentMatch)
+
+	matches, results = n.MatchAll(nil)
+	assert.Empty(t, matches)
+	assert.Empty(t, results)
+}
+
+func TestNoGo_MatchAll_AllowList(t *testing.T) {
+	paths := []string{"a.go", "a.txt", "src/", "src/a.go", "src/a.txt", "docs/", "docs/a.md", "src/testdata/", "src/testdata/a.go"}
+
+	for _, compile := range []bool{false, true} {
+		n := NewAllowList()
+		n.AddRules(MustCompileAll("", []byte("*.go\n/docs\n!src/testdata"))...)
+		if compile {
+			n.Compile()
+		}
+
+		matches, results := n.MatchAll(paths)
+		for i, path := range paths {
+			isDir := strings.HasSuffix(path, "/")
+			wantMatch, wantBecause := n.MatchBecause(strings.TrimSuffix(path, "/"), isDir)
+			assert.Equal(t, wantMatch, matches[i], "%v: %s", compile, path)
+			assert.Equal(t, wantBecause, results[i], "%v: %s", compile, path)
+		}
+		assert.Equal(t, []bool{false, true, false, false, true, false, false, true, false}, matches, compile)
+	}
+}
@@ -0,0 +1,72 @@
+package nogo
+
+// MatchDir matches a directory and also tells whether a walker has to descend
+// into it: ignored is true if the directory itself is ignored and
+// mayContainUnignored is false if it is known that everything inside of it
+// is ignored.
+//
+// With the gitignore semantics nothing inside of an ignored directory can be
+// re-included, so mayContainUnignored is always false for ignored directories.
+// It is also false for directories which are not ignored themselves but all
+// of their content is, e.g. "build" for the rule "build/**".
+//
+// For allow lists (see NewAllowList), negated rules don't exclude everything
+// inside of a directory, so a later rule may include paths inside of an
+// excluded directory again, e.g. "src/testdata/keep" for the rules
+// "/src", "!/src/testdata" and "/src/testdata/keep". mayContainUnignored is
+// true if any rule may include a path inside of the directory.
+//
+// mayContainUnignored may be true even if nothing inside of the directory is
+// included, as not all rules can be analyzed.
+func (n *NoGo) MatchDir(path string) (ignored bool, mayContainUnignored bool) {
+	match, because := n.MatchBecause(path, true)
+
+	n.lock(path)
+	defer n.unlock()
+	normalized, ok := n.normalize(path)
+	if !ok {
+		// Invalid paths are never ignored.
+		return false, true
+	}
+
+	if n.allowList {
+		if because.Found && because.Resolve(true) {
+			return false, true
+		}
+		// Directories which may contain included paths are not matched.
+		return true, !match || mayAllowInside(normalized, n.allowed)
+	}
+
+	if match {
+		return true, false
+	}
+	return false, !n.ignoresContent(normalized)
+}
+
+// ignoresContent checks if a terminal rule ignores everything inside of the
+// directory, e.g. "build/**".
+func (n *NoGo) ignoresContent(dir string) bool {
+	if n.terminals == nil {
+		return false
+	}
+	self, ok := n.terminals.paths[dir]
+	return ok && !self
+}
+
+// mayAllowInside checks if any of the allowed rules may match a path inside
+// of the directory. Literal rules which only match the directory itself or one
+// of its parents are not checked, as they can't override the rule which
+// excluded the directory for the paths inside of it.
+func mayAllowInside(dir string, allowed []terminalRule) bool {
+	for _, rule := range allowed {
+		switch {
+		case isPathInside(dir, rule.path) && (rule.unanchored || !rule.literal):
+			return true
+		case rule.path == dir && !rule.self:
+			return true
+		case rule.path != dir && isPathInside(rule.path, dir):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoGo_MatchDir(t *testing.T) {
+	type verdict struct {
+		ignored             bool
+		mayContainUnignored bool
+	}
+
+	tests := []struct {
+		name      string
+		allowList bool
+		rules     string
+		want      map[string]verdict
+	}{
+		{
+			name:  "gitignore",
+			rules: "/build\ncache/**\n!cache/**/keep\ndist/**\n*.log\nvendor/*\n!vendor/keep\n",
+			want: map[string]verdict{
+				"build":     {ignored: true, mayContainUnignored: false},
+				"build/sub": {ignored: true, mayContainUnignored: false},
+				"src":       {ignored: false, mayContainUnignored: true},
+				"dist":      {ignored: false, mayContainUnignored: false},
+				"dist/sub":  {ignored: true, mayContainUnignored: false},
+				"cache":     {ignored: false, mayContainUnignored: true},
+				"logs.log":  {ignored: true, mayContainUnignored: false},
+				"vendor":    {ignored: false, mayContainUnignored: true},
+			},
+		},
+		{
+			name:      "allow list",
+			allowList: true,
+			rules:     "/src\n!/src/testdata\n/src/testdata/keep\n!/src/generated\n/docs/**/*.md\n",
+			want: map[string]verdict{
+				"src":                {ignored: false, mayContainUnignored: true},
+				"src/testdata":       {ignored: true, mayContainUnignored: true},
+				"src/generated":      {ignored: true, mayContainUnignored: false},
+				"src/testdata/other": {ignored: true, mayContainUnignored: false},
+				"docs":               {ignored: true, mayContainUnignored: true},
+				"docs/api":           {ignored: true, mayContainUnignored: true},
+				"other":              {ignored: true, mayContainUnignored: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New()
+			if tt.allowList {
+				n = NewAllowList()
+			}
+			n.AddRules(MustCompileAll("", []byte(tt.rules))...)
+
+			for path, want := range tt.want {
+				ignored, mayContainUnignored := n.MatchDir(path)
+				assert.Equal(t, want, verdict{ignored, mayContainUnignored}, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package nogo
+
+// MatchPattern checks if the gitignore pattern ignores the path, without
+// creating a NoGo instance, e.g. for config loaders and small tools which
+// only need a quick check. The path is relative to the folder of the pattern.
+//
+// Like in an ignore file, everything inside of a matched directory is
+// ignored, too, so "build/" matches "build/main.o". A negated pattern alone
+// never ignores anything.
+//
+// It returns a *PatternError if the pattern is invalid.
+func MatchPattern(pattern, path string, isDir bool) (bool, error) {
+	return MatchPatterns([]string{pattern}, path, isDir)
+}
+
+// MatchPatterns does the same as MatchPattern for several patterns, which
+// are handled like the lines of a single ignore file. So a later negated
+// pattern can include a path again:
+//
+//	ignored, err := nogo.MatchPatterns([]string{"*.log", "!keep.log"}, "keep.log", false) // false
+//
+// If many paths have to be checked, creating a NoGo once is much faster,
+// as the patterns are compiled for each call.
+func MatchPatterns(patterns []string, path string, isDir bool) (bool, error) {
+	rules, err := DialectGitignore.compileLines("", patterns)
+	if err != nil {
+		return false, err
+	}
+	return New(WithRules(rules...)).Match(path, isDir), nil
+}
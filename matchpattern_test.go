@@ -0,0 +1,68 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+		wantErr bool
+	}{
+		{pattern: "*.log", path: "debug.log", want: true},
+		{pattern: "*.log", path: "logs/debug.log", want: true},
+		{pattern: "*.log", path: "main.go", want: false},
+		{pattern: "/build", path: "sub/build", want: false},
+		{pattern: "build/", path: "build", isDir: false, want: false},
+		{pattern: "build/", path: "build", isDir: true, want: true},
+		{pattern: "build/", path: "build/main.o", want: true},
+		{pattern: "!keep.log", path: "keep.log", want: false},
+		{pattern: "# comment", path: "# comment", want: false},
+		{pattern: "[", path: "[", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			got, err := MatchPattern(tt.pattern, tt.path, tt.isDir)
+			if tt.wantErr {
+				var patternErr *PatternError
+				assert.ErrorAs(t, err, &patternErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchPatterns(t *testing.T) {
+	patterns := []string{"*.log", "!keep.log", "tmp/"}
+
+	tests := map[string]bool{
+		"debug.log":     true,
+		"keep.log":      false,
+		"sub/keep.log":  false,
+		"tmp/keep.log":  true,
+		"main.go":       false,
+		"sub/debug.log": true,
+	}
+	for path, want := range tests {
+		got, err := MatchPatterns(patterns, path, false)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, path)
+	}
+
+	_, err := MatchPatterns([]string{"*.log", "a[", "b"}, "a.log", false)
+	var patternErr *PatternError
+	require.ErrorAs(t, err, &patternErr)
+	assert.Equal(t, 2, patternErr.Line)
+
+	got, err := MatchPatterns(nil, "a.log", false)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
@@ -0,0 +1,69 @@
+package nogo
+
+// MergeOrder defines which rules take precedence when merging two NoGo instances.
+type MergeOrder int
+
+const (
+	// MergeAppend checks the rules of the second NoGo after the rules of the
+	// first one, so they take precedence, like ignore files which are added later.
+	MergeAppend MergeOrder = iota
+
+	// MergePrepend checks the rules of the second NoGo before the rules of the
+	// first one, so the first one takes precedence.
+	MergePrepend
+)
+
+func (o MergeOrder) String() string {
+	switch o {
+	case MergeAppend:
+		return "append"
+	case MergePrepend:
+		return "prepend"
+	default:
+		return "unknown"
+	}
+}
+
+// Merge combines the rules of a and b into a new NoGo, e.g. to layer built-in
+// defaults, the config of the user and the ignore files of a repository:
+//
+//	n := nogo.Merge(nogo.Merge(defaults, user, nogo.MergeAppend), repo, nogo.MergeAppend)
+//
+// As always, the rules of deeper folders take precedence over the rules of their
+// parents. The order only decides which rules win for the same folder.
+//
+// The options of a are used for the new NoGo, except WithFS and WithStatFS
+// (see Rebase). So both should be created with the same options, especially
+// WithIgnoreCase. Of instances which load their ignore files lazily, only the
+// rules which were already loaded are merged.
+func Merge(a, b *NoGo, order MergeOrder) *NoGo {
+	first, second := a.groups, b.groups
+	if order == MergePrepend {
+		first, second = second, first
+	}
+
+	groups := make([]group, 0, len(first)+len(second))
+	for _, g := range first {
+		groups = append(groups, g.copy())
+	}
+	for _, g := range second {
+		groups = append(groups, g.copy())
+	}
+
+	// Groups with the same depth keep their order.
+	sortGroups(groups)
+	merged := a.derive(groups)
+	merged.copyLoads(b)
+	return merged
+}
+
+// copy returns a copy of the group without its stats.
+func (g group) copy() group {
+	return group{
+		prefix: g.prefix,
+		rules:  append([]Rule(nil), g.rules...),
+		source: g.source,
+		folded: g.folded,
+		tag:    g.tag,
+	}
+}
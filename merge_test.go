@@ -0,0 +1,65 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	defaults := New(DotGitRule)
+	require.NoError(t, defaults.AddPatterns("", []string{"*.log", "/build"}))
+
+	repo := New()
+	require.NoError(t, repo.AddPatterns("", []string{"!debug.log", "*.tmp"}))
+	require.NoError(t, repo.AddPatterns("sub", []string{"!*.tmp"}))
+
+	tests := []struct {
+		name  string
+		order MergeOrder
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{name: "append first", order: MergeAppend, path: "a.log", want: true},
+		{name: "append second", order: MergeAppend, path: "a.tmp", want: true},
+		{name: "append negation wins", order: MergeAppend, path: "debug.log", want: false},
+		{name: "append deeper wins", order: MergeAppend, path: "sub/a.tmp", want: false},
+		{name: "append dot git", order: MergeAppend, path: ".git", isDir: true, want: true},
+		{name: "prepend negation loses", order: MergePrepend, path: "debug.log", want: true},
+		{name: "prepend deeper wins", order: MergePrepend, path: "sub/a.tmp", want: false},
+		{name: "prepend second", order: MergePrepend, path: "a.tmp", want: true},
+		{name: "prepend first", order: MergePrepend, path: "build", isDir: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Merge(defaults, repo, tt.order)
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestMerge_Copy(t *testing.T) {
+	a := New(WithCache(10))
+	require.NoError(t, a.AddPatterns("", []string{"*.log"}))
+	b := New()
+	require.NoError(t, b.AddPatterns("", []string{"*.tmp"}))
+
+	n := Merge(a, b, MergeAppend)
+	assert.NotNil(t, n.cache, "options of a must be kept")
+
+	// Changing the merged NoGo doesn't change the original ones.
+	n.ReplaceGroup("", nil)
+	assert.False(t, n.Match("a.log", false))
+	assert.True(t, a.Match("a.log", false))
+	assert.True(t, b.Match("a.tmp", false))
+	assert.Len(t, a.Groups(), 1)
+}
+
+func TestMergeOrder_String(t *testing.T) {
+	assert.Equal(t, "append", MergeAppend.String())
+	assert.Equal(t, "prepend", MergePrepend.String())
+	assert.Equal(t, "unknown", MergeOrder(42).String())
+}
@@ -0,0 +1,39 @@
+package nogo
+
+// HasNegations checks if any rule is negated ("!pattern"). This is the common
+// case for most repositories. Without negations nothing inside of an ignored
+// directory can be included again, so walkers can safely skip it and caches
+// can store the result of a directory for all of its content.
+//
+// Rules of tags disabled by DisableTag are not checked.
+// With WithLazyLoading only the ignore files loaded so far are checked.
+func (n *NoGo) HasNegations() bool {
+	return n.hasNegations(func(g group) bool { return true })
+}
+
+// HasNegationsIn does the same as HasNegations but only checks the rules which
+// may apply to dir or the paths inside of it. These are the rules of dir, its
+// parents and its sub directories. Use "" for the root.
+func (n *NoGo) HasNegationsIn(dir string) bool {
+	dir = n.groupPrefix(dir)
+	if dir == "." {
+		dir = ""
+	}
+	return n.hasNegations(func(g group) bool {
+		return isPathInside(dir, g.prefix) || isPathInside(g.prefix, dir)
+	})
+}
+
+func (n *NoGo) hasNegations(include func(g group) bool) bool {
+	for _, g := range n.groups {
+		if g.tag != "" && n.disabledTags[g.tag] || !include(g) {
+			continue
+		}
+		for _, rule := range g.rules {
+			if rule.Negate {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_HasNegations(t *testing.T) {
+	n := New()
+	require.NoError(t, n.AddFromFS(fstest.MapFS{
+		".gitignore":         {Data: []byte("*.log\n")},
+		"a/.gitignore":       {Data: []byte("build/\n")},
+		"b/c/.gitignore":     {Data: []byte("*.tmp\n!keep.tmp\n")},
+		"bc/file":            {},
+		"b/c/d/e/file":       {},
+		"other/.gitignore":   {Data: []byte("# comment\n")},
+		"other/sub/file.txt": {},
+	}, ".gitignore"))
+
+	assert.True(t, n.HasNegations())
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{dir: "", want: true},
+		{dir: ".", want: true},
+		{dir: "a", want: false},
+		{dir: "b", want: true},
+		{dir: "b/c", want: true},
+		{dir: "b/c/d", want: true},
+		{dir: "bc", want: false},
+		{dir: "other/sub", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.HasNegationsIn(tt.dir))
+		})
+	}
+}
+
+func TestNoGo_HasNegations_tags(t *testing.T) {
+	n := New(WithRules(MustCompileAll("", []byte("*.log"))...))
+	assert.False(t, n.HasNegations())
+
+	n.AddRulesTagged("user", MustCompileAll("", []byte("!keep.log"))...)
+	assert.True(t, n.HasNegations())
+
+	n.DisableTag("user")
+	assert.False(t, n.HasNegations())
+	assert.False(t, n.HasNegationsIn("sub"))
+}
@@ -0,0 +1,108 @@
+package nogo
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// RuleSpec describes a Rule which is built from raw regexps by NewRule.
+type RuleSpec struct {
+	// Prefix is the folder of the rule, see Rule.Prefix.
+	Prefix string
+
+	// Regexps all have to match a path to match the rule.
+	// They match the full path including the Prefix and are anchored at
+	// both ends, so "^" and "$" are not needed.
+	// Like the regexps of patterns, they match byte by byte (see Rule.Regexp).
+	Regexps []string
+
+	// MatchSlash allows the regexps to match '/' using anything else than a
+	// literal '/', e.g. using "." or "[^a]". By default this is rejected,
+	// as '*' and '?' of patterns never match '/' either.
+	MatchSlash bool
+
+	Negate     bool
+	OnlyFolder bool
+}
+
+// NewRule creates a Rule from raw regexps, e.g. for rules which can't be
+// expressed as gitignore patterns. The regexps are validated and compiled
+// the same way as the ones of Compile.
+//
+// As the rule has no gitignore pattern, its Pattern is a comment which
+// contains the regexps. It is only meant to be displayed, e.g. as Result.Rule.
+// The GlobBackend always uses the regexps for such rules.
+//
+// Invalid regexps are returned as *PatternError and an invalid Prefix
+// as *InvalidPathError.
+func NewRule(spec RuleSpec) (Rule, error) {
+	prefix := strings.TrimSuffix(spec.Prefix, "/")
+	if prefix != "" {
+		if err := ValidatePath(prefix); err != nil {
+			return Rule{}, err
+		}
+	}
+	if len(spec.Regexps) == 0 {
+		return Rule{}, &PatternError{Cause: errors.New("no regexp")}
+	}
+
+	rule := Rule{
+		Prefix:     prefix,
+		Pattern:    "# regexp: " + strings.Join(spec.Regexps, " && "),
+		Negate:     spec.Negate,
+		OnlyFolder: spec.OnlyFolder,
+	}
+	for _, expr := range spec.Regexps {
+		anchored := ByteRunes("^(?:" + expr + ")$")
+		if !spec.MatchSlash {
+			parsed, err := syntax.Parse(anchored, syntax.Perl)
+			if err != nil {
+				return Rule{}, &PatternError{Pattern: expr, Cause: err}
+			}
+			if matchesSlash(parsed) {
+				return Rule{}, &PatternError{Pattern: expr, Cause: errors.New("the regexp may match '/', use a literal '/' or MatchSlash")}
+			}
+		}
+
+		reg, err := regexp.Compile(anchored)
+		if err != nil {
+			return Rule{}, &PatternError{Pattern: expr, Cause: err}
+		}
+		rule.Regexp = append(rule.Regexp, reg)
+	}
+	return rule, nil
+}
+
+// MustNewRule does the same as NewRule but panics on error.
+func MustNewRule(spec RuleSpec) Rule {
+	rule, err := NewRule(spec)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// matchesSlash checks if the regexp contains anything else than a literal
+// '/' which may match '/'.
+func matchesSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			if lo <= '/' && '/' <= hi && !(len(re.Rune) == 2 && lo == '/' && hi == '/') {
+				return true
+			}
+		}
+	}
+
+	for _, sub := range re.Sub {
+		if matchesSlash(sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package nogo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      RuleSpec
+		match     []string
+		noMatch   []string
+		wantErr   error
+		wantRegex []string
+	}{
+		{
+			name:      "anchored",
+			spec:      RuleSpec{Regexps: []string{`[^/]*\.log`}},
+			match:     []string{"debug.log"},
+			noMatch:   []string{"a/debug.log", "debug.log.bak"},
+			wantRegex: []string{`^(?:[^/]*\.log)$`},
+		},
+		{
+			name:    "prefix",
+			spec:    RuleSpec{Prefix: "sub/", Regexps: []string{`sub/(?:[^/]+/)*[^/]*\.log`}},
+			match:   []string{"sub/debug.log", "sub/a/debug.log"},
+			noMatch: []string{"debug.log", "subway/debug.log"},
+		},
+		{
+			name:    "all regexps have to match",
+			spec:    RuleSpec{Regexps: []string{`[^/]*\.log`, `a[^/]*`}},
+			match:   []string{"a.log"},
+			noMatch: []string{"b.log", "a.txt"},
+		},
+		{
+			name:    "match slash",
+			spec:    RuleSpec{Regexps: []string{`a.*b`}, MatchSlash: true},
+			match:   []string{"ab", "a/x/b"},
+			noMatch: []string{"a/x/c"},
+		},
+		{
+			name:    "non-ASCII",
+			spec:    RuleSpec{Regexps: []string{`ä[^/]*`}},
+			match:   []string{"äpfel"},
+			noMatch: []string{"apfel"},
+		},
+		{name: "dot crosses slash", spec: RuleSpec{Regexps: []string{`a.*b`}}, wantErr: &PatternError{}},
+		{name: "class crosses slash", spec: RuleSpec{Regexps: []string{`a[^x]b`}}, wantErr: &PatternError{}},
+		{name: "invalid regexp", spec: RuleSpec{Regexps: []string{`a(`}}, wantErr: &PatternError{}},
+		{name: "no regexp", spec: RuleSpec{}, wantErr: &PatternError{}},
+		{name: "invalid prefix", spec: RuleSpec{Prefix: "../a", Regexps: []string{`a`}}, wantErr: &InvalidPathError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewRule(tt.spec)
+			if tt.wantErr != nil {
+				assert.IsType(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, path := range tt.match {
+				assert.True(t, rule.MatchPath(path).Found, path)
+			}
+			for _, path := range tt.noMatch {
+				assert.False(t, rule.MatchPath(path).Found, path)
+			}
+			if tt.wantRegex != nil {
+				var regexps []string
+				for _, reg := range rule.Regexp {
+					regexps = append(regexps, reg.String())
+				}
+				assert.Equal(t, tt.wantRegex, regexps)
+			}
+		})
+	}
+}
+
+func TestNewRule_NoGo(t *testing.T) {
+	rule := MustNewRule(RuleSpec{Prefix: "Sub", Regexps: []string{`Sub/[^/]*\.LOG`}, OnlyFolder: true})
+	assert.Equal(t, "Sub", rule.Prefix)
+	assert.Equal(t, `# regexp: Sub/[^/]*\.LOG`, rule.Pattern)
+	assert.Nil(t, rule.Matchers())
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		n := New(WithBackend(backend), WithRules(rule))
+		assert.True(t, n.Match("Sub/a.LOG", true))
+		assert.False(t, n.Match("Sub/a.LOG", false))
+		n.Compile()
+		assert.True(t, n.Match("Sub/a.LOG", true))
+	}
+
+	// The rule is kept with WithIgnoreCase and matched against the lower case path.
+	n := New(WithRules(rule), WithIgnoreCase())
+	assert.Len(t, n.Rules(), 1)
+	assert.Equal(t, rule.Pattern, n.Rules()[0].Pattern)
+}
+
+func TestNoGo_AddRules_UTF8Regexps(t *testing.T) {
+	// Hand-built rules with UTF-8 text are converted, rules of Compile and NewRule are not.
+	handBuilt := Rule{Regexp: []*regexp.Regexp{regexp.MustCompile("^(.*/)?(ä|€)[^/]*$")}}
+	compiled := MustCompileAll("", []byte("/ö*"))[0]
+	newRule := MustNewRule(RuleSpec{Regexps: []string{"ü[^/]*"}})
+
+	for name, n := range map[string]*NoGo{
+		"AddRules":       New(handBuilt, compiled, newRule),
+		"AddRulesTagged": func() *NoGo { n := New(); n.AddRulesTagged("tag", handBuilt, compiled, newRule); return n }(),
+		"ReplaceGroup":   func() *NoGo { n := New(); n.ReplaceGroup("", []Rule{handBuilt, compiled, newRule}); return n }(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, n.Match("a/äx", false))
+			assert.True(t, n.Match("€", false))
+			assert.True(t, n.Match("öx", false))
+			assert.True(t, n.Match("üx", false))
+			assert.False(t, n.Match("ax", false))
+		})
+	}
+
+	assert.Equal(t, "^(.*/)?(ä|€)[^/]*$", handBuilt.Regexp[0].String(), "the rule itself is not changed")
+}
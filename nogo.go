@@ -29,48 +29,168 @@
 //    * A slash followed by two consecutive asterisks then a slash matches zero or more directories. For example, "a/**/b" matches "a/b", "a/x/b", "a/x/y/b" and so on.
 //
 //    * Other consecutive asterisks are considered regular asterisks and will matches according to the previous rules.
+//
+//  STABILITY
+//
+//  The following API is stable. It only changes in a backwards compatible way:
+//
+//    * New, ForFS, Option, NoGo.Apply, WithCache, WithBackend and DotGitRule
+//    * NoGo.AddFromFS, NoGo.AddFile, NoGo.AddRules and NoGo.Compile
+//    * NoGo.Match, NoGo.MatchBecause and NoGo.MatchWithoutParents
+//    * NoGo.ForWalkDir, NoGo.WalkFunc and NoGo.WalkDir
+//    * Rule, Result, Compile, CompileAll and MustCompileAll
+//
+//  All other API may still change in minor versions, but breaking changes are avoided.
+//  Experimental features, which may change or be removed in any version,
+//  are in the package github.com/aligator/nogo/x.
 package nogo
 
 import (
 	"errors"
 	"io"
 	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type group struct {
 	prefix string
 	rules  []Rule
+
+	// source is the ignore file the rules were loaded from.
+	// It is empty if they were not loaded from a file.
+	source string
+
+	// folded is set if the rules were converted by WithIgnoreCase.
+	folded bool
+
+	// tag is set by AddRulesTagged.
+	tag string
+
+	// hits counts the matches of each rule if WithStats is used.
+	hits []uint64
 }
 
 type NoGo struct {
 	groups []group
+
+	// tree indexes the groups by their prefix. It is nil if the groups were
+	// set without calling changed, so all groups have to be checked.
+	tree *groupTree
+
+	// cache is nil if caching is disabled.
+	cache *matchCache
+
+	backend Backend
+	// globs contains the rules prepared for the GlobBackend.
+	// It is nil for any other backend.
+	globs [][]*glob
+
+	// stripStreams enables the removal of Windows alternate data streams.
+	stripStreams bool
+
+	// precompiled is set by Compile.
+	precompiled bool
+	// compiled is nil if the rules are not precompiled.
+	compiled *compiledRules
+
+	// terminals is nil if there are no terminal rules.
+	terminals *terminalRules
+
+	// allowList inverts the decisions, see NewAllowList.
+	allowList bool
+	// allowed contains the paths of all non-negated rules of an allow list.
+	allowed []terminalRule
+
+	// validatePaths enables the validation of all matched paths.
+	validatePaths bool
+
+	// dialect is used to compile ignore files and patterns.
+	dialect Dialect
+
+	// statFS is used to resolve DirUnknown. It may be nil.
+	statFS fs.FS
+
+	// ignoreCase enables case-insensitive matching.
+	ignoreCase bool
+
+	// windowsPaths enables the normalization of Windows paths.
+	windowsPaths bool
+
+	// loadFS and ignoreFileNames are set by WithFS and WithIgnoreFileNames.
+	loadFS          fs.FS
+	ignoreFileNames []string
+
+	// gitDir is set by WithGitDir.
+	gitDir bool
+
+	// submodules is nil if WithSubmodules is not used.
+	submodules *submoduleRoots
+
+	// lazyLoading is set by WithLazyLoading.
+	lazyLoading bool
+	// lazy is nil if the ignore files are not loaded lazily.
+	lazy *lazyLoader
+
+	// strictIO is set by WithStrictIO.
+	strictIO bool
+
+	// stats enables counting the matches of each rule.
+	stats bool
+
+	// progress is nil if WithProgress is not used.
+	progress ProgressFunc
+
+	// logger is nil if WithLogger is not used.
+	logger Logger
+
+	// loads contains when each source of the groups was loaded, see Sources.
+	loads map[string]sourceLoad
+
+	// disabledTags contains the tags disabled by DisableTag.
+	disabledTags map[string]bool
 }
 
 // New creates a NoGo instance which works for the given ignoreFileNames.
 // You can pass additional options if needed.
-func New(rules ...Rule) *NoGo {
+// Rules are Options, too, so they can be passed directly.
+func New(opts ...Option) *NoGo {
 	n := &NoGo{}
-	n.AddRules(rules...)
+	n.Apply(opts...)
+	n.initLazyLoading()
 	return n
 }
 
 // AddFromFS ignore files which can be found in the given fsys.
 // It only loads ignore files which are not ignored itself by another ignore-file.
 func (n *NoGo) AddFromFS(fsys fs.FS, ignoreFilename string) error {
-	return fs.WalkDir(n.ForWalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+	return n.addFromFS(fsys, ignoreFilename)
+}
+
+// addFromFS does the same as AddFromFS for several ignore file names.
+func (n *NoGo) addFromFS(fsys fs.FS, ignoreFilenames ...string) error {
+	return fs.WalkDir(n.ForWalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if d.IsDir() {
-			// Load a maybe existing ignore file if it is not itself ignored.
-			possibleIgnoreFile := filepath.Join(path, ignoreFilename)
-			if match, _ := n.MatchWithoutParents(possibleIgnoreFile, false); !match {
-				err := n.AddFile(fsys, filepath.Join(path, ignoreFilename))
-				if err != nil && !errors.Is(err, fs.ErrNotExist) {
-					return err
+			n.report(ProgressEvent{Kind: ProgressDir, Path: name})
+			for _, ignoreFilename := range ignoreFilenames {
+				// Load a maybe existing ignore file if it is not itself ignored.
+				possibleIgnoreFile := path.Join(name, ignoreFilename)
+				if match, _ := n.MatchWithoutParents(possibleIgnoreFile, false); !match {
+					rules, err := n.addFile(fsys, possibleIgnoreFile)
+					if errors.Is(err, fs.ErrNotExist) {
+						continue
+					}
+					n.report(ProgressEvent{Kind: ProgressIgnoreFile, Path: possibleIgnoreFile, Rules: rules, Err: err})
+					if err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -80,13 +200,16 @@ func (n *NoGo) AddFromFS(fsys fs.FS, ignoreFilename string) error {
 }
 
 // AddRules to NoGo which are already compiled.
+// Like for AddFile, rules with a deeper Prefix take precedence.
+// Regexps of hand-built rules which contain UTF-8 text are converted, see Rule.Regexp.
 func (n *NoGo) AddRules(rules ...Rule) {
 	for _, rule := range rules {
-		n.groups = append(n.groups, group{
+		n.addGroup(group{
 			prefix: rule.Prefix,
-			rules:  []Rule{rule},
+			rules:  []Rule{byteRegexps(rule)},
 		})
 	}
+	n.changed()
 }
 
 // AddFile reads the given file and tries to load the content as an ignore file.
@@ -95,43 +218,117 @@ func (n *NoGo) AddRules(rules ...Rule) {
 //
 // The folder of the give filepath is used as Prefix for the rules.
 //
-// Note that the order in which rules are added is very important.
-// You should always first add the rules of parent folders and then of the
-// children folders.
-// TODO: in the future the rules could be re-sorted based on the prefix names.
+// The rules of deeper folders always take precedence over the rules of their
+// parents, like in git. So the files can be added in any order. Files of the
+// same folder are checked in the order they were added.
 func (n *NoGo) AddFile(fsys fs.FS, path string) error {
-	file, err := fsys.Open(path)
+	_, err := n.addFile(fsys, path)
+	return err
+}
+
+// addFile does the same as AddFile but also returns the number of added rules.
+func (n *NoGo) addFile(fsys fs.FS, name string) (int, error) {
+	start := time.Now()
+	file, err := fsys.Open(name)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	data, err := io.ReadAll(file)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	data, err = n.dialect.resolveIncludes(fsys, name, data)
+	if err != nil {
+		return 0, err
 	}
 
-	folder := filepath.Dir(path)
+	folder := path.Dir(name)
 	if folder == "." {
 		folder = ""
 	}
 
-	rules, err := CompileAll(folder, data)
+	rules, err := n.dialect.CompileAll(folder, data)
 	if err != nil {
-		return err
+		return 0, patternErrorAt(err, name, 0)
 	}
 
-	n.groups = append(n.groups, group{
+	n.addGroup(group{
 		prefix: folder,
 		rules:  rules,
+		source: name,
 	})
+	n.loaded(name, start)
+	n.changed()
+
+	return len(rules), nil
+}
+
+// changed has to be called whenever the rules are modified.
+// It drops everything which was calculated based on the old rules.
+func (n *NoGo) changed() {
+	if n.ignoreCase {
+		n.foldGroups()
+	}
+
+	if n.cache != nil {
+		n.cache.clear()
+	}
+	if n.stats {
+		n.initStats()
+	}
+
+	n.tree = newGroupTree(n.groups)
+
+	n.globs = nil
+	if n.backend == GlobBackend {
+		n.globs = newGlobs(n.groups)
+	}
+
+	// The compiled and terminal rules don't know about submodules.
+	if n.precompiled && n.submodules == nil {
+		n.compiled = compileRules(n.enabledGroups())
+	}
 
-	return nil
+	n.terminals = nil
+	n.allowed = nil
+	if n.allowList {
+		groups, _ := n.enabledGroups()
+		n.allowed = allowedPaths(groups)
+	} else if n.submodules == nil {
+		groups, _ := n.enabledGroups()
+		n.terminals = newTerminalRules(groups)
+	}
+}
+
+// Compile indexes all rules by literal text they require (e.g. the ".go" of "*.go").
+// Matching then only checks the rules whose literal occurs in the path, which
+// makes it much faster if there are many rules.
+//
+// Rules added after calling Compile are indexed automatically.
+func (n *NoGo) Compile() {
+	n.precompiled = true
+	if n.submodules == nil {
+		n.compiled = compileRules(n.enabledGroups())
+	}
 }
 
 // Match calculates if the path matches any rule.
 // It does the same as MatchBecause but only returns the boolean
 // for more easy in-if usage.
+//
+// Match doesn't allocate for valid paths (see ValidatePath), so it can be
+// used for millions of paths.
 func (n *NoGo) Match(path string, isDir bool) bool {
+	// The cause is not needed, so everything inside of terminal rules
+	// can be ignored without checking any other rule.
+	if n.lazy == nil && n.terminals != nil && !n.stats && n.logger == nil {
+		if path, ok := n.normalize(path); ok && n.terminals.match(path) {
+			return true
+		}
+	}
+
 	match, _ := n.MatchBecause(path, isDir)
 	return match
 }
@@ -179,33 +376,225 @@ func (n *NoGo) MatchWithoutParents(path string, isDir bool) (match bool, because
 	return n.match(path, isDir, true)
 }
 
+// normalize prepares the path for matching.
+// ok is false if the path can't be normalized or is not valid (see WithValidatePaths).
+func (n *NoGo) normalize(path string) (normalized string, ok bool) {
+	path, err := n.normalizeErr(path)
+	if err != nil {
+		return "", false
+	}
+	return path, n.valid(path)
+}
+
+// normalizeErr prepares the path for matching.
+func (n *NoGo) normalizeErr(path string) (string, error) {
+	path, err := n.fsPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	if n.ignoreCase {
+		path = lowerASCIIString(path)
+	}
+	return path, nil
+}
+
+// fsPath does the same as normalizeErr but keeps the case,
+// so the path can still be used to access the file system.
+func (n *NoGo) fsPath(path string) (string, error) {
+	// Convert to slash for windows compatibility.
+	path = filepath.ToSlash(path)
+
+	if n.windowsPaths {
+		var err error
+		path, err = NormalizeWindowsPath(path)
+		if err != nil {
+			return "", err
+		}
+	}
+	if n.stripStreams {
+		path = stripWindowsStreams(path)
+	}
+	return path, nil
+}
+
 func (n *NoGo) match(path string, isDir bool, noParents bool) (match bool, because Result) {
-	pathToCheck := []string{path}
-	if !noParents {
-		// Convert to slash for windows compatibility before splitting.
-		pathToCheck = strings.Split(filepath.ToSlash(path), "/")
+	n.lock(path)
+	defer n.unlock()
+
+	path, ok := n.normalize(path)
+	if !ok {
+		return false, Result{}
 	}
 
-	path = ""
-	for i, p := range pathToCheck {
-		// Convert to slash for windows compatibility.
-		path = filepath.ToSlash(filepath.Join(path, p))
+	if n.allowList {
+		// Included parents include everything inside of them,
+		// so they always have to be checked.
+		noParents = false
+	}
 
-		for _, g := range n.groups {
-			if !strings.HasPrefix(path, g.prefix) {
-				continue
-			}
+	if n.cache == nil {
+		match, because = n.matchRules(path, isDir, noParents)
+		n.logMatch(path, isDir, match, because, false)
+		return match, because
+	}
+
+	key := cacheKey{path: path, isDir: isDir, noParents: noParents}
+	if entry, ok := n.cache.get(key); ok {
+		n.logMatch(path, isDir, entry.match, entry.because, true)
+		return entry.match, entry.because
+	}
+
+	match, because = n.matchRules(path, isDir, noParents)
+	n.cache.put(cacheEntry{key: key, match: match, because: because})
+	n.logMatch(path, isDir, match, because, false)
+	return match, because
+}
 
-			for _, rule := range g.rules {
-				newRes := rule.MatchPath(path)
+func (n *NoGo) matchRules(path string, isDir bool, noParents bool) (match bool, because Result) {
+	path = cleanPath(path)
 
-				if newRes.Found && ((newRes.OnlyFolder && isDir) || !newRes.OnlyFolder) {
-					because = newRes
-					because.ParentMatch = i < len(pathToCheck)-1
-				}
+	// The parents are checked using prefixes of the path to avoid allocations.
+	end := len(path)
+	if !noParents {
+		end = segmentEnd(path, 0)
+	}
+
+	for {
+		// All parents are directories.
+		last := end == len(path)
+		if newRes, found := n.matchLast(path[:end], isDir || !last); found {
+			if n.logger != nil {
+				n.logRuleMatch(path[:end], isDir || !last, newRes)
+			}
+			because = newRes
+			because.ParentMatch = !last
+
+			// As git, a path can't be re-included if a parent is excluded.
+			if n.parentExcluded(because, last) {
+				break
 			}
 		}
+
+		if last {
+			break
+		}
+		end = segmentEnd(path, end+1)
 	}
 
+	if n.allowList {
+		return n.excluded(path, isDir, because), because
+	}
 	return because.Resolve(isDir), because
 }
+
+// segmentEnd returns the index of the next '/' starting at start
+// or the length of the path if there is none.
+func segmentEnd(path string, start int) int {
+	if i := strings.IndexByte(path[start:], '/'); i >= 0 {
+		return start + i
+	}
+	return len(path)
+}
+
+// cleanPath removes empty and "." elements as well as leading and trailing
+// slashes, as path.Join does it. Paths which are already clean are
+// returned without allocating.
+func cleanPath(name string) string {
+	if isCleanPath(name) {
+		return name
+	}
+	return path.Join(strings.Split(name, "/")...)
+}
+
+// isCleanPath checks if the path has no empty, "." or ".." elements.
+// The root "." itself is clean.
+func isCleanPath(path string) bool {
+	if path == "." {
+		return true
+	}
+	start := 0
+	for start <= len(path) {
+		end := segmentEnd(path, start)
+		switch path[start:end] {
+		case "", ".", "..":
+			return false
+		}
+		start = end + 1
+	}
+	return true
+}
+
+// parentExcluded checks if the result excludes a parent folder, so that
+// nothing inside of it can be re-included by a negated rule.
+// For allow lists each negated rule has to be checked, so it is never true.
+func (n *NoGo) parentExcluded(because Result, last bool) bool {
+	return !last && !n.allowList && because.Resolve(true)
+}
+
+// matchLast returns the last rule which matches the path itself.
+// The parent folders are not checked.
+func (n *NoGo) matchLast(path string, isDir bool) (because Result, found bool) {
+	if n.compiled != nil {
+		return n.compiled.match(path, isDir)
+	}
+
+	// Inside of submodules only the ignore files of the submodule apply.
+	submodule := n.submoduleRoot(path)
+
+	if n.tree == nil {
+		for gi := range n.groups {
+			n.matchGroup(gi, path, isDir, submodule, &because, &found)
+		}
+		return because, found
+	}
+
+	// Only the groups of the parent folders (and of the path itself) can match.
+	node := n.tree
+	for start := 0; node != nil; {
+		for _, gi := range node.groups {
+			n.matchGroup(gi, path, isDir, submodule, &because, &found)
+		}
+		if start >= len(path) {
+			break
+		}
+		end := segmentEnd(path, start)
+		node = node.child(path[start:end])
+		start = end + 1
+	}
+
+	return because, found
+}
+
+// matchGroup matches the path against the rules of the group with the index gi.
+// because and found are only changed if a rule matches.
+func (n *NoGo) matchGroup(gi int, path string, isDir bool, submodule string, because *Result, found *bool) {
+	g := &n.groups[gi]
+	if !isPathInside(path, g.prefix) {
+		return
+	}
+	if g.tag != "" && n.disabledTags[g.tag] {
+		return
+	}
+	if g.source != "" && !isPathInside(g.prefix, submodule) {
+		return
+	}
+
+	for ri, rule := range g.rules {
+		var newRes Result
+		if n.globs != nil {
+			newRes = matchRule(rule, n.globs[gi][ri], path)
+		} else {
+			newRes = rule.MatchPath(path)
+		}
+
+		if newRes.Found && ((newRes.OnlyFolder && isDir) || !newRes.OnlyFolder) {
+			newRes.Tag = g.tag
+			*because = newRes
+			*found = true
+			if g.hits != nil {
+				atomic.AddUint64(&g.hits[ri], 1)
+			}
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package nogo
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// benchRules generates an ignore file with about count rules,
+// similar to what can be found in big repositories.
+func benchRules(count int) []byte {
+	var b strings.Builder
+	for i := 0; i < count/4; i++ {
+		fmt.Fprintf(&b, "*.ext%d\n", i)
+		fmt.Fprintf(&b, "/generated%d/\n", i)
+		fmt.Fprintf(&b, "build%d/**\n", i)
+		fmt.Fprintf(&b, "!keep%d.ext%d\n", i, i)
+	}
+	return []byte(b.String())
+}
+
+var benchPaths = []string{
+	"src/main.go",
+	"src/pkg/sub/file.go",
+	"docs/README.md",
+	"src/pkg/sub/deeper/even/deeper/file.txt",
+	"generated3/file.go",
+	"src/file.ext42",
+}
+
+func benchmarkMatch(b *testing.B, rules int, compile bool, opts ...Option) {
+	n := New(opts...)
+	n.AddRules(MustCompileAll("", benchRules(rules))...)
+	if compile {
+		n.Compile()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Match(benchPaths[i%len(benchPaths)], false)
+	}
+}
+
+func BenchmarkNoGo_Match(b *testing.B) {
+	for _, rules := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("%d rules", rules), func(b *testing.B) {
+			benchmarkMatch(b, rules, false)
+		})
+		b.Run(fmt.Sprintf("%d rules compiled", rules), func(b *testing.B) {
+			benchmarkMatch(b, rules, true)
+		})
+		b.Run(fmt.Sprintf("%d rules glob", rules), func(b *testing.B) {
+			benchmarkMatch(b, rules, false, WithBackend(GlobBackend))
+		})
+	}
+}
+
+func BenchmarkNoGo_MatchAll(b *testing.B) {
+	n := New()
+	n.AddRules(MustCompileAll("", benchRules(100))...)
+
+	var paths []string
+	for i := 0; i < 100; i++ {
+		for _, path := range benchPaths {
+			paths = append(paths, fmt.Sprintf("%s%d", path, i))
+		}
+	}
+
+	b.Run("Match", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, path := range paths {
+				n.Match(path, false)
+			}
+		}
+	})
+	b.Run("MatchAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n.MatchAll(paths)
+		}
+	})
+}
+
+func BenchmarkNoGo_Match_Terminal(b *testing.B) {
+	var rules strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&rules, "*.ext%d\n", i)
+	}
+	rules.WriteString("node_modules\n")
+
+	n := New()
+	n.AddRules(MustCompileAll("", []byte(rules.String()))...)
+	path := "web/node_modules/some-package/lib/index.js"
+
+	b.Run("Match", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n.Match(path, false)
+		}
+	})
+	b.Run("MatchBecause", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n.MatchBecause(path, false)
+		}
+	})
+}
+
+// kernelIgnore resembles the root .gitignore of the linux kernel.
+const kernelIgnore = `.*
+*.a
+*.asn1.[ch]
+*.bin
+*.bz2
+*.dtb
+*.dwo
+*.elf
+*.gcno
+*.gz
+*.ko
+*.ll
+*.lst
+*.lz4
+*.lzma
+*.lzo
+*.mod
+*.mod.c
+*.o
+*.o.*
+*.patch
+*.s
+*.so
+*.so.dbg
+*.su
+*.symtypes
+*.tab.[ch]
+*.tar
+*.xz
+*.zst
+Module.symvers
+modules.order
+/linux
+/modules-only.symvers
+/vmlinux
+/vmlinux.32
+/vmlinux-gdb.py
+/vmlinuz
+/System.map
+/Module.markers
+/modules.builtin.modinfo
+/tar-install/
+/debian/
+/rpmbuild/
+*.spec
+!.gitignore
+!.mailmap
+!.clang-format
+!.get_maintainer.ignore
+!.gitattributes
+!.cocciconfig
+*.orig
+*~
+\#*#
+/include/config/
+/include/generated/
+/arch/*/include/generated/
+/certs/*.pem
+/certs/*.x509
+`
+
+// chromiumIgnore resembles the root .gitignore of chromium.
+const chromiumIgnore = `*.pyc
+*.swp
+.DS_Store
+*.xcodeproj
+*.sln
+*.vcxproj*
+/.gclient_entries
+/out/
+/out_*/
+/xcodebuild/
+/build/linux/debian_*-sysroot/
+/build/util/LASTCHANGE*
+/chrome/tools/test/reference_build/
+/third_party/*
+!/third_party/blink/
+!/third_party/abseil-cpp/
+!/third_party/protobuf/
+/third_party/blink/web_tests/external/wpt/
+/native_client
+/tools/perf/page_sets/data/*.wpr
+**/node_modules
+cscope.*
+tags
+TAGS
+GPATH
+GRTAGS
+GTAGS
+`
+
+// benchFS generates a repository with the ignore file at the root
+// and a small ignore file in each of the dirs top level folders.
+func benchFS(ignore string, dirs int) fstest.MapFS {
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte(ignore)},
+	}
+	for i := 0; i < dirs; i++ {
+		dir := fmt.Sprintf("drivers/dir%d", i)
+		fsys[dir+"/.gitignore"] = &fstest.MapFile{Data: []byte(fmt.Sprintf("/generated%d\n*.tmp\n", i))}
+		for j := 0; j < 10; j++ {
+			fsys[fmt.Sprintf("%s/file%d.c", dir, j)] = &fstest.MapFile{}
+			fsys[fmt.Sprintf("%s/file%d.o", dir, j)] = &fstest.MapFile{}
+			fsys[fmt.Sprintf("%s/sub/file%d.h", dir, j)] = &fstest.MapFile{}
+		}
+		fsys[fmt.Sprintf("%s/generated%d/out.c", dir, i)] = &fstest.MapFile{}
+	}
+	fsys["out/Release/chrome"] = &fstest.MapFile{}
+	fsys["third_party/blink/renderer/core.cc"] = &fstest.MapFile{}
+	fsys["third_party/other/lib.cc"] = &fstest.MapFile{}
+	fsys["web/node_modules/pkg/index.js"] = &fstest.MapFile{}
+	return fsys
+}
+
+var benchFixtures = []struct {
+	name   string
+	ignore string
+}{
+	{name: "kernel", ignore: kernelIgnore},
+	{name: "chromium", ignore: chromiumIgnore},
+}
+
+// benchFixturePaths are paths which are checked against the fixtures.
+var benchFixturePaths = []string{
+	"drivers/dir1/file1.c",
+	"drivers/dir1/file1.o",
+	"drivers/dir1/sub/file2.h",
+	"drivers/dir1/generated1/out.c",
+	"arch/x86/include/generated/asm/unistd.h",
+	"include/config/auto.conf",
+	"out/Release/chrome",
+	"third_party/blink/renderer/core.cc",
+	"third_party/other/lib.cc",
+	"web/node_modules/pkg/index.js",
+	".gitignore",
+	"vmlinux",
+}
+
+func BenchmarkCompileAll(b *testing.B) {
+	for _, fixture := range benchFixtures {
+		b.Run(fixture.name, func(b *testing.B) {
+			data := []byte(fixture.ignore)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := CompileAll("", data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNoGo_Compile(b *testing.B) {
+	for _, fixture := range benchFixtures {
+		b.Run(fixture.name, func(b *testing.B) {
+			rules := MustCompileAll("", []byte(fixture.ignore))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n := New()
+				n.AddRules(rules...)
+				n.Compile()
+			}
+		})
+	}
+}
+
+func BenchmarkNew_Rules(b *testing.B) {
+	rules := MustCompileAll("", benchRules(2000))
+	opts := make([]Option, len(rules))
+	for i, rule := range rules {
+		opts[i] = rule
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		New(opts...)
+	}
+}
+
+func BenchmarkNoGo_Match_Fixtures(b *testing.B) {
+	for _, fixture := range benchFixtures {
+		n, err := ForFS(benchFS(fixture.ignore, 10), ".gitignore")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fixture.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n.Match(benchFixturePaths[i%len(benchFixturePaths)], false)
+			}
+		})
+
+		n.Compile()
+		b.Run(fixture.name+" compiled", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n.Match(benchFixturePaths[i%len(benchFixturePaths)], false)
+			}
+		})
+	}
+}
+
+func BenchmarkNoGo_WalkDir(b *testing.B) {
+	for _, fixture := range benchFixtures {
+		fsys := benchFS(fixture.ignore, 50)
+		walkFn := func(path string, d fs.DirEntry, err error) error {
+			return err
+		}
+
+		b.Run(fixture.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := New().WalkDir(fsys, ".", walkFn, WithIgnoreFile(".gitignore")); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// Only walk without loading the ignore files.
+		n, err := ForFS(fsys, ".gitignore")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fixture.name+" preloaded", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := n.WalkDir(fsys, ".", walkFn); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestNoGo_Match_AllocationBudget fails if Match allocates more than expected,
+// as the benchmarks are not run in CI. Match must not allocate for valid paths.
+func TestNoGo_Match_AllocationBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	const budget = 0
+
+	for _, fixture := range benchFixtures {
+		for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+			for _, compile := range []bool{false, true} {
+				n, err := ForFS(benchFS(fixture.ignore, 10), ".gitignore", WithBackend(backend))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if compile {
+					n.Compile()
+				}
+
+				for _, path := range benchFixturePaths {
+					allocs := testing.AllocsPerRun(100, func() {
+						n.Match(path, false)
+					})
+					if allocs > budget {
+						t.Errorf("%s %v %v: Match(%q) allocates %v times, budget is %v", fixture.name, backend, compile, path, allocs, budget)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkNoGo_Match_NestedIgnoreFiles(b *testing.B) {
+	for _, files := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d files", files), func(b *testing.B) {
+			n := New()
+			for i := 0; i < files; i++ {
+				n.AddRules(MustCompileAll(fmt.Sprintf("pkg%d/sub", i), []byte("*.log\n!keep.log\n"))...)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				n.Match("pkg5/sub/internal/file.log", false)
+			}
+		})
+	}
+}
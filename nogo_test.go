@@ -14,6 +14,7 @@ var (
 	TestFSGroups = []group{
 		{
 			prefix: "",
+			source: ".gitignore",
 			rules: []Rule{
 				{
 					Regexp:  []*regexp.Regexp{regexp.MustCompile("^(.*/)?globallyIgnored$")},
@@ -46,6 +47,7 @@ var (
 		},
 		{
 			prefix: "aFolder",
+			source: "aFolder/.gitignore",
 			rules: []Rule{
 				{
 					Regexp:  []*regexp.Regexp{regexp.MustCompile("^aFolder/locallyIgnoredFile$")},
@@ -61,6 +63,7 @@ var (
 		},
 		{
 			prefix: "aPartiallyIgnoredFolder",
+			source: "aPartiallyIgnoredFolder/.gitignore",
 			rules: []Rule{
 				{
 					Regexp:  []*regexp.Regexp{regexp.MustCompile("^aPartiallyIgnoredFolder(/.*)?/unignoredFile$")},
@@ -72,6 +75,7 @@ var (
 		},
 		{
 			prefix: "glob-tests",
+			source: "glob-tests/.gitignore",
 			rules: []Rule{
 				{
 					Regexp:  []*regexp.Regexp{regexp.MustCompile("^glob-tests/file[^/]*withStar$")},
@@ -79,14 +83,13 @@ var (
 					Pattern: "/file*withStar",
 				},
 				{
-					Regexp:  []*regexp.Regexp{regexp.MustCompile("^glob-tests/question[^/]?mark[^/]?[^/]?file[^/]?[^/]?[^/]?$")},
+					Regexp:  []*regexp.Regexp{regexp.MustCompile("^glob-tests/question[^/]mark[^/][^/]file[^/][^/][^/]$")},
 					Prefix:  "glob-tests",
 					Pattern: "/question?mark??file???",
 				},
 				{
 					Regexp: []*regexp.Regexp{
-						regexp.MustCompile("^glob-tests/file[^/]with[^/]ranges$"),
-						regexp.MustCompile("^glob-tests/file[a-z]with[^0-9]ranges$"),
+						regexp.MustCompile("^glob-tests/file[a-z]with[^/0-9]ranges$"),
 					},
 					Prefix:  "glob-tests",
 					Pattern: "/file[a-z]with[!0-9]ranges",
@@ -107,7 +110,7 @@ var (
 					Pattern: "any/**",
 				},
 				{
-					Regexp:  []*regexp.Regexp{regexp.MustCompile("^glob-tests/something.*/more$")},
+					Regexp:  []*regexp.Regexp{regexp.MustCompile("^glob-tests/something/(.*/)?more$")},
 					Prefix:  "glob-tests",
 					Pattern: "something/**/more",
 				},
@@ -137,7 +140,7 @@ var TestFSData = map[string]struct {
 	"aPartiallyIgnoredFolder/.gitignore":                           {"!unignoredFile", &Result{Rule: TestFSGroups[0].rules[2], Found: true, ParentMatch: false}, false},
 	"aPartiallyIgnoredFolder/unignoredFile":                        {"", &Result{Rule: TestFSGroups[2].rules[0], Found: true, ParentMatch: false}, false},
 	"aPartiallyIgnoredFolder/ignoredFile":                          {"", &Result{Rule: TestFSGroups[0].rules[1], Found: true, ParentMatch: false}, false},
-	"aPartiallyIgnoredFolder/ignoredFolder/.gitignore":             {"notParsed as it is in an ignored folder", &Result{Rule: TestFSGroups[0].rules[1], Found: true, ParentMatch: false}, false},
+	"aPartiallyIgnoredFolder/ignoredFolder/.gitignore":             {"notParsed as it is in an ignored folder", &Result{Rule: TestFSGroups[0].rules[4], Found: true, ParentMatch: true}, false},
 	"aFolder/anotherFolder/globallyIgnored":                        {"", &Result{Rule: TestFSGroups[0].rules[0], Found: true, ParentMatch: false}, false},
 	"aFolder/anotherFolder/globallyIgnored/aFileInGloballyIgnored": {"", &Result{Rule: TestFSGroups[0].rules[0], Found: true, ParentMatch: true}, false},
 
@@ -148,10 +151,12 @@ var TestFSData = map[string]struct {
 	"glob-tests/file4/2withStar": {"", nil, false},
 
 	// question mark
-	"glob-tests/questionmarkfile":       {"", &Result{Rule: TestFSGroups[3].rules[1], Found: true, ParentMatch: false}, false},
+	"glob-tests/questionmarkfile":       {"", nil, false},
 	"glob-tests/question0mark42file123": {"", &Result{Rule: TestFSGroups[3].rules[1], Found: true, ParentMatch: false}, false},
-	"glob-tests/questionämarköfileü":    {"", &Result{Rule: TestFSGroups[3].rules[1], Found: true, ParentMatch: false}, false},
-	"glob-tests/question/markfile":      {"", nil, false},
+	// Like git, '?' matches a single byte and not a whole UTF-8 character.
+	"glob-tests/questionämarköfileü":  {"", nil, false},
+	"glob-tests/questionxmarkäfileü1": {"", &Result{Rule: TestFSGroups[3].rules[1], Found: true, ParentMatch: false}, false},
+	"glob-tests/question/markfile":    {"", nil, false},
 
 	// ranges
 	"glob-tests/filefwith-ranges": {"", &Result{Rule: TestFSGroups[3].rules[2], Found: true, ParentMatch: false}, false},
@@ -174,7 +179,7 @@ var TestFSData = map[string]struct {
 
 	// any/**
 	"glob-tests/any":         {"", nil, false},
-	"glob-tests/any/foo/bar": {"", &Result{Rule: TestFSGroups[3].rules[5], Found: true, ParentMatch: false}, false},
+	"glob-tests/any/foo/bar": {"", &Result{Rule: TestFSGroups[3].rules[5], Found: true, ParentMatch: true}, false},
 	"glob-tests/any/foo":     {"", &Result{Rule: TestFSGroups[3].rules[5], Found: true, ParentMatch: false}, false},
 	"glob-tests/anyfoo/bar":  {"", nil, false},
 
@@ -202,6 +207,16 @@ func NewTestFS() fs.FS {
 	return memfs
 }
 
+// newTestMapFS creates a file system which contains the files with their
+// content. Each call returns a new file system, so tests can modify it.
+func newTestMapFS(files map[string]string) fstest.MapFS {
+	memfs := fstest.MapFS{}
+	for path, data := range files {
+		memfs[path] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return memfs
+}
+
 func TestCompile(t *testing.T) {
 	type args struct {
 		prefix  string
@@ -416,7 +431,7 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder/nogo.js?",
 			},
-			wantRegexp: []string{"^a/folder/aFolder/nogo\\.js[^/]?$"},
+			wantRegexp: []string{"^a/folder/aFolder/nogo\\.js[^/]$"},
 			wantMatches: []matches{
 				{
 					name:    "with one char at the end",
@@ -425,7 +440,7 @@ func TestCompile(t *testing.T) {
 				},
 				{
 					name:    "without something at the end",
-					matches: true,
+					matches: false,
 					input:   "a/folder/aFolder/nogo.js",
 				},
 				{
@@ -442,7 +457,7 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder?yay/nogo.go",
 			},
-			wantRegexp: []string{"^a/folder/aFolder[^/]?yay/nogo\\.go$"},
+			wantRegexp: []string{"^a/folder/aFolder[^/]yay/nogo\\.go$"},
 			wantMatches: []matches{
 				{
 					name:    "with one char",
@@ -451,8 +466,8 @@ func TestCompile(t *testing.T) {
 				},
 				{
 					name:    "without something",
-					matches: true,
-					input:   "a/folder/aFolder-yay/nogo.go",
+					matches: false,
+					input:   "a/folder/aFolderyay/nogo.go",
 				},
 				{
 					name:    "with too many chars",
@@ -473,16 +488,16 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder???yay/nogo.go",
 			},
-			wantRegexp: []string{"^a/folder/aFolder[^/]?[^/]?[^/]?yay/nogo\\.go$"},
+			wantRegexp: []string{"^a/folder/aFolder[^/][^/][^/]yay/nogo\\.go$"},
 			wantMatches: []matches{
 				{
 					name:    "with one char",
-					matches: true,
+					matches: false,
 					input:   "a/folder/aFolder-yay/nogo.go",
 				},
 				{
 					name:    "with two chars",
-					matches: true,
+					matches: false,
 					input:   "a/folder/aFolder--yay/nogo.go",
 				},
 				{
@@ -541,7 +556,7 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder/nogo.[jt]s",
 			},
-			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[^/]s$", "^a/folder/aFolder/nogo\\.[jt]s$"},
+			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[jt]s$"},
 			wantMatches: []matches{
 				{
 					name:    "with one of these characters",
@@ -572,7 +587,7 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder/nogo.[a-z]s",
 			},
-			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[^/]s$", "^a/folder/aFolder/nogo\\.[a-z]s$"},
+			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[a-z]s$"},
 			wantMatches: []matches{
 				{
 					name:    "with one of these characters",
@@ -603,7 +618,7 @@ func TestCompile(t *testing.T) {
 				prefix:  "a/folder",
 				pattern: "/aFolder/nogo.[!a-z]s",
 			},
-			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[^/]s$", "^a/folder/aFolder/nogo\\.[^a-z]s$"},
+			wantRegexp: []string{"^a/folder/aFolder/nogo\\.[^/a-z]s$"},
 			wantMatches: []matches{
 				{
 					name:    "with one of these characters",
@@ -654,6 +669,16 @@ func TestCompile(t *testing.T) {
 			wantSkip:   true,
 			wantErr:    assert.NoError,
 		},
+		{
+			name: "ignore pattern with only spaces",
+			args: args{
+				prefix:  "a/folder",
+				pattern: "   ",
+			},
+			wantRegexp: nil,
+			wantSkip:   true,
+			wantErr:    assert.NoError,
+		},
 		{
 			name: "ignore with # prefix",
 			args: args{
@@ -807,7 +832,7 @@ func TestCompile(t *testing.T) {
 		t.Run(tt.args.pattern+"|"+tt.name, func(t *testing.T) {
 			gotSkip, gotRule, err := Compile(tt.args.prefix, tt.args.pattern)
 
-			if tt.wantErr(t, err) {
+			if !tt.wantErr(t, err) || err != nil {
 				return
 			}
 
@@ -840,6 +865,23 @@ func TestCompile(t *testing.T) {
 	}
 }
 
+func TestByteRunes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: ""},
+		{input: "a/b.go", want: "a/b.go"},
+		{input: "ä", want: "\u00c3\u00a4"},
+		{input: "a/äb", want: "a/\u00c3\u00a4b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, ByteRunes(tt.input))
+		})
+	}
+}
+
 func TestNoGo_AddAll(t *testing.T) {
 	type fields struct {
 		fs             fs.FS
@@ -902,6 +944,12 @@ func TestNoGo_MatchBecause(t *testing.T) {
 }
 
 func TestNoGo_MatchWithoutParents(t *testing.T) {
+	// Paths inside of an excluded folder which are also matched directly.
+	selfMatches := map[string]*Result{
+		"aPartiallyIgnoredFolder/ignoredFolder/.gitignore": {Rule: TestFSGroups[0].rules[1], Found: true},
+		"glob-tests/any/foo/bar":                           {Rule: TestFSGroups[3].rules[5], Found: true},
+	}
+
 	for path, tt := range TestFSData {
 		t.Run(path, func(t *testing.T) {
 			n := &NoGo{
@@ -911,7 +959,7 @@ func TestNoGo_MatchWithoutParents(t *testing.T) {
 
 			// The same test as TestNoGo_MatchBecause, but ignore all parent matches.
 			if tt.ignoredBy != nil && tt.ignoredBy.ParentMatch {
-				tt.ignoredBy = nil
+				tt.ignoredBy = selfMatches[path]
 			}
 
 			if gotBecause.Negate {
@@ -974,14 +1022,14 @@ func TestNoGo_MatchWithoutParents(t *testing.T) {
 		assert.False(t, gotBecause.Resolve(false))
 		assert.EqualValues(t, Result{}, gotBecause)
 
-		// Should be matched by the normal match:
+		// Should be matched by the normal match because of the excluded parent folder:
 		gotMatch, gotBecause = n.MatchBecause("anIgnoredFolder/anotherFile", false)
 		assert.True(t, gotMatch)
 		assert.True(t, gotBecause.Resolve(false))
 		assert.EqualValues(t, Result{
-			Rule:        n.groups[1].rules[1],
+			Rule:        n.groups[0].rules[0],
 			Found:       true,
-			ParentMatch: false,
+			ParentMatch: true,
 		}, gotBecause)
 
 		// And it should also match with MatchWithoutParents as the file is matched inside the folder directly:
@@ -995,3 +1043,112 @@ func TestNoGo_MatchWithoutParents(t *testing.T) {
 		}, gotBecause)
 	})
 }
+
+func TestNoGo_Match_ReInclude(t *testing.T) {
+	// All decisions are the same as `git check-ignore` returns.
+	// A path can't be re-included if a parent folder is excluded.
+	tests := []struct {
+		rules string
+		want  map[string]bool
+	}{
+		{
+			rules: "build/\n!build/output.txt",
+			want:  map[string]bool{"build/output.txt": true, "build/other": true},
+		},
+		{
+			rules: "/build\n!/build/keep",
+			want:  map[string]bool{"build/keep": true},
+		},
+		{
+			rules: "build/**\n!build/keep",
+			want:  map[string]bool{"build/keep": false, "build/sub/keep": true},
+		},
+		{
+			rules: "dir/*\n!dir/keep",
+			want:  map[string]bool{"dir/keep": false, "dir/keep/a": false, "dir/other": true, "dir/other/keep": true},
+		},
+		{
+			rules: "/*\n!/foo\n/foo/*\n!/foo/bar",
+			want:  map[string]bool{"foo/bar": false, "foo/bar/a": false, "foo/baz": true, "other": true, "other/bar": true},
+		},
+		{
+			rules: "*\n!foo",
+			want:  map[string]bool{"foo": false, "dir/foo": true},
+		},
+		{
+			rules: "*\n!*/\n!foo",
+			want:  map[string]bool{"foo": false, "dir/foo": false, "dir/bar": true},
+		},
+		{
+			rules: "dir/\n!dir/",
+			want:  map[string]bool{"dir/a": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rules, func(t *testing.T) {
+			for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+				for _, compile := range []bool{false, true} {
+					n := New(WithBackend(backend))
+					n.AddRules(MustCompileAll("", []byte(tt.rules))...)
+					if compile {
+						n.Compile()
+					}
+
+					for path, want := range tt.want {
+						assert.Equal(t, want, n.Match(path, false), "%v %v: %s", backend, compile, path)
+						matches, _ := n.MatchAll([]string{path})
+						assert.Equal(t, want, matches[0], "%v %v: MatchAll %s", backend, compile, path)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "a/b/c", want: "a/b/c"},
+		{path: ".", want: "."},
+		{path: "a//b", want: "a/b"},
+		{path: "/a/b/", want: "a/b"},
+		{path: "./a/./b", want: "a/b"},
+		{path: "a/../b", want: "b"},
+		{path: ".a/b..", want: ".a/b.."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanPath(tt.path))
+			assert.Equal(t, tt.path == tt.want, isCleanPath(tt.path))
+		})
+	}
+}
+
+// TestStableAPI fails to compile if the signature of the stable API,
+// as listed in the package documentation, changes.
+func TestStableAPI(t *testing.T) {
+	var (
+		_ func(opts ...Option) *NoGo                                                                = New
+		_ func(n *NoGo, opts ...Option) *NoGo                                                       = (*NoGo).Apply
+		_ func(fsys fs.FS, ignoreFileName string, opts ...Option) (*NoGo, error)                    = ForFS
+		_ func(size int) Option                                                                     = WithCache
+		_ func(backend Backend) Option                                                              = WithBackend
+		_ Rule                                                                                      = DotGitRule
+		_ func(n *NoGo, fsys fs.FS, ignoreFilename string) error                                    = (*NoGo).AddFromFS
+		_ func(n *NoGo, fsys fs.FS, path string) error                                              = (*NoGo).AddFile
+		_ func(n *NoGo, rules ...Rule)                                                              = (*NoGo).AddRules
+		_ func(n *NoGo)                                                                             = (*NoGo).Compile
+		_ func(n *NoGo, path string, isDir bool) bool                                               = (*NoGo).Match
+		_ func(n *NoGo, path string, isDir bool) (bool, Result)                                     = (*NoGo).MatchBecause
+		_ func(n *NoGo, path string, isDir bool) (bool, Result)                                     = (*NoGo).MatchWithoutParents
+		_ func(n *NoGo, fsys fs.FS, root string, fn fs.WalkDirFunc) (fs.FS, string, fs.WalkDirFunc) = (*NoGo).ForWalkDir
+		_ func(n *NoGo, fsys fs.FS, path string, isDir bool, err error) (bool, error)               = (*NoGo).WalkFunc
+		_ func(n *NoGo, fsys fs.FS, root string, fn fs.WalkDirFunc, opts ...WalkOption) error       = (*NoGo).WalkDir
+		_ func(prefix string, pattern string) (bool, Rule, error)                                   = Compile
+		_ func(prefix string, data []byte) ([]Rule, error)                                          = CompileAll
+		_ func(prefix string, data []byte) []Rule                                                   = MustCompileAll
+		_ func(r Result, isDir bool) bool                                                           = Result.Resolve
+	)
+}
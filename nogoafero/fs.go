@@ -0,0 +1,178 @@
+// Package nogoafero provides an afero.Fs which hides all files ignored by nogo.
+//
+// It is a separate module, so that nogo itself does not depend on afero.
+package nogoafero
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aligator/nogo"
+	"github.com/spf13/afero"
+)
+
+// Fs wraps an afero.Fs and hides all files and folders which are ignored
+// by the NoGo instance.
+//
+// Reading ignored files returns an error (os.ErrNotExist by default) and
+// directory listings do not contain them.
+// Write operations are always passed through.
+type Fs struct {
+	afero.Fs
+	n          *nogo.NoGo
+	ignoredErr error
+}
+
+// Option configures the Fs.
+type Option func(f *Fs)
+
+// WithIgnoredError sets the error which is returned when reading an ignored file.
+// The default is os.ErrNotExist.
+func WithIgnoredError(err error) Option {
+	return func(f *Fs) {
+		f.ignoredErr = err
+	}
+}
+
+// Wrap the afero.Fs.
+// Note that you have to load the ignore files into the NoGo instance before,
+// e.g. using n.AddFromFS(afero.NewIOFS(fs), ".gitignore").
+func Wrap(fs afero.Fs, n *nogo.NoGo, opts ...Option) afero.Fs {
+	f := &Fs{
+		Fs:         fs,
+		n:          n,
+		ignoredErr: os.ErrNotExist,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// relative converts the afero path into the form nogo expects
+// (relative to the root, using '/').
+func relative(name string) string {
+	name = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(name)), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func (f *Fs) ignored(name string, isDir bool) bool {
+	name = relative(name)
+	if name == "." {
+		return false
+	}
+	return f.n.Match(name, isDir)
+}
+
+func (f *Fs) pathError(op, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: f.ignoredErr}
+}
+
+func (f *Fs) Name() string {
+	return "nogo(" + f.Fs.Name() + ")"
+}
+
+func (f *Fs) Stat(name string) (os.FileInfo, error) {
+	info, err := f.Fs.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.ignored(name, info.IsDir()) {
+		return nil, f.pathError("stat", name)
+	}
+	return info, nil
+}
+
+// LstatIfPossible implements afero.Lstater.
+func (f *Fs) LstatIfPossible(name string) (os.FileInfo, bool, error) {
+	var info os.FileInfo
+	var lstatCalled bool
+	var err error
+	if lstater, ok := f.Fs.(afero.Lstater); ok {
+		info, lstatCalled, err = lstater.LstatIfPossible(name)
+	} else {
+		info, err = f.Fs.Stat(name)
+	}
+	if err != nil {
+		return nil, lstatCalled, err
+	}
+
+	if f.ignored(name, info.IsDir()) {
+		return nil, lstatCalled, f.pathError("lstat", name)
+	}
+	return info, lstatCalled, nil
+}
+
+func (f *Fs) Open(name string) (afero.File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (f *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	file, err := f.Fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Writing is always possible.
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_APPEND|os.O_TRUNC) != 0 {
+		return &File{File: file, fs: f, name: name}, nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	if f.ignored(name, info.IsDir()) {
+		_ = file.Close()
+		return nil, f.pathError("open", name)
+	}
+
+	return &File{File: file, fs: f, name: name}, nil
+}
+
+// File is an afero.File which hides ignored files in directory listings.
+type File struct {
+	afero.File
+	fs   *Fs
+	name string
+}
+
+func (f *File) Readdir(count int) ([]os.FileInfo, error) {
+	var result []os.FileInfo
+	for {
+		infos, err := f.File.Readdir(count)
+		for _, info := range infos {
+			if !f.fs.ignored(filepath.Join(f.name, info.Name()), info.IsDir()) {
+				result = append(result, info)
+			}
+		}
+
+		// If everything was filtered, read the next entries,
+		// as returning nothing would indicate the end of the directory.
+		if err == nil && count > 0 && len(result) == 0 && len(infos) > 0 {
+			continue
+		}
+
+		if err == io.EOF && len(result) > 0 {
+			return result, nil
+		}
+		return result, err
+	}
+}
+
+func (f *File) Readdirnames(count int) ([]string, error) {
+	infos, err := f.Readdir(count)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names, err
+}
@@ -0,0 +1,134 @@
+package nogoafero
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/aligator/nogo"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFs creates a MemMapFs with some ignored files and a NoGo instance
+// which already loaded the .gitignore files.
+func newTestFs(t *testing.T) (afero.Fs, *nogo.NoGo) {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		".gitignore":                "*.log\n/build\n",
+		"main.go":                   "package main",
+		"debug.log":                 "log",
+		"build/out":                 "binary",
+		"sub/.gitignore":            "secret.txt",
+		"sub/secret.txt":            "secret",
+		"sub/public.txt":            "public",
+		"sub/deeper/also-debug.log": "log",
+	}
+	for name, content := range files {
+		require.NoError(t, afero.WriteFile(fs, name, []byte(content), 0644))
+	}
+
+	n := nogo.New()
+	require.NoError(t, n.AddFromFS(afero.NewIOFS(fs), ".gitignore"))
+	return fs, n
+}
+
+func TestFs_Read(t *testing.T) {
+	base, n := newTestFs(t)
+	fs := Wrap(base, n)
+
+	tests := []struct {
+		name    string
+		ignored bool
+	}{
+		{name: "main.go"},
+		{name: "./sub/public.txt"},
+		{name: "debug.log", ignored: true},
+		{name: "build", ignored: true},
+		{name: "build/out", ignored: true},
+		{name: "sub/secret.txt", ignored: true},
+		{name: "sub/deeper/also-debug.log", ignored: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fs.Stat(tt.name)
+			file, openErr := fs.Open(tt.name)
+
+			if tt.ignored {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				assert.ErrorIs(t, openErr, os.ErrNotExist)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.NoError(t, openErr)
+			assert.NoError(t, file.Close())
+		})
+	}
+}
+
+func TestFs_Readdir(t *testing.T) {
+	base, n := newTestFs(t)
+	fs := Wrap(base, n)
+
+	names := func(dir string) []string {
+		file, err := fs.Open(dir)
+		require.NoError(t, err)
+		defer file.Close()
+
+		names, err := file.Readdirnames(-1)
+		require.NoError(t, err)
+		sort.Strings(names)
+		return names
+	}
+
+	assert.Equal(t, []string{".gitignore", "main.go", "sub"}, names("/"))
+	assert.Equal(t, []string{".gitignore", "deeper", "public.txt"}, names("sub"))
+	assert.Empty(t, names("sub/deeper"))
+
+	t.Run("with count", func(t *testing.T) {
+		file, err := fs.Open("/")
+		require.NoError(t, err)
+		defer file.Close()
+
+		var all []string
+		for {
+			infos, err := file.Readdir(1)
+			if err != nil {
+				break
+			}
+			require.Len(t, infos, 1)
+			all = append(all, infos[0].Name())
+		}
+		sort.Strings(all)
+		assert.Equal(t, []string{".gitignore", "main.go", "sub"}, all)
+	})
+}
+
+func TestFs_Write(t *testing.T) {
+	base, n := newTestFs(t)
+	fs := Wrap(base, n)
+
+	// Writing to ignored files is possible.
+	require.NoError(t, afero.WriteFile(fs, "new.log", []byte("new"), 0644))
+	require.NoError(t, fs.MkdirAll("build/sub", 0755))
+
+	// But reading is not.
+	_, err := afero.ReadFile(fs, "new.log")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	data, err := afero.ReadFile(base, "new.log")
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
+
+func TestWithIgnoredError(t *testing.T) {
+	base, n := newTestFs(t)
+	errIgnored := errors.New("ignored")
+	fs := Wrap(base, n, WithIgnoredError(errIgnored))
+
+	_, err := fs.Stat("debug.log")
+	assert.ErrorIs(t, err, errIgnored)
+}
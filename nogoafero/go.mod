@@ -0,0 +1,18 @@
+module github.com/aligator/nogo/nogoafero
+
+go 1.20
+
+require (
+	github.com/aligator/nogo v0.0.0-00010101000000-000000000000
+	github.com/spf13/afero v1.9.5
+	github.com/stretchr/testify v1.8.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.3.7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/aligator/nogo => ../
@@ -0,0 +1,37 @@
+package nogoafero
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/aligator/nogo"
+	"github.com/spf13/afero"
+)
+
+// Walk does the same as afero.Walk but skips all files and folders
+// ignored by the NoGo instance. The walkFn is only called for paths which
+// are not ignored.
+//
+// You have to load the ignore files into the NoGo instance before,
+// e.g. using n.AddFromFS(afero.NewIOFS(fs), ".gitignore").
+//
+// In contrast to afero.Walk, filepath.SkipAll returned by walkFn stops the
+// walk without an error, like for filepath.Walk.
+func Walk(fs afero.Fs, n *nogo.NoGo, root string, walkFn filepath.WalkFunc) error {
+	iofs := afero.NewIOFS(fs)
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return walkFn(path, info, err)
+		}
+
+		if ok, err := n.WalkFunc(iofs, relative(path), info.IsDir(), nil); !ok {
+			return err
+		}
+
+		return walkFn(path, info, nil)
+	})
+	if err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
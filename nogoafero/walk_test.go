@@ -0,0 +1,48 @@
+package nogoafero
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	fs, n := newTestFs(t)
+
+	var paths []string
+	err := Walk(fs, n, ".", func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		paths = append(paths, path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		".",
+		".gitignore",
+		"main.go",
+		"sub",
+		"sub/.gitignore",
+		"sub/deeper",
+		"sub/public.txt",
+	}, paths)
+}
+
+func TestWalk_SkipAll(t *testing.T) {
+	fs, n := newTestFs(t)
+
+	var paths []string
+	err := Walk(fs, n, ".", func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		paths = append(paths, path)
+		if path == "main.go" {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitignore", "main.go"}, paths)
+}
@@ -0,0 +1,109 @@
+// Package nogoarchive writes tar and zip archives which only contain the
+// files which are not ignored by nogo, e.g. to create a build context or a
+// source bundle.
+package nogoarchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"io/fs"
+
+	"github.com/aligator/nogo"
+)
+
+// FilterTar writes all files and directories of src which are not ignored by n
+// as tar archive to dst. The rules have to be loaded before, e.g. using nogo.ForFS.
+//
+// Only regular files and directories are written. Other files, such as
+// symlinks, are skipped, as they can't be read using fs.FS.
+// The tar writer is closed, but dst is not.
+func FilterTar(dst io.Writer, src fs.FS, n *nogo.NoGo) error {
+	tw := tar.NewWriter(dst)
+	err := walk(src, n, func(path string, d fs.DirEntry, info fs.FileInfo) error {
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = archiveName(path, d)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return copyFile(tw, src, path)
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// FilterZip does the same as FilterTar but writes a zip archive.
+// The files are compressed using zip.Deflate.
+func FilterZip(dst io.Writer, src fs.FS, n *nogo.NoGo) error {
+	zw := zip.NewWriter(dst)
+	err := walk(src, n, func(path string, d fs.DirEntry, info fs.FileInfo) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = archiveName(path, d)
+		if !d.IsDir() {
+			header.Method = zip.Deflate
+		}
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return copyFile(w, src, path)
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// walk calls fn for all regular files and directories which are not ignored,
+// except the root.
+func walk(src fs.FS, n *nogo.NoGo, fn func(path string, d fs.DirEntry, info fs.FileInfo) error) error {
+	return n.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == "." || !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, d, info)
+	})
+}
+
+// archiveName returns the name of the path in the archive.
+// Directories end with a '/'.
+func archiveName(path string, d fs.DirEntry) string {
+	if d.IsDir() {
+		return path + "/"
+	}
+	return path
+}
+
+func copyFile(dst io.Writer, src fs.FS, path string) error {
+	f, err := src.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(dst, f)
+	return err
+}
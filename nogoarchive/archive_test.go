@@ -0,0 +1,82 @@
+package nogoarchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aligator/nogo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		".gitignore":       {Data: []byte("*.log\n/build\n")},
+		"main.go":          {Data: []byte("package main")},
+		"debug.log":        {Data: []byte("log")},
+		"build/out":        {Data: []byte("binary")},
+		"sub/.gitignore":   {Data: []byte("secret.txt")},
+		"sub/secret.txt":   {Data: []byte("secret")},
+		"sub/public.txt":   {Data: []byte("public")},
+		"sub/deeper/a.log": {Data: []byte("log")},
+	}
+}
+
+var wantFiles = map[string]string{
+	".gitignore":     "*.log\n/build\n",
+	"main.go":        "package main",
+	"sub/":           "",
+	"sub/.gitignore": "secret.txt",
+	"sub/public.txt": "public",
+	"sub/deeper/":    "",
+}
+
+func TestFilterTar(t *testing.T) {
+	fsys := newTestFS()
+	n, err := nogo.ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, FilterTar(&b, fsys, n))
+
+	got := make(map[string]string)
+	r := tar.NewReader(&b)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		got[header.Name] = string(content)
+	}
+	assert.Equal(t, wantFiles, got)
+}
+
+func TestFilterZip(t *testing.T) {
+	fsys := newTestFS()
+	n, err := nogo.ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, FilterZip(&b, fsys, n))
+
+	r, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		got[f.Name] = string(content)
+	}
+	assert.Equal(t, wantFiles, got)
+}
@@ -0,0 +1,143 @@
+// Package nogofsnotify provides a x.WatchBackend based on fsnotify and a
+// FilteredWatcher, which drops the fsnotify events of ignored paths.
+//
+// It is a separate module, so that nogo itself does not depend on fsnotify.
+package nogofsnotify
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/aligator/nogo/x"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Backend implements x.WatchBackend using fsnotify.
+type Backend struct {
+	watcher *fsnotify.Watcher
+	root    string
+
+	events chan string
+	errors chan error
+
+	// closing is closed by Close to stop sending events.
+	closing chan struct{}
+	// done is closed after all channels were closed.
+	done chan struct{}
+}
+
+var _ x.WatchBackend = (*Backend)(nil)
+
+// New creates a Backend which watches directories relative to the root directory.
+// The root has to be the same directory as used for the fs.FS passed to x.NewWatcher,
+// e.g. New(dir) and os.DirFS(dir).
+func New(root string) (*Backend, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	b := &Backend{
+		watcher: watcher,
+		root:    absRoot,
+		events:  make(chan string),
+		errors:  make(chan error),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+// Add starts watching the directory.
+func (b *Backend) Add(dir string) error {
+	return b.watcher.Add(b.abs(dir))
+}
+
+// Remove stops watching the directory.
+func (b *Backend) Remove(dir string) error {
+	return b.watcher.Remove(b.abs(dir))
+}
+
+// Events returns the paths of changed files and directories.
+func (b *Backend) Events() <-chan string {
+	return b.events
+}
+
+// Errors returns the errors of fsnotify.
+func (b *Backend) Errors() <-chan error {
+	return b.errors
+}
+
+// Close stops all watches and closes the channels.
+func (b *Backend) Close() error {
+	close(b.closing)
+	err := b.watcher.Close()
+	<-b.done
+	return err
+}
+
+func (b *Backend) run() {
+	defer close(b.done)
+	defer close(b.events)
+	defer close(b.errors)
+
+	for {
+		select {
+		case event, ok := <-b.watcher.Events:
+			if !ok {
+				return
+			}
+			// Only the content of the files is relevant.
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			name, ok := b.relative(event.Name)
+			if !ok {
+				continue
+			}
+			select {
+			case b.events <- name:
+			case <-b.closing:
+				return
+			}
+		case err, ok := <-b.watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case b.errors <- err:
+			case <-b.closing:
+				return
+			}
+		}
+	}
+}
+
+// abs converts the slash separated path relative to the root to an absolute path.
+func (b *Backend) abs(name string) string {
+	return filepath.Join(b.root, filepath.FromSlash(name))
+}
+
+// relative converts the absolute path to a slash separated path relative to the root.
+// It returns false if the path is not inside the root.
+func (b *Backend) relative(name string) (string, bool) {
+	return relativeTo(b.root, name)
+}
+
+// relativeTo converts the absolute path to a slash separated path relative to the root.
+// It returns false if the path is not inside the root.
+func relativeTo(root, name string) (string, bool) {
+	rel, err := filepath.Rel(root, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
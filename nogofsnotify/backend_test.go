@@ -0,0 +1,67 @@
+package nogofsnotify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aligator/nogo"
+	"github.com/aligator/nogo/x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+
+	b, err := New(dir)
+	require.NoError(t, err)
+	require.NoError(t, b.Add("sub"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", ".gitignore"), []byte("*.log"), 0644))
+
+	select {
+	case name := <-b.Events():
+		assert.Equal(t, "sub/.gitignore", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	require.NoError(t, b.Remove("sub"))
+	require.NoError(t, b.Close())
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log"), 0644))
+
+	b, err := New(dir)
+	require.NoError(t, err)
+
+	w, err := x.NewWatcher(os.DirFS(dir), ".gitignore", b)
+	require.NoError(t, err)
+	defer w.Close()
+
+	reloaded := make(chan struct{}, 10)
+	w.Subscribe(func(n *nogo.NoGo, err error) {
+		assert.NoError(t, err)
+		reloaded <- struct{}{}
+	})
+
+	assert.True(t, w.Match("debug.log", false))
+	assert.False(t, w.Match("secret.txt", false))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\nsecret.txt"), 0644))
+
+	// Writing a file may result in several events.
+	timeout := time.After(5 * time.Second)
+	for !w.Match("secret.txt", false) {
+		select {
+		case <-reloaded:
+		case <-timeout:
+			t.Fatal("rules not reloaded")
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package nogofsnotify
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/aligator/nogo"
+	"github.com/fsnotify/fsnotify"
+)
+
+// ErrIgnored is returned by FilteredWatcher.Add for ignored directories.
+var ErrIgnored = errors.New("nogofsnotify: the directory is ignored")
+
+// FilteredWatcher wraps a fsnotify.Watcher. It drops the events of ignored
+// paths and refuses to watch ignored directories, e.g. so that dev servers
+// don't rebuild on changes inside of node_modules.
+//
+// The paths are matched using nogo.EventFilter, relative to the root.
+// Events of paths outside of the root are passed through.
+type FilteredWatcher struct {
+	// Events contains the events of the paths which are not ignored.
+	Events chan fsnotify.Event
+	// Errors contains the errors of fsnotify.
+	Errors chan error
+
+	watcher *fsnotify.Watcher
+	n       *nogo.NoGo
+	filter  func(path string) bool
+	root    string
+
+	// closing is closed by Close to stop sending events.
+	closing chan struct{}
+	// done is closed after all channels were closed.
+	done chan struct{}
+}
+
+// NewFilteredWatcher creates a FilteredWatcher which matches the paths using n.
+// The root is the directory the rules are relative to, e.g. the directory
+// used for os.DirFS when loading them. The rules of n must not be changed
+// while watching, except by the lazy loading of nogo.New.
+//
+// Use nogo.WithStatFS(os.DirFS(root)) for n, so that rules ending with a '/'
+// also drop the events of the directories themselves.
+func NewFilteredWatcher(root string, n *nogo.NoGo) (*FilteredWatcher, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &FilteredWatcher{
+		Events:  make(chan fsnotify.Event),
+		Errors:  make(chan error),
+		watcher: watcher,
+		n:       n,
+		filter:  nogo.EventFilter(n),
+		root:    absRoot,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Add starts watching the directory, like fsnotify.Watcher.Add.
+// It returns an error wrapping ErrIgnored if the directory is ignored.
+func (w *FilteredWatcher) Add(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return err
+	}
+	if rel, ok := relativeTo(w.root, abs); ok && rel != "." && w.n.Match(rel, true) {
+		return fmt.Errorf("%w: %s", ErrIgnored, name)
+	}
+	return w.watcher.Add(abs)
+}
+
+// Remove stops watching the directory, like fsnotify.Watcher.Remove.
+func (w *FilteredWatcher) Remove(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return err
+	}
+	return w.watcher.Remove(abs)
+}
+
+// Close stops all watches and closes the channels.
+func (w *FilteredWatcher) Close() error {
+	close(w.closing)
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
+
+func (w *FilteredWatcher) run() {
+	defer close(w.done)
+	defer close(w.Events)
+	defer close(w.Errors)
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if rel, ok := relativeTo(w.root, event.Name); ok && rel != "." && !w.filter(rel) {
+				continue
+			}
+			select {
+			case w.Events <- event:
+			case <-w.closing:
+				return
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.Errors <- err:
+			case <-w.closing:
+				return
+			}
+		}
+	}
+}
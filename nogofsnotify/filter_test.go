@@ -0,0 +1,51 @@
+package nogofsnotify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aligator/nogo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilteredWatcher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "react"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/\n*.log"), 0644))
+
+	n, err := nogo.ForFS(os.DirFS(dir), ".gitignore", nogo.WithStatFS(os.DirFS(dir)))
+	require.NoError(t, err)
+
+	w, err := NewFilteredWatcher(dir, n)
+	require.NoError(t, err)
+	defer w.Close()
+
+	err = w.Add(filepath.Join(dir, "node_modules"))
+	assert.True(t, errors.Is(err, ErrIgnored), err)
+	require.NoError(t, w.Add(dir))
+
+	// The ignored files are written first, so their events would arrive first.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "node_modules2"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-w.Events:
+			name := filepath.Base(event.Name)
+			assert.NotEqual(t, "debug.log", name)
+			if name == "main.go" {
+				return
+			}
+		case err := <-w.Errors:
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatal("no event for main.go")
+		}
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package nogo
+
+const raceEnabled = false
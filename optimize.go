@@ -0,0 +1,190 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// Reasons why Optimize removed a rule.
+const (
+	// ReasonDuplicate means that the same rule is repeated later.
+	ReasonDuplicate = "duplicate"
+	// ReasonShadowed means that a later rule matches every path the rule matches,
+	// so the rule never decides anything, e.g. "debug.log" followed by "*.log".
+	ReasonShadowed = "shadowed"
+	// ReasonSubsumed means that the rule only matches paths inside of a folder
+	// which is already excluded, e.g. "foo/**" and "foo/".
+	ReasonSubsumed = "subsumed"
+)
+
+// RemovedRule is a rule removed by OptimizeReport.
+type RemovedRule struct {
+	Rule Rule
+	// By is the rule which makes the removed rule redundant.
+	By     Rule
+	Reason string
+}
+
+// Optimize removes rules which don't change the result of matching, see OptimizeReport.
+func Optimize(rules []Rule) []Rule {
+	optimized, _ := OptimizeReport(rules)
+	return optimized
+}
+
+// OptimizeReport removes rules which don't change the result of matching
+// and reports why each of them was removed:
+//   - Duplicates of a later rule.
+//   - Rules for which a later rule matches every path they match. Only rules
+//     consisting of literals are checked, e.g. "/build" or "debug.log".
+//   - Rules like "foo/**" if the folder "foo" is excluded by another rule
+//     and no negated rule can re-include it.
+//
+// The rules have to be in the order they are checked, e.g. all rules of
+// NoGo.Groups. The order of the remaining rules is kept.
+// It is meant for ignore files, not for allow lists, where negated rules
+// may re-include paths inside of excluded folders.
+func OptimizeReport(rules []Rule) ([]Rule, []RemovedRule) {
+	removed := make([]*RemovedRule, len(rules))
+
+	// Only the last of duplicated rules can decide anything.
+	seen := make(map[string]int)
+	for i := len(rules) - 1; i >= 0; i-- {
+		key, ok := ruleKey(rules[i])
+		if !ok {
+			continue
+		}
+		if by, ok := seen[key]; ok {
+			removed[i] = &RemovedRule{Rule: rules[i], By: rules[by], Reason: ReasonDuplicate}
+			continue
+		}
+		seen[key] = i
+	}
+
+	for i, rule := range rules {
+		if removed[i] != nil {
+			continue
+		}
+		if by, ok := shadowedBy(rules, removed, i); ok {
+			removed[i] = &RemovedRule{Rule: rule, By: rules[by], Reason: ReasonShadowed}
+		}
+	}
+
+	for i, rule := range rules {
+		if removed[i] != nil {
+			continue
+		}
+		if by, ok := subsumedBy(rules, removed, i); ok {
+			removed[i] = &RemovedRule{Rule: rule, By: rules[by], Reason: ReasonSubsumed}
+		}
+	}
+
+	optimized := make([]Rule, 0, len(rules))
+	var report []RemovedRule
+	for i, rule := range rules {
+		if removed[i] != nil {
+			report = append(report, *removed[i])
+			continue
+		}
+		optimized = append(optimized, rule)
+	}
+	return optimized, report
+}
+
+// ruleKey returns a key which is the same for rules which match the same way.
+// ok is false for rules without a pattern.
+func ruleKey(rule Rule) (key string, ok bool) {
+	pattern, _, skip := cleanPattern(rule.Pattern)
+	if skip {
+		return "", false
+	}
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var flags string
+	if rule.Negate {
+		flags += "!"
+	}
+	if rule.OnlyFolder {
+		flags += "/"
+	}
+	return strings.Trim(rule.Prefix, "/") + "\x00" + pattern + "\x00" + flags, true
+}
+
+// shadowedBy returns a later rule which matches every path the rule at index i matches.
+func shadowedBy(rules []Rule, removed []*RemovedRule, i int) (int, bool) {
+	t := analyzeTerminalRule(rules[i])
+	if !t.literal {
+		return 0, false
+	}
+
+	var path string
+	switch {
+	case t.unanchored && t.name != "":
+		path = joinPrefix(t.path, t.name)
+	case !t.unanchored && t.self:
+		path = t.path
+	default:
+		return 0, false
+	}
+
+	for j := len(rules) - 1; j > i; j-- {
+		later := rules[j]
+		if removed[j] != nil || later.OnlyFolder && !rules[i].OnlyFolder {
+			continue
+		}
+		if t.unanchored && !isNamePattern(later, t.path) {
+			continue
+		}
+		if later.MatchPath(path).Found {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// isNamePattern checks if the rule matches by the name at any level below
+// the prefix, e.g. "*.log", and if prefix is inside of the prefix of the rule.
+func isNamePattern(rule Rule, prefix string) bool {
+	pattern, _, skip := cleanPattern(rule.Pattern)
+	if skip {
+		return false
+	}
+	return !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") &&
+		isPathInside(prefix, strings.Trim(rule.Prefix, "/"))
+}
+
+// subsumedBy returns a rule which excludes the folder or one of its parents,
+// if the rule at index i only matches paths inside of that folder (e.g. "foo/**").
+func subsumedBy(rules []Rule, removed []*RemovedRule, i int) (int, bool) {
+	t := analyzeTerminalRule(rules[i])
+	if rules[i].Negate || !t.literal || t.unanchored || t.self || t.path == "" {
+		return 0, false
+	}
+
+	// No negated rule may re-include the folder or one of its parents.
+	for _, rule := range rules {
+		if rule.Negate && matchesFolderOrParent(rule, t.path) {
+			return 0, false
+		}
+	}
+
+	for j, rule := range rules {
+		if j == i || removed[j] != nil || rule.Negate {
+			continue
+		}
+		if matchesFolderOrParent(rule, t.path) {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// matchesFolderOrParent checks if the rule matches the folder or one of its parents.
+func matchesFolderOrParent(rule Rule, folder string) bool {
+	for end := segmentEnd(folder, 0); ; end = segmentEnd(folder, end+1) {
+		if rule.MatchPath(folder[:end]).Found {
+			return true
+		}
+		if end == len(folder) {
+			return false
+		}
+	}
+}
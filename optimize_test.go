@@ -0,0 +1,116 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		want    []string
+		removed map[string]string
+	}{
+		{
+			name:    "duplicates",
+			rules:   MustCompileAll("", []byte("*.log\nbuild/\n*.log\n!keep\n!keep")),
+			want:    []string{"build/", "*.log", "!keep"},
+			removed: map[string]string{"*.log": ReasonDuplicate, "!keep": ReasonDuplicate},
+		},
+		{
+			name:    "not duplicated in other prefix",
+			rules:   append(MustCompileAll("", []byte("/a")), MustCompileAll("sub", []byte("/a"))...),
+			want:    []string{"/a", "/a"},
+			removed: map[string]string{},
+		},
+		{
+			name:    "shadowed",
+			rules:   MustCompileAll("", []byte("debug.log\n/build\n/dist/\n*.log\nbuil*\n!dist")),
+			want:    []string{"*.log", "buil*", "!dist"},
+			removed: map[string]string{"debug.log": ReasonShadowed, "/build": ReasonShadowed, "/dist/": ReasonShadowed},
+		},
+		{
+			name:    "not shadowed by folder rule",
+			rules:   MustCompileAll("", []byte("/build\nbuild/")),
+			want:    []string{"/build", "build/"},
+			removed: map[string]string{},
+		},
+		{
+			name:    "not shadowed by earlier rule",
+			rules:   MustCompileAll("", []byte("*.log\ndebug.log")),
+			want:    []string{"*.log", "debug.log"},
+			removed: map[string]string{},
+		},
+		{
+			name:    "not shadowed by anchored rule in other folder",
+			rules:   MustCompileAll("", []byte("debug.log\n/debug.log")),
+			want:    []string{"debug.log", "/debug.log"},
+			removed: map[string]string{},
+		},
+		{
+			name:    "shadowed by rule of parent folder",
+			rules:   append(MustCompileAll("sub", []byte("debug.log")), MustCompileAll("", []byte("*.log"))...),
+			want:    []string{"*.log"},
+			removed: map[string]string{"debug.log": ReasonShadowed},
+		},
+		{
+			name:    "subsumed",
+			rules:   MustCompileAll("", []byte("foo/**\nfoo/\na/b/**\n/a")),
+			want:    []string{"foo/", "/a"},
+			removed: map[string]string{"foo/**": ReasonSubsumed, "a/b/**": ReasonSubsumed},
+		},
+		{
+			name:    "not subsumed if re-included",
+			rules:   MustCompileAll("", []byte("foo/**\nfoo/\n!foo/")),
+			want:    []string{"foo/**", "!foo/"},
+			removed: map[string]string{"foo/": ReasonShadowed},
+		},
+		{
+			name:    "not subsumed without excluded folder",
+			rules:   MustCompileAll("", []byte("foo/**\nbar/")),
+			want:    []string{"foo/**", "bar/"},
+			removed: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optimized, removed := OptimizeReport(tt.rules)
+
+			var got []string
+			for _, rule := range optimized {
+				got = append(got, rule.Pattern)
+			}
+			assert.Equal(t, tt.want, got)
+
+			gotRemoved := make(map[string]string)
+			for _, r := range removed {
+				gotRemoved[r.Rule.Pattern] = r.Reason
+				assert.NotEmpty(t, r.By.Pattern)
+			}
+			assert.Equal(t, tt.removed, gotRemoved)
+			assert.Equal(t, optimized, Optimize(tt.rules))
+		})
+	}
+}
+
+func TestOptimize_SameResult(t *testing.T) {
+	rules := MustCompileAll("", []byte("debug.log\n*.log\n!keep.log\nfoo/**\nfoo/\n/build\nbuild*\n*.log\nsub/x/**\nsub/\n!sub/y"))
+	optimized := Optimize(rules)
+	assert.Less(t, len(optimized), len(rules))
+
+	n := New()
+	n.AddRules(rules...)
+	o := New()
+	o.AddRules(optimized...)
+
+	for _, path := range []string{
+		"debug.log", "a/debug.log", "keep.log", "foo", "foo/a", "foo/a/b", "build", "build/a",
+		"buildx", "sub/x/a", "sub/y", "sub", "main.go",
+	} {
+		for _, isDir := range []bool{false, true} {
+			assert.Equal(t, n.Match(path, isDir), o.Match(path, isDir), "%s %v", path, isDir)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package nogo
+
+import "io/fs"
+
+// Option configures a NoGo instance and can be passed to New.
+//
+// Every Rule is also an Option which just adds the rule.
+// So you can pass rules and other options mixed:
+//
+//	n := nogo.New(nogo.DotGitRule, nogo.WithCache(1000))
+type Option interface {
+	apply(n *NoGo)
+}
+
+type optionFunc func(n *NoGo)
+
+func (f optionFunc) apply(n *NoGo) {
+	f(n)
+}
+
+// apply adds the rule to NoGo, which makes each Rule usable as Option.
+func (r Rule) apply(n *NoGo) {
+	n.AddRules(r)
+}
+
+// Apply applies the options to n and returns n.
+// Usually the options are passed to New instead. WithFS only takes effect
+// if it is passed to New or ForFS.
+func (n *NoGo) Apply(opts ...Option) *NoGo {
+	// Rules are added together, so that the rules are only indexed once
+	// instead of once per rule.
+	var rules []Rule
+	for _, opt := range opts {
+		if rule, ok := opt.(Rule); ok {
+			rules = append(rules, rule)
+			continue
+		}
+		if len(rules) > 0 {
+			n.AddRules(rules...)
+			rules = nil
+		}
+		opt.apply(n)
+	}
+	if len(rules) > 0 {
+		n.AddRules(rules...)
+	}
+	return n
+}
+
+// WithCache enables caching of match results.
+// It is useful if the same paths get matched many times,
+// e.g. when NoGo is used in a file watcher.
+//
+// Size is the maximum amount of cached results. If it is reached, the
+// least recently used result gets removed.
+// A size <= 0 disables the cache.
+//
+// The cache gets invalidated whenever rules are added.
+func WithCache(size int) Option {
+	return optionFunc(func(n *NoGo) {
+		if size <= 0 {
+			n.cache = nil
+			return
+		}
+		n.cache = newMatchCache(size)
+	})
+}
+
+// WithRules adds the rules, e.g. the result of CompileAll.
+// It does the same as AddRules.
+func WithRules(rules ...Rule) Option {
+	return optionFunc(func(n *NoGo) {
+		n.AddRules(rules...)
+	})
+}
+
+// WithFS sets the file system from which New loads the ignore files
+// (see WithIgnoreFileNames). As New can't return errors, the ignore files
+// are loaded lazily like with WithLazyLoading. Use ForFS to load them at once.
+func WithFS(fsys fs.FS) Option {
+	return optionFunc(func(n *NoGo) {
+		n.loadFS = fsys
+	})
+}
+
+// WithIgnoreFileNames sets the names of the ignore files which are loaded
+// from the file system of WithFS. The default is ".gitignore".
+// If a folder contains several of them, the rules of later names take
+// precedence over earlier ones.
+func WithIgnoreFileNames(names ...string) Option {
+	return optionFunc(func(n *NoGo) {
+		n.ignoreFileNames = names
+	})
+}
@@ -0,0 +1,70 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRules(t *testing.T) {
+	rules := MustCompileAll("", []byte("*.log\n!keep.log"))
+	n := New(WithRules(rules...), WithCache(10))
+
+	assert.True(t, n.Match("a.log", false))
+	assert.False(t, n.Match("keep.log", false))
+	assert.Len(t, n.Groups(), 2)
+}
+
+func TestNew_Rules(t *testing.T) {
+	rules := MustCompileAll("", []byte("*.log\n!keep.log\nbuild"))
+	n := New(rules[0], WithBackend(GlobBackend), rules[1], rules[2])
+
+	added := New()
+	added.AddRules(rules...)
+	assert.Equal(t, added.Groups(), n.Groups(), "the rules keep their order")
+	assert.True(t, n.Match("a.log", false))
+	assert.False(t, n.Match("keep.log", false))
+	assert.True(t, n.Match("build", true))
+}
+
+func TestWithFS(t *testing.T) {
+	fsys := newForFSTestFS()
+	fsys.MapFS[".dockerignore"] = &fstest.MapFile{Data: []byte("main.go\n!debug.log")}
+	fsys.MapFS["sub/.dockerignore"] = &fstest.MapFile{Data: []byte("keep.log")}
+
+	t.Run("default name", func(t *testing.T) {
+		n := New(WithFS(fsys), DotGitRule)
+		assert.Empty(t, fsys.opened, "the files are loaded lazily")
+
+		assert.True(t, n.Match(".git", true))
+		assert.True(t, n.Match("debug.log", false))
+		assert.False(t, n.Match("main.go", false))
+		assert.False(t, n.Match("sub/keep.log", false))
+		assert.NoError(t, n.LazyLoadErr())
+	})
+
+	t.Run("several names", func(t *testing.T) {
+		n := New(WithFS(fsys), WithIgnoreFileNames(".gitignore", ".dockerignore"))
+
+		// The rules of later names take precedence.
+		assert.False(t, n.Match("debug.log", false))
+		assert.True(t, n.Match("main.go", false))
+		assert.True(t, n.Match("sub/keep.log", false))
+		assert.True(t, n.Match("sub/out", true))
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		n := NewAllowList(WithFS(fsys), WithIgnoreFileNames(".dockerignore"))
+		assert.False(t, n.Match("main.go", false))
+		assert.True(t, n.Match("other.go", false))
+	})
+
+	t.Run("ForFS overrides it", func(t *testing.T) {
+		n, err := ForFS(fsys, ".dockerignore", WithFS(fstest.MapFS{}), WithIgnoreFileNames(".gitignore"))
+		require.NoError(t, err)
+		assert.True(t, n.Match("main.go", false))
+		assert.Nil(t, n.lazy)
+	})
+}
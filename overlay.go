@@ -0,0 +1,33 @@
+package nogo
+
+// WithExtra returns a Matcher which checks the patterns on top of the rules of n,
+// e.g. for the exclude flags of rsync or tar like tools, which have to be
+// evaluated together with the ignore files of a repository:
+//
+//	m := n.WithExtra(excludes...)
+//	err := nogo.WalkDirMatcher(fsys, ".", m, fn)
+//
+// The patterns are relative to the root and take precedence over all rules
+// of n, so a negated pattern like "!keep.log" includes a path ignored by an
+// ignore file. They are compiled using the dialect and options of n, which is
+// not modified. So a new overlay can be created for each call, while n is reused.
+//
+// Invalid patterns are skipped, like git skips lines it can't match.
+// Use Translate to validate them before.
+func (n *NoGo) WithExtra(patterns ...string) Matcher {
+	rules, err := n.dialect.compileLines("", patterns)
+	if err != nil {
+		// Compile each pattern on its own to skip only the invalid ones.
+		rules = make([]Rule, 0, len(patterns))
+		for _, pattern := range patterns {
+			compiled, err := n.dialect.compileLines("", []string{pattern})
+			if err != nil {
+				continue
+			}
+			rules = append(rules, compiled...)
+		}
+	}
+
+	extra := n.derive([]group{{rules: rules}})
+	return Chain(n, extra)
+}
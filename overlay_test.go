@@ -0,0 +1,58 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_WithExtra(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n")},
+		"sub/.gitignore": {Data: []byte("/out\n")},
+	}
+	n, err := ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	m := n.WithExtra("*.tmp", "!keep.log", "[a-z", "/build/")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "debug.log", want: true},
+		{path: "sub/out", want: true},
+		{path: "a.tmp", want: true},
+		{path: "sub/a.tmp", want: true},
+		{path: "keep.log", want: false},
+		{path: "sub/keep.log", want: false},
+		{path: "build", isDir: true, want: true},
+		{path: "build/x", want: true},
+		{path: "sub/build", isDir: true, want: false},
+		{path: "main.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir))
+		})
+	}
+
+	match, because := m.MatchBecause("keep.log", false)
+	assert.False(t, match)
+	assert.Equal(t, "!keep.log", because.Pattern)
+
+	// The base is not modified.
+	assert.False(t, n.Match("a.tmp", false))
+	assert.True(t, n.Match("keep.log", false))
+	assert.Len(t, n.Groups(), 2)
+}
+
+func TestNoGo_WithExtra_IgnoreCase(t *testing.T) {
+	n := New(WithIgnoreCase())
+	m := n.WithExtra("Build/")
+	assert.True(t, m.Match("build", true))
+	assert.True(t, m.Match("BUILD/x", false))
+}
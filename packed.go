@@ -0,0 +1,27 @@
+package nogo
+
+// LoadPacked creates a NoGo with the options and loads the rules encoded by
+// MarshalBinary. The ignore files don't have to be read and parsed again,
+// so this is meant for default rules which are embedded into a program,
+// e.g. the Go source generated by "nogo gen".
+//
+// The data has to be created by the same version of nogo, otherwise
+// ErrIncompatibleVersion is returned. So generated code has to be
+// generated again after updating nogo.
+func LoadPacked(data []byte, opts ...Option) (*NoGo, error) {
+	n := New(opts...)
+	if err := n.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// MustLoadPacked does the same as LoadPacked but panics on error.
+// It is meant for package level variables.
+func MustLoadPacked(data []byte, opts ...Option) *NoGo {
+	n, err := LoadPacked(data, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
@@ -0,0 +1,42 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPacked(t *testing.T) {
+	n := New(DotGitRule)
+	require.NoError(t, n.AddFromFS(fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n!keep.log\n")},
+		"sub/.gitignore": {Data: []byte("/build/\n")},
+	}, ".gitignore"))
+
+	data, err := n.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded, err := LoadPacked(data, WithIgnoreCase())
+	require.NoError(t, err)
+	assert.True(t, loaded.Match(".git", true))
+	assert.True(t, loaded.Match("DEBUG.LOG", false))
+	assert.False(t, loaded.Match("keep.log", false))
+	assert.True(t, loaded.Match("sub/build", true))
+	assert.False(t, loaded.Match("build", true))
+
+	_, err = LoadPacked([]byte("invalid"))
+	assert.Error(t, err)
+
+	_, err = LoadPacked(append([]byte(marshalMagic+string(rune(marshalVersion+1))), data[5:]...))
+	assert.ErrorIs(t, err, ErrIncompatibleVersion)
+}
+
+func TestMustLoadPacked(t *testing.T) {
+	data, err := New(DotGitRule).MarshalBinary()
+	require.NoError(t, err)
+
+	assert.True(t, MustLoadPacked(data).Match(".git", true))
+	assert.Panics(t, func() { MustLoadPacked(nil) })
+}
@@ -0,0 +1,157 @@
+package nogo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// utf8BOM is the byte order mark some editors write at the start of a file.
+// Like git, it is removed before parsing an ignore file.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// splitLines removes the byte order mark and splits the data into lines
+// without the line endings. Both "\n" and "\r\n" are supported, even mixed.
+func splitLines(data []byte) []string {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		// Remove \r on windows.
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// ParseWarning describes a line which is valid but most likely doesn't do
+// what it is meant to do.
+type ParseWarning struct {
+	// Line is the line number, starting at 1.
+	Line    int
+	Pattern string
+	Message string
+}
+
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("line %d: %q: %s", w.Line, w.Pattern, w.Message)
+}
+
+// ParseReport is the result of Parse.
+type ParseReport struct {
+	// Rules are the same as returned by CompileAll.
+	Rules []Rule
+
+	// RuleLines contains the line number of each rule, starting at 1.
+	// It is nil for DialectHelmignore and DialectHgignore, as their rules
+	// don't correspond to single lines.
+	RuleLines []int
+
+	// BlankLines and CommentLines contain the numbers of the lines without a pattern.
+	BlankLines   []int
+	CommentLines []int
+
+	// BOM is true if the data started with a UTF-8 byte order mark.
+	BOM bool
+
+	// Warnings are only set in strict mode.
+	Warnings []ParseWarning
+}
+
+// Parse does the same as CompileAll but also reports the positions of the
+// rules, blank lines and comments.
+//
+// In strict mode, lines which most likely don't do what they are meant to do
+// are reported as warnings, e.g. a trailing backslash which doesn't escape
+// anything. Lines with an unclosed range like "[a-z" are reported as warning
+// and skipped, as git never matches them. Otherwise they are an error,
+// the same as for CompileAll.
+func Parse(prefix string, data []byte, strict bool) (*ParseReport, error) {
+	return DialectGitignore.Parse(prefix, data, strict)
+}
+
+// Parse does the same as the function Parse using the syntax of the dialect.
+func (d Dialect) Parse(prefix string, data []byte, strict bool) (*ParseReport, error) {
+	report := &ParseReport{
+		BOM: bytes.HasPrefix(data, utf8BOM),
+	}
+
+	lines := splitLines(data)
+	for i, line := range lines {
+		number := i + 1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			report.BlankLines = append(report.BlankLines, number)
+			continue
+		case d != DialectHgignore && line[0] == '#':
+			report.CommentLines = append(report.CommentLines, number)
+			continue
+		case d == DialectHgignore && trimmed[0] == '#':
+			report.CommentLines = append(report.CommentLines, number)
+			continue
+		}
+
+		if d == DialectHelmignore || d == DialectHgignore {
+			continue
+		}
+
+		if strict {
+			warnings, skip := patternWarnings(number, line)
+			report.Warnings = append(report.Warnings, warnings...)
+			if skip {
+				continue
+			}
+		}
+
+		rules, err := d.compile(prefix, line)
+		if err != nil {
+			return nil, patternErrorAt(err, "", number)
+		}
+		for range rules {
+			report.RuleLines = append(report.RuleLines, number)
+		}
+		report.Rules = append(report.Rules, rules...)
+	}
+
+	if d == DialectHelmignore || d == DialectHgignore {
+		rules, err := d.compileLines(prefix, lines)
+		if err != nil {
+			return nil, err
+		}
+		report.Rules = rules
+	}
+	if report.Rules == nil {
+		report.Rules = make([]Rule, 0)
+	}
+	return report, nil
+}
+
+// patternWarnings checks a line of a gitignore file for common mistakes.
+// skip is true if the line can't be compiled.
+func patternWarnings(number int, line string) (warnings []ParseWarning, skip bool) {
+	pattern, _, skip := cleanPattern(line)
+	if skip {
+		return nil, false
+	}
+
+	warn := func(message string) {
+		warnings = append(warnings, ParseWarning{Line: number, Pattern: line, Message: message})
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i == len(pattern)-1 {
+				warn("trailing backslash doesn't escape anything, the pattern never matches")
+			}
+			i++
+		case '[':
+			end := rangeEnd(pattern, i+1)
+			if end < 0 {
+				warn("unclosed range, the pattern never matches")
+				return warnings, true
+			}
+			i = end
+		}
+	}
+	return warnings, false
+}
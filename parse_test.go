@@ -0,0 +1,108 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileAll_BOM(t *testing.T) {
+	rules, err := CompileAll("", []byte("\xef\xbb\xbf*.log\r\nbuild/\n"))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "*.log", rules[0].Pattern)
+
+	n := New()
+	n.AddRules(rules...)
+	assert.True(t, n.Match("a.log", false))
+}
+
+func TestParse(t *testing.T) {
+	data := []byte("\xef\xbb\xbf# comment\r\n*.log\n\n   \nbuild/\r\n\\#hash\n!keep.log")
+
+	report, err := Parse("", data, false)
+	require.NoError(t, err)
+
+	want, err := CompileAll("", data)
+	require.NoError(t, err)
+	assert.Equal(t, want, report.Rules)
+	assert.Equal(t, []int{2, 5, 6, 7}, report.RuleLines)
+	assert.Equal(t, []int{3, 4}, report.BlankLines)
+	assert.Equal(t, []int{1}, report.CommentLines)
+	assert.True(t, report.BOM)
+	assert.Nil(t, report.Warnings)
+}
+
+func TestParse_Strict(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "*.log", want: nil},
+		{line: "file[a-z].txt", want: nil},
+		{line: `\[a`, want: nil},
+		{line: "file[a-z.txt", want: []string{"unclosed range, the pattern never matches"}},
+		{line: `foo\`, want: []string{"trailing backslash doesn't escape anything, the pattern never matches"}},
+		{line: `foo\\`, want: nil},
+		{line: `foo\ `, want: nil},
+		{line: `[a\`, want: []string{"unclosed range, the pattern never matches"}},
+		{line: "# [comment", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			report, err := Parse("", []byte("\n"+tt.line), true)
+			require.NoError(t, err)
+
+			var got []string
+			for _, warning := range report.Warnings {
+				assert.Equal(t, 2, warning.Line)
+				assert.Equal(t, tt.line, warning.Pattern)
+				got = append(got, warning.Message)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unclosed range is skipped", func(t *testing.T) {
+		report, err := Parse("", []byte("a[\nb"), true)
+		require.NoError(t, err)
+		assert.Len(t, report.Rules, 1)
+		assert.Equal(t, []int{2}, report.RuleLines)
+
+		_, err = Parse("", []byte("b\na["), false)
+		assert.ErrorContains(t, err, "line 2: ")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		w := ParseWarning{Line: 3, Pattern: "a[", Message: "unclosed range"}
+		assert.Equal(t, `line 3: "a[": unclosed range`, w.String())
+	})
+}
+
+func TestDialect_Parse(t *testing.T) {
+	t.Run("braces", func(t *testing.T) {
+		report, err := DialectBraces.Parse("", []byte("# comment\n{a,b}.txt\nc"), false)
+		require.NoError(t, err)
+		assert.Len(t, report.Rules, 3)
+		assert.Equal(t, []int{2, 2, 3}, report.RuleLines)
+		assert.Equal(t, []int{1}, report.CommentLines)
+	})
+
+	t.Run("hgignore", func(t *testing.T) {
+		data := []byte("# comment\nsyntax: glob\n*.orig\n")
+		report, err := DialectHgignore.Parse("", data, true)
+		require.NoError(t, err)
+		want, err := DialectHgignore.CompileAll("", data)
+		require.NoError(t, err)
+		assert.Equal(t, want, report.Rules)
+		assert.Nil(t, report.RuleLines)
+		assert.Equal(t, []int{1}, report.CommentLines)
+		assert.Equal(t, []int{4}, report.BlankLines)
+	})
+
+	t.Run("error with line", func(t *testing.T) {
+		_, err := DialectBraces.Parse("", []byte("a\n{a,b}["), false)
+		assert.ErrorContains(t, err, "line 2: ")
+	})
+}
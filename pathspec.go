@@ -0,0 +1,308 @@
+package nogo
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Pathspec is a path argument of git commands, e.g. "src/*.go" or ":!vendor".
+// Use ParsePathspec to parse the magic of a pathspec.
+//
+// Without magic, a pathspec matches the path itself and everything inside
+// of it. Wildcards are matched like fnmatch, so "*" matches '/', too:
+// "src/*.go" matches "src/a/b.go".
+type Pathspec struct {
+	// Pattern is the pathspec without the magic.
+	Pattern string
+
+	// Exclude is set by ":(exclude)", ":!" or ":^".
+	// The matched paths are removed from the selected paths.
+	Exclude bool
+
+	// Top is set by ":(top)" or ":/". The Pattern is relative to the root
+	// instead of the current directory.
+	Top bool
+
+	// Icase is set by ":(icase)". The Pattern matches case-insensitively.
+	Icase bool
+
+	// Glob is set by ":(glob)". The wildcards are matched like in gitignore
+	// files: "*" doesn't match '/' and "**" matches any number of folders.
+	Glob bool
+
+	// Literal is set by ":(literal)". The Pattern doesn't contain wildcards.
+	Literal bool
+}
+
+// ParsePathspec parses the magic of a pathspec like git does it:
+// the long form ":(top,icase)pattern" and the short form ":/!pattern"
+// for top and exclude. The magic "attr" is not supported.
+// Unsupported magic results in a *PatternError.
+func ParsePathspec(spec string) (Pathspec, error) {
+	if !strings.HasPrefix(spec, ":") {
+		return Pathspec{Pattern: spec}, nil
+	}
+
+	var p Pathspec
+	rest := spec[1:]
+	if strings.HasPrefix(rest, "(") {
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return Pathspec{}, &PatternError{Pattern: spec, Cause: errors.New("missing ')' at the end of the magic")}
+		}
+
+		for _, magic := range strings.Split(rest[1:end], ",") {
+			switch magic {
+			case "top":
+				p.Top = true
+			case "exclude":
+				p.Exclude = true
+			case "icase":
+				p.Icase = true
+			case "glob":
+				p.Glob = true
+			case "literal":
+				p.Literal = true
+			case "":
+			default:
+				return Pathspec{}, &PatternError{Pattern: spec, Cause: fmt.Errorf("unsupported magic %q", magic)}
+			}
+		}
+		p.Pattern = rest[end+1:]
+	} else {
+		i := 0
+	short:
+		for ; i < len(rest); i++ {
+			switch rest[i] {
+			case '/':
+				p.Top = true
+			case '!', '^':
+				p.Exclude = true
+			case ':':
+				// An optional ':' ends the magic.
+				i++
+				break short
+			default:
+				break short
+			}
+		}
+		p.Pattern = rest[i:]
+	}
+
+	if p.Glob && p.Literal {
+		return Pathspec{}, &PatternError{Pattern: spec, Cause: errors.New("glob and literal magic are incompatible")}
+	}
+	return p, nil
+}
+
+// Rules compiles the pathspec into rules for an allow list. The prefix is the
+// current directory relative to the root, which is used for all pathspecs
+// without Top. The rules always match the whole path relative to the root,
+// so their Prefix is "".
+//
+// If Exclude is set, the rules are negated and an additional rule matches
+// everything inside of the matched paths, so that they are excluded even if
+// another pathspec matches them.
+func (p Pathspec) Rules(prefix string) ([]Rule, error) {
+	rule, err := p.rule(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []Rule{rule}
+	if p.Exclude {
+		inside := Rule{Negate: true}
+		for _, reg := range rule.Regexp {
+			expr := strings.TrimSuffix(strings.TrimPrefix(reg.String(), "^"), "$")
+			inside.Regexp = append(inside.Regexp, regexp.MustCompile("^(?:"+expr+")/.*$"))
+		}
+		rules = append(rules, inside)
+	}
+
+	if p.Icase {
+		for i, rule := range rules {
+			regexps := make([]*regexp.Regexp, len(rule.Regexp))
+			for ri, reg := range rule.Regexp {
+				regexps[ri] = regexp.MustCompile("(?i)" + reg.String())
+			}
+			// The pattern would match case-sensitively.
+			rules[i].Regexp = regexps
+			rules[i].Pattern = ""
+		}
+	}
+	return rules, nil
+}
+
+// rule compiles the pathspec into a single rule which matches the path itself.
+func (p Pathspec) rule(prefix string) (Rule, error) {
+	if p.Top {
+		prefix = ""
+	}
+
+	pattern := p.Pattern
+	onlyFolder := strings.HasSuffix(pattern, "/")
+	full := path.Join(strings.Trim(prefix, "/"), pattern)
+	if full == ".." || strings.HasPrefix(full, "../") || strings.HasPrefix(full, "/") {
+		return Rule{}, &PatternError{Pattern: pattern, Cause: errors.New("outside of the root")}
+	}
+	if full == "." {
+		full = ""
+		onlyFolder = false
+	}
+
+	var rule Rule
+	switch {
+	case full == "":
+		// Everything is matched.
+		rule = Rule{Regexp: []*regexp.Regexp{regexp.MustCompile("^.*$")}}
+	case p.Glob:
+		line := full
+		if !strings.HasPrefix(line, "**/") {
+			line = "/" + line
+		}
+		if onlyFolder {
+			line += "/"
+		}
+
+		_, compiled, err := Compile("", patternLine(line, false))
+		if err != nil {
+			return Rule{}, err
+		}
+		rule = compiled
+	case !p.Literal && strings.ContainsAny(full, `*?[\`):
+		reg, err := regexp.Compile(ByteRunes("^" + fnmatchRegexp(full) + "$"))
+		if err != nil {
+			return Rule{}, &PatternError{Pattern: pattern, Cause: err}
+		}
+		rule = Rule{Regexp: []*regexp.Regexp{reg}}
+	case !p.Icase && !strings.Contains(full, `\`):
+		// Literal rules keep their pattern, so they can be indexed.
+		line := "/" + escapeGlob(full)
+		if onlyFolder {
+			line += "/"
+		}
+
+		_, compiled, err := Compile("", patternLine(line, false))
+		if err != nil {
+			return Rule{}, err
+		}
+		rule = compiled
+	default:
+		rule = Rule{Regexp: []*regexp.Regexp{regexp.MustCompile(ByteRunes("^" + regexp.QuoteMeta(full) + "$"))}}
+	}
+
+	rule.Negate = p.Exclude
+	rule.OnlyFolder = onlyFolder
+	return rule, nil
+}
+
+// escapeGlob escapes all wildcards of a literal path.
+func escapeGlob(literal string) string {
+	var b strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if strings.IndexByte("*?[]", literal[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(literal[i])
+	}
+	return b.String()
+}
+
+// fnmatchRegexp converts a pattern to a regexp which matches like fnmatch
+// without FNM_PATHNAME, so the wildcards match '/', too.
+func fnmatchRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			} else {
+				b.WriteString(`\\`)
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if i+1 < len(pattern) && (pattern[i+1] == '!' || pattern[i+1] == '^') {
+				end = strings.IndexByte(pattern[i+2:], ']')
+				if end >= 0 {
+					end++
+				}
+			}
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := pattern[i+1 : i+1+end]
+			if class != "" && (class[0] == '!' || class[0] == '^') {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// CompilePathspecs compiles the pathspecs into rules for an allow list
+// (see NewAllowList), so that the paths selected by git are included.
+// The prefix is the current directory relative to the root.
+//
+// Like in git, excluded paths are never selected, independent of the order
+// of the pathspecs. If there are only excluding pathspecs, everything inside
+// of the current directory is selected, except the excluded paths.
+func CompilePathspecs(prefix string, specs ...string) ([]Rule, error) {
+	var include, exclude []Rule
+	for _, spec := range specs {
+		p, err := ParsePathspec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		rules, err := p.Rules(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if p.Exclude {
+			exclude = append(exclude, rules...)
+		} else {
+			include = append(include, rules...)
+		}
+	}
+
+	if len(include) == 0 {
+		rules, err := Pathspec{}.Rules(prefix)
+		if err != nil {
+			return nil, err
+		}
+		include = rules
+	}
+	return append(include, exclude...), nil
+}
+
+// NewPathspecs creates an allow list (see NewAllowList) which includes all
+// paths selected by the pathspecs, so Match returns false for them:
+//
+//	n, err := nogo.NewPathspecs("src", []string{"*.go", ":!vendor", ":/docs"})
+//	// ...
+//	err = n.WalkDir(fsys, ".", fn)
+func NewPathspecs(prefix string, specs []string, opts ...Option) (*NoGo, error) {
+	rules, err := CompilePathspecs(prefix, specs...)
+	if err != nil {
+		return nil, err
+	}
+
+	n := NewAllowList(opts...)
+	n.AddRules(rules...)
+	return n, nil
+}
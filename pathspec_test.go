@@ -0,0 +1,100 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathspec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Pathspec
+		wantErr bool
+	}{
+		{spec: "src/*.go", want: Pathspec{Pattern: "src/*.go"}},
+		{spec: ":!vendor", want: Pathspec{Pattern: "vendor", Exclude: true}},
+		{spec: ":^vendor", want: Pathspec{Pattern: "vendor", Exclude: true}},
+		{spec: ":/docs", want: Pathspec{Pattern: "docs", Top: true}},
+		{spec: ":/!:docs", want: Pathspec{Pattern: "docs", Top: true, Exclude: true}},
+		{spec: "::a", want: Pathspec{Pattern: "a"}},
+		{spec: ":/", want: Pathspec{Top: true}},
+		{spec: ":(top,icase)README", want: Pathspec{Pattern: "README", Top: true, Icase: true}},
+		{spec: ":(exclude,glob)**/*.pb.go", want: Pathspec{Pattern: "**/*.pb.go", Exclude: true, Glob: true}},
+		{spec: ":(literal)a*b", want: Pathspec{Pattern: "a*b", Literal: true}},
+		{spec: ":()a", want: Pathspec{Pattern: "a"}},
+		{spec: ":(attr:binary)a", wantErr: true},
+		{spec: ":(top", wantErr: true},
+		{spec: ":(glob,literal)a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParsePathspec(tt.spec)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPattern)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewPathspecs(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		specs  []string
+		path   string
+		isDir  bool
+		want   bool
+	}{
+		{name: "literal", specs: []string{"src"}, path: "src/main.go", want: true},
+		{name: "literal other", specs: []string{"src"}, path: "srcs/main.go", want: false},
+		{name: "literal folder", specs: []string{"src/"}, path: "src", want: false},
+		{name: "literal folder dir", specs: []string{"src/"}, path: "src", isDir: true, want: true},
+		{name: "wildcard matches slash", specs: []string{"src/*.go"}, path: "src/a/b.go", want: true},
+		{name: "wildcard other", specs: []string{"src/*.go"}, path: "lib/b.go", want: false},
+		{name: "range", specs: []string{"[!a]*.go"}, path: "b/c.go", want: true},
+		{name: "range negated", specs: []string{"[!a]*.go"}, path: "a.go", want: false},
+		{name: "glob star", specs: []string{":(glob)src/*.go"}, path: "src/a/b.go", want: false},
+		{name: "glob star direct", specs: []string{":(glob)src/*.go"}, path: "src/b.go", want: true},
+		{name: "glob double star", specs: []string{":(glob)**/*.go"}, path: "src/a/b.go", want: true},
+		{name: "literal magic", specs: []string{":(literal)a*b"}, path: "a*b", want: true},
+		{name: "literal magic no wildcard", specs: []string{":(literal)a*b"}, path: "axb", want: false},
+		{name: "icase", specs: []string{":(icase)readme.md"}, path: "README.md", want: true},
+		{name: "icase glob", specs: []string{":(icase,glob)DOCS/*.md"}, path: "docs/a.md", want: true},
+		{name: "prefix", prefix: "src", specs: []string{"*.go"}, path: "src/a/b.go", want: true},
+		{name: "prefix outside", prefix: "src", specs: []string{"*.go"}, path: "lib/b.go", want: false},
+		{name: "prefix parent", prefix: "src/app", specs: []string{"../lib"}, path: "src/lib/a.go", want: true},
+		{name: "top", prefix: "src", specs: []string{":/lib"}, path: "lib/a.go", want: true},
+		{name: "exclude", specs: []string{"*.go", ":!vendor"}, path: "vendor/a.go", want: false},
+		{name: "exclude first", specs: []string{":!vendor", "*.go"}, path: "vendor/a.go", want: false},
+		{name: "exclude other", specs: []string{"*.go", ":!vendor"}, path: "main.go", want: true},
+		{name: "exclude folder", specs: []string{":!vendor/"}, path: "vendor/a/b.go", want: false},
+		{name: "exclude icase", specs: []string{":(exclude,icase)VENDOR"}, path: "vendor/a.go", want: false},
+		{name: "only exclude", specs: []string{":!vendor"}, path: "main.go", want: true},
+		{name: "only exclude prefix", prefix: "src", specs: []string{":!vendor"}, path: "main.go", want: false},
+		{name: "only exclude prefix inside", prefix: "src", specs: []string{":!vendor"}, path: "src/main.go", want: true},
+		{name: "dot", prefix: "src", specs: []string{"."}, path: "src/a/b.go", want: true},
+		{name: "dot outside", prefix: "src", specs: []string{"."}, path: "lib/b.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewPathspecs(tt.prefix, tt.specs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, !n.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestNewPathspecs_Errors(t *testing.T) {
+	_, err := NewPathspecs("", []string{"../a"})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+
+	_, err = NewPathspecs("", []string{":(attr:x)a"})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
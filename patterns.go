@@ -0,0 +1,118 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// Anchoring controls where a pattern added by AddPatterns may match.
+type Anchoring int
+
+const (
+	// AnchorDefault uses the gitignore semantics: a pattern is relative to the
+	// prefix if it contains a '/' at the beginning or in the middle.
+	// Otherwise it matches at any level below the prefix.
+	AnchorDefault Anchoring = iota
+
+	// AnchorPrefix makes every pattern relative to the prefix, as if it starts with a '/'.
+	// Patterns starting with "**/" still match at any level.
+	// This is how e.g. the exclude patterns of tar with --anchored behave.
+	AnchorPrefix
+
+	// AnchorAnywhere lets every pattern match at any level below the prefix,
+	// as if it starts with "**/".
+	AnchorAnywhere
+)
+
+// RuleOption configures how AddPatterns compiles the patterns.
+type RuleOption interface {
+	apply(c *ruleConfig)
+}
+
+type ruleOptionFunc func(c *ruleConfig)
+
+func (f ruleOptionFunc) apply(c *ruleConfig) {
+	f(c)
+}
+
+type ruleConfig struct {
+	anchoring Anchoring
+}
+
+// WithAnchoring sets the anchoring of the patterns independently of the
+// slashes inside of them. The default is AnchorDefault.
+func WithAnchoring(anchoring Anchoring) RuleOption {
+	return ruleOptionFunc(func(c *ruleConfig) {
+		c.anchoring = anchoring
+	})
+}
+
+// AddPatterns compiles the patterns and adds them as one group, the same way
+// as AddFile does with the lines of an ignore file.
+// The prefix is the folder the patterns are relative to, "" is the root.
+//
+// This is useful to mix patterns from other sources, such as exclude flags
+// of a command line tool, with the rules of the ignore files:
+//
+//	err := n.AddPatterns("", excludes, nogo.WithAnchoring(nogo.AnchorPrefix))
+//
+// If any pattern is invalid, no rules are added at all.
+func (n *NoGo) AddPatterns(prefix string, patterns []string, opts ...RuleOption) error {
+	var config ruleConfig
+	for _, opt := range opts {
+		opt.apply(&config)
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	lines := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		lines[i] = anchorPattern(pattern, config.anchoring)
+	}
+
+	rules, err := n.dialect.compileLines(prefix, lines)
+	if err != nil {
+		return err
+	}
+
+	n.addGroup(group{
+		prefix: prefix,
+		rules:  rules,
+	})
+	n.changed()
+	return nil
+}
+
+// anchorPattern rewrites the pattern so that Compile applies the anchoring.
+func anchorPattern(pattern string, anchoring Anchoring) string {
+	if anchoring == AnchorDefault {
+		return pattern
+	}
+
+	if _, _, skip := cleanPattern(pattern); skip {
+		return pattern
+	}
+
+	var negate string
+	if pattern[0] == '!' {
+		negate = "!"
+		pattern = pattern[1:]
+	}
+
+	// The escaped '#' is only needed at the start of a line.
+	if strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasPrefix(pattern, "**/") {
+		return negate + pattern
+	}
+
+	switch anchoring {
+	case AnchorPrefix:
+		if !strings.HasPrefix(pattern, "/") {
+			pattern = "/" + pattern
+		}
+	case AnchorAnywhere:
+		pattern = "**/" + strings.TrimPrefix(pattern, "/")
+	}
+	return negate + pattern
+}
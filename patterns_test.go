@@ -0,0 +1,120 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorPattern(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		anchoring Anchoring
+		want      string
+	}{
+		{pattern: "foo", anchoring: AnchorDefault, want: "foo"},
+		{pattern: "foo", anchoring: AnchorPrefix, want: "/foo"},
+		{pattern: "/foo", anchoring: AnchorPrefix, want: "/foo"},
+		{pattern: "a/b", anchoring: AnchorPrefix, want: "/a/b"},
+		{pattern: "!foo", anchoring: AnchorPrefix, want: "!/foo"},
+		{pattern: `\#foo`, anchoring: AnchorPrefix, want: "/#foo"},
+		{pattern: "**/foo", anchoring: AnchorPrefix, want: "**/foo"},
+		{pattern: "foo", anchoring: AnchorAnywhere, want: "**/foo"},
+		{pattern: "/a/b/", anchoring: AnchorAnywhere, want: "**/a/b/"},
+		{pattern: "!a/b", anchoring: AnchorAnywhere, want: "!**/a/b"},
+		{pattern: "**/a/b", anchoring: AnchorAnywhere, want: "**/a/b"},
+		{pattern: "# comment", anchoring: AnchorPrefix, want: "# comment"},
+		{pattern: "", anchoring: AnchorAnywhere, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, anchorPattern(tt.pattern, tt.anchoring))
+		})
+	}
+}
+
+func TestNoGo_AddPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		patterns []string
+		opts     []RuleOption
+		want     map[string]bool
+	}{
+		{
+			name:     "default",
+			patterns: []string{"build", "docs/tmp"},
+			want: map[string]bool{
+				"build":         true,
+				"src/build":     true,
+				"docs/tmp":      true,
+				"src/docs/tmp":  false,
+				"src/build.txt": false,
+			},
+		},
+		{
+			name:     "anchored",
+			patterns: []string{"build", "!build/keep", "*.log"},
+			opts:     []RuleOption{WithAnchoring(AnchorPrefix)},
+			want: map[string]bool{
+				"build":         true,
+				"build/out":     true,
+				"build/keep":    true, // The parent is excluded.
+				"src/build":     false,
+				"a.log":         true,
+				"src/a.log":     false,
+				"src/build.txt": false,
+			},
+		},
+		{
+			name:     "anchored with prefix",
+			prefix:   "sub/",
+			patterns: []string{"build"},
+			opts:     []RuleOption{WithAnchoring(AnchorPrefix)},
+			want: map[string]bool{
+				"build":         false,
+				"sub/build":     true,
+				"sub/src/build": false,
+			},
+		},
+		{
+			name:     "anywhere",
+			patterns: []string{"/docs/tmp", "a/b"},
+			opts:     []RuleOption{WithAnchoring(AnchorAnywhere)},
+			want: map[string]bool{
+				"docs/tmp":     true,
+				"src/docs/tmp": true,
+				"docs":         false,
+				"x/y/a/b":      true,
+				"x/y/a/c":      false,
+			},
+		},
+		{
+			name:     "comments and empty patterns",
+			patterns: []string{"", "# comment", "build"},
+			opts:     []RuleOption{WithAnchoring(AnchorPrefix)},
+			want: map[string]bool{
+				"build":     true,
+				"# comment": false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+				n := New(WithBackend(backend))
+				require.NoError(t, n.AddPatterns(tt.prefix, tt.patterns, tt.opts...))
+				for path, want := range tt.want {
+					assert.Equal(t, want, n.Match(path, true), "%v: %s", backend, path)
+				}
+			}
+		})
+	}
+}
+
+func TestNoGo_AddPatterns_Invalid(t *testing.T) {
+	n := New()
+	assert.Error(t, n.AddPatterns("", []string{"build", "a[b"}))
+	assert.Empty(t, n.groups)
+}
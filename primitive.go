@@ -0,0 +1,249 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// PrimitiveKind defines what a Primitive matches.
+type PrimitiveKind int
+
+const (
+	// PrimitiveLiteral matches exactly the Value of the Primitive.
+	PrimitiveLiteral PrimitiveKind = iota
+
+	// PrimitiveSeparator matches a single '/'.
+	PrimitiveSeparator
+
+	// PrimitiveStar matches any amount of characters except '/'.
+	PrimitiveStar
+
+	// PrimitiveDoubleStar matches zero or more complete directories.
+	// It is always a whole path segment.
+	PrimitiveDoubleStar
+
+	// PrimitiveQuestionMark matches any single character except '/'.
+	PrimitiveQuestionMark
+
+	// PrimitiveRange matches any single character (except '/') of the
+	// range in Value. The Value uses the fnmatch syntax without the
+	// brackets and without the negating '!', e.g. "a-zA-Z" or "[:digit:]_".
+	// If Negated is set, it matches any character which is not in the range.
+	PrimitiveRange
+)
+
+func (k PrimitiveKind) String() string {
+	switch k {
+	case PrimitiveLiteral:
+		return "Literal"
+	case PrimitiveSeparator:
+		return "Separator"
+	case PrimitiveStar:
+		return "Star"
+	case PrimitiveDoubleStar:
+		return "DoubleStar"
+	case PrimitiveQuestionMark:
+		return "QuestionMark"
+	case PrimitiveRange:
+		return "Range"
+	}
+	return "Unknown"
+}
+
+// Primitive is a single building block of a compiled pattern.
+// A list of Primitives describes the same paths as the Regexp of a Rule,
+// but in a form which can be evaluated without regexp.
+type Primitive struct {
+	Kind PrimitiveKind
+
+	// Value is the text of a PrimitiveLiteral or the range of a PrimitiveRange.
+	Value string
+
+	// Negated is only used by PrimitiveRange.
+	Negated bool
+}
+
+// Matchers returns the ordered primitives which together match the same
+// paths as the rule. The primitives always describe the full path,
+// including the Prefix of the rule.
+// Negate and OnlyFolder are not part of the primitives,
+// they have to be checked separately, exactly as with the Regexp.
+//
+// It returns nil if the rule has no Pattern (e.g. if it was built by hand
+// only from regexps).
+func (r Rule) Matchers() []Primitive {
+	pattern, _, skip := cleanPattern(r.Pattern)
+	if skip {
+		return nil
+	}
+
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var primitives []Primitive
+	if r.Prefix != "" {
+		primitives = append(primitives,
+			Primitive{Kind: PrimitiveLiteral, Value: strings.TrimSuffix(r.Prefix, "/")},
+			Primitive{Kind: PrimitiveSeparator},
+		)
+	}
+
+	// Patterns without a '/' may match at any level below the prefix.
+	if !anchored {
+		primitives = append(primitives,
+			Primitive{Kind: PrimitiveDoubleStar},
+			Primitive{Kind: PrimitiveSeparator},
+		)
+	}
+
+	return append(primitives, parsePrimitives(pattern)...)
+}
+
+// parsePrimitives converts a cleaned glob (without leading or trailing '/')
+// into primitives.
+func parsePrimitives(glob string) []Primitive {
+	var primitives []Primitive
+	for i, segment := range splitGlob(glob) {
+		if i > 0 {
+			primitives = append(primitives, Primitive{Kind: PrimitiveSeparator})
+		}
+
+		// "**" only has its special meaning as a whole segment.
+		// Like git, more stars are the same as two.
+		if len(segment) >= 2 && strings.Trim(segment, "*") == "" {
+			primitives = append(primitives, Primitive{Kind: PrimitiveDoubleStar})
+			continue
+		}
+
+		primitives = append(primitives, parseSegment(segment)...)
+	}
+	return primitives
+}
+
+// splitGlob splits the glob at each '/' which is not inside of a range.
+// Like git, an escaped '/' separates segments, too.
+func splitGlob(glob string) []string {
+	var segments []string
+	start := 0
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				segments = append(segments, glob[start:i])
+				start = i + 2
+			}
+			i++
+		case '[':
+			if end := rangeEnd(glob, i+1); end >= 0 {
+				i = end
+			}
+		case '/':
+			segments = append(segments, glob[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, glob[start:])
+}
+
+// parseSegment converts a single path segment of a glob into primitives.
+func parseSegment(segment string) []Primitive {
+	var primitives []Primitive
+	var literal strings.Builder
+
+	add := func(p Primitive) {
+		if literal.Len() > 0 {
+			primitives = append(primitives, Primitive{Kind: PrimitiveLiteral, Value: literal.String()})
+			literal.Reset()
+		}
+
+		// Consecutive stars are the same as a single star.
+		if p.Kind == PrimitiveStar && len(primitives) > 0 && primitives[len(primitives)-1].Kind == PrimitiveStar {
+			return
+		}
+		primitives = append(primitives, p)
+	}
+
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		switch c {
+		case '\\':
+			// Like git, a backslash escapes any character.
+			if i+1 == len(segment) {
+				// A trailing backslash never matches, the same as an empty range.
+				add(Primitive{Kind: PrimitiveRange})
+				continue
+			}
+			i++
+			literal.WriteByte(segment[i])
+		case '*':
+			add(Primitive{Kind: PrimitiveStar})
+		case '?':
+			add(Primitive{Kind: PrimitiveQuestionMark})
+		case '[':
+			end := rangeEnd(segment, i+1)
+			if end < 0 {
+				// An unclosed range is not valid, just treat it literally.
+				literal.WriteByte(c)
+				continue
+			}
+
+			// Like git, "[^" negates the range the same way as "[!".
+			p := Primitive{Kind: PrimitiveRange, Value: segment[i+1 : end]}
+			if strings.HasPrefix(p.Value, "!") || strings.HasPrefix(p.Value, "^") {
+				p.Negated = true
+				p.Value = p.Value[1:]
+			}
+			add(p)
+			i = end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	if literal.Len() > 0 {
+		primitives = append(primitives, Primitive{Kind: PrimitiveLiteral, Value: literal.String()})
+	}
+	return primitives
+}
+
+// rangeEnd returns the index of the first not escaped ']' starting at start,
+// which is not part of a character class such as "[:digit:]".
+// Like git, a ']' directly at the start (or after the negating '!' or '^')
+// is part of the range. It returns -1 if there is none.
+func rangeEnd(s string, start int) int {
+	i := start
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		i++
+	}
+	if i < len(s) && s[i] == ']' {
+		i++
+	}
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			if end := classEnd(s, i); end >= 0 {
+				i = end
+			}
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// classEnd returns the index of the closing ']' of the character class
+// (e.g. "[:digit:]") starting at start, or -1 if there is none.
+// Like git, the class ends at the first ']' and only is a class if that
+// ']' follows a ':'.
+func classEnd(s string, start int) int {
+	if !strings.HasPrefix(s[start:], "[:") {
+		return -1
+	}
+	end := strings.IndexByte(s[start+2:], ']')
+	if end <= 0 || s[start+2+end-1] != ':' {
+		return -1
+	}
+	return start + 2 + end
+}
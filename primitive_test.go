@@ -0,0 +1,112 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Matchers(t *testing.T) {
+	var (
+		sep        = Primitive{Kind: PrimitiveSeparator}
+		star       = Primitive{Kind: PrimitiveStar}
+		doubleStar = Primitive{Kind: PrimitiveDoubleStar}
+		question   = Primitive{Kind: PrimitiveQuestionMark}
+		literal    = func(v string) Primitive { return Primitive{Kind: PrimitiveLiteral, Value: v} }
+	)
+
+	tests := []struct {
+		name    string
+		prefix  string
+		pattern string
+		want    []Primitive
+	}{
+		{
+			name:    "a specific file in the current folder",
+			prefix:  "a/folder",
+			pattern: "/aFile",
+			want:    []Primitive{literal("a/folder"), sep, literal("aFile")},
+		},
+		{
+			name:    "a file anywhere below",
+			prefix:  "a/folder",
+			pattern: "aFile",
+			want:    []Primitive{literal("a/folder"), sep, doubleStar, sep, literal("aFile")},
+		},
+		{
+			name:    "a file anywhere below with empty prefix",
+			pattern: "aFile",
+			want:    []Primitive{doubleStar, sep, literal("aFile")},
+		},
+		{
+			name:    "only folder and negated",
+			prefix:  "a/folder",
+			pattern: "!sub/aFolder/",
+			want:    []Primitive{literal("a/folder"), sep, literal("sub"), sep, literal("aFolder")},
+		},
+		{
+			name:    "stars and question marks",
+			pattern: "/a*b**c?d",
+			want:    []Primitive{literal("a"), star, literal("b"), star, literal("c"), question, literal("d")},
+		},
+		{
+			name:    "double stars as segments",
+			pattern: "**/a/**/b/**",
+			want:    []Primitive{doubleStar, sep, literal("a"), sep, doubleStar, sep, literal("b"), sep, doubleStar},
+		},
+		{
+			name:    "ranges",
+			pattern: "/file[a-z]with[!0-9]ranges",
+			want: []Primitive{
+				literal("file"),
+				{Kind: PrimitiveRange, Value: "a-z"},
+				literal("with"),
+				{Kind: PrimitiveRange, Value: "0-9", Negated: true},
+				literal("ranges"),
+			},
+		},
+		{
+			name:    "character classes in ranges",
+			pattern: "/[[:digit:]]x[^[:alpha:]_]",
+			want: []Primitive{
+				{Kind: PrimitiveRange, Value: "[:digit:]"},
+				literal("x"),
+				{Kind: PrimitiveRange, Value: "[:alpha:]_", Negated: true},
+			},
+		},
+		{
+			name:    "escaped special chars",
+			pattern: `/a\*\?\[b\]\**\c`,
+			want:    []Primitive{literal(`a*?[b]*`), star, literal("c")},
+		},
+		{
+			name:    "escaped hash and trailing spaces",
+			pattern: `\#aFile  `,
+			want:    []Primitive{doubleStar, sep, literal("#aFile")},
+		},
+		{
+			name:    "unclosed range",
+			pattern: "/[abc",
+			want:    []Primitive{literal("[abc")},
+		},
+		{
+			name:    "comment",
+			pattern: "# a comment",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.name, func(t *testing.T) {
+			rule := Rule{Prefix: tt.prefix, Pattern: tt.pattern}
+			assert.Equal(t, tt.want, rule.Matchers())
+		})
+	}
+
+	t.Run("compiled rule", func(t *testing.T) {
+		skip, rule, err := Compile("a/folder", "*.go")
+		require.NoError(t, err)
+		require.False(t, skip)
+		assert.Equal(t, []Primitive{literal("a/folder"), sep, doubleStar, sep, star, literal(".go")}, rule.Matchers())
+	})
+}
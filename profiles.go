@@ -0,0 +1,96 @@
+package nogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProfileSet contains several named sets of rules which are parsed from a
+// single file with sections like in EditorConfig files:
+//
+//	# Rules before the first section belong to all profiles.
+//	.git
+//
+//	[linting]
+//	vendor/
+//	*.pb.go
+//
+//	[packaging]
+//	*_test.go
+//
+// Each profile is a NoGo, so it can be used as Matcher or to walk a file tree.
+type ProfileSet struct {
+	names    []string
+	profiles map[string]*NoGo
+}
+
+// ParseProfiles parses the sections of data into profiles. The lines of each
+// section are compiled the same way as by AddPatterns, using the prefix and
+// the options (e.g. WithDialect) for each profile.
+//
+// A line is a section header if it starts with "[" and ends with "]" after
+// removing surrounding white space. So a pattern like "[abc]" has to be
+// escaped as "\[abc]". Sections with the same name are merged.
+func ParseProfiles(prefix string, data []byte, opts ...Option) (*ProfileSet, error) {
+	set := &ProfileSet{
+		profiles: make(map[string]*NoGo),
+	}
+
+	var shared []string
+	sections := make(map[string][]string)
+	current := ""
+	for i, line := range splitLines(data) {
+		if name, ok := sectionName(line); ok {
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", i+1)
+			}
+			if _, ok := sections[name]; !ok {
+				set.names = append(set.names, name)
+				sections[name] = nil
+			}
+			current = name
+			continue
+		}
+
+		if current == "" {
+			shared = append(shared, line)
+		} else {
+			sections[current] = append(sections[current], line)
+		}
+	}
+
+	for _, name := range set.names {
+		lines := make([]string, 0, len(shared)+len(sections[name]))
+		lines = append(lines, shared...)
+		lines = append(lines, sections[name]...)
+
+		n := New(opts...)
+		if err := n.AddPatterns(prefix, lines); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		set.profiles[name] = n
+	}
+
+	return set, nil
+}
+
+// sectionName returns the name of a section header like "[name]".
+func sectionName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '[' || line[len(line)-1] != ']' {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// Names returns the names of all profiles in the order of their first section.
+func (p *ProfileSet) Names() []string {
+	return append([]string(nil), p.names...)
+}
+
+// Profile returns the rules of the profile. It returns false if there is no
+// section with the name.
+func (p *ProfileSet) Profile(name string) (*NoGo, bool) {
+	n, ok := p.profiles[name]
+	return n, ok
+}
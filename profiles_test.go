@@ -0,0 +1,86 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesTestData = `# shared
+.git
+
+[linting]
+vendor/
+*.pb.go
+
+ [ packaging ]
+*_test.go
+\[docs]
+
+[linting]
+!keep.pb.go
+`
+
+func TestParseProfiles(t *testing.T) {
+	set, err := ParseProfiles("", []byte(profilesTestData))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linting", "packaging"}, set.Names())
+
+	_, ok := set.Profile("missing")
+	assert.False(t, ok)
+
+	tests := []struct {
+		profile string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{profile: "linting", path: ".git", isDir: true, want: true},
+		{profile: "linting", path: "vendor/lib.go", want: true},
+		{profile: "linting", path: "api/api.pb.go", want: true},
+		{profile: "linting", path: "keep.pb.go", want: false},
+		{profile: "linting", path: "main_test.go", want: false},
+		{profile: "packaging", path: ".git", isDir: true, want: true},
+		{profile: "packaging", path: "main_test.go", want: true},
+		{profile: "packaging", path: "[docs]", want: true},
+		{profile: "packaging", path: "vendor/lib.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile+"/"+tt.path, func(t *testing.T) {
+			n, ok := set.Profile(tt.profile)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestParseProfiles_Options(t *testing.T) {
+	set, err := ParseProfiles("sub", []byte("[a]\n*.{js,ts}"), WithDialect(DialectBraces))
+	require.NoError(t, err)
+
+	n, ok := set.Profile("a")
+	require.True(t, ok)
+	assert.True(t, n.Match("sub/main.ts", false))
+	assert.False(t, n.Match("main.ts", false))
+}
+
+func TestParseProfiles_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "empty name", data: "[a]\n*.go\n[ ]", err: "line 3: empty profile name"},
+		{name: "invalid pattern", data: "[a]\n*.[go", err: `profile "a": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfiles("", []byte(tt.data), WithDialect(DialectBraces))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
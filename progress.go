@@ -0,0 +1,60 @@
+package nogo
+
+// ProgressKind is the kind of a ProgressEvent.
+type ProgressKind int
+
+const (
+	// ProgressDir is reported when a directory is entered.
+	ProgressDir ProgressKind = iota
+	// ProgressIgnoreFile is reported when an ignore file was loaded.
+	ProgressIgnoreFile
+	// ProgressSkipped is reported when an ignored file or directory is skipped.
+	ProgressSkipped
+)
+
+func (k ProgressKind) String() string {
+	switch k {
+	case ProgressDir:
+		return "dir"
+	case ProgressIgnoreFile:
+		return "ignore-file"
+	case ProgressSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// ProgressEvent is passed to the ProgressFunc set by WithProgress.
+type ProgressEvent struct {
+	Kind ProgressKind
+	Path string
+	// IsDir is set for skipped directories.
+	IsDir bool
+	// Rules is the amount of rules loaded from an ignore file.
+	Rules int
+	// Err is set if an ignore file could not be loaded.
+	Err error
+}
+
+// ProgressFunc is called for each ProgressEvent.
+type ProgressFunc func(event ProgressEvent)
+
+// WithProgress sets a function which is called while AddFromFS and the walk
+// functions (e.g. NoGo.WalkDir or ForWalkDir) are running, e.g. to render
+// a progress bar or a verbose trace. It gets called for each directory which
+// is entered, each ignore file which was loaded and each skipped path.
+//
+// With WalkDirConcurrent it may be called concurrently.
+func WithProgress(fn ProgressFunc) Option {
+	return optionFunc(func(n *NoGo) {
+		n.progress = fn
+	})
+}
+
+// report passes the event to the ProgressFunc, if there is one.
+func (n *NoGo) report(event ProgressEvent) {
+	if n.progress != nil {
+		n.progress(event)
+	}
+}
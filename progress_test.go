@@ -0,0 +1,53 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProgress(t *testing.T) {
+	want := []ProgressEvent{
+		{Kind: ProgressDir, Path: "."},
+		{Kind: ProgressIgnoreFile, Path: ".gitignore", Rules: 2},
+		{Kind: ProgressSkipped, Path: "build", IsDir: true},
+		{Kind: ProgressSkipped, Path: "debug.log"},
+		{Kind: ProgressDir, Path: "sub"},
+		{Kind: ProgressIgnoreFile, Path: "sub/.gitignore", Rules: 1},
+		{Kind: ProgressDir, Path: "sub/deeper"},
+		{Kind: ProgressSkipped, Path: "sub/deeper/a.log"},
+		{Kind: ProgressSkipped, Path: "sub/deeper/b.log"},
+		{Kind: ProgressSkipped, Path: "sub/secret.txt"},
+	}
+
+	t.Run("AddFromFS", func(t *testing.T) {
+		var got []ProgressEvent
+		n := New(WithProgress(func(event ProgressEvent) {
+			got = append(got, event)
+		}))
+		require.NoError(t, n.AddFromFS(newTestMapFS(walkTestFiles), ".gitignore"))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("WalkDir", func(t *testing.T) {
+		var got []ProgressEvent
+		n := New(WithProgress(func(event ProgressEvent) {
+			got = append(got, event)
+		}))
+		err := n.WalkDir(newTestMapFS(walkTestFiles), ".", func(path string, d fs.DirEntry, err error) error {
+			return err
+		}, WithIgnoreFile(".gitignore"))
+		require.NoError(t, err)
+		// The children of a directory are matched before they are walked.
+		assert.ElementsMatch(t, want, got)
+	})
+}
+
+func TestProgressKind_String(t *testing.T) {
+	assert.Equal(t, "dir", ProgressDir.String())
+	assert.Equal(t, "ignore-file", ProgressIgnoreFile.String())
+	assert.Equal(t, "skipped", ProgressSkipped.String())
+	assert.Equal(t, "unknown", ProgressKind(42).String())
+}
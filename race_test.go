@@ -0,0 +1,7 @@
+//go:build race
+
+package nogo
+
+// raceEnabled is true if the tests run with the race detector, which
+// allocates by itself.
+const raceEnabled = true
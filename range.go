@@ -0,0 +1,140 @@
+package nogo
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// compileRange converts a range primitive to a regexp class which never
+// matches a '/', like git does it. As Go regexps don't support the subtraction
+// of classes, the range is collected byte by byte and the '/' is removed from it.
+func compileRange(p Primitive) (string, error) {
+	var ranges []rune
+	value := p.Value
+	for i := 0; i < len(value); {
+		if end := classEnd(value, i); end >= 0 {
+			class, err := classRanges(value[i : end+1])
+			if err != nil {
+				return "", err
+			}
+			ranges = append(ranges, class...)
+			i = end + 1
+			continue
+		}
+
+		start, size := nextRangeChar(value[i:])
+		i += size
+
+		// A '-' between two characters defines a range.
+		end := start
+		if i+1 < len(value) && value[i] == '-' {
+			end, size = nextRangeChar(value[i+1:])
+			i += 1 + size
+		}
+		if end < start {
+			return "", &syntax.Error{Code: syntax.ErrInvalidCharRange, Expr: value}
+		}
+		ranges = append(ranges, rune(start), rune(end))
+	}
+
+	if p.Negated {
+		// Negated ranges exclude the '/', too.
+		return "[^" + formatClass(normalizeRanges(append(ranges, '/', '/'))) + "]", nil
+	}
+	ranges = removeRune(normalizeRanges(ranges), '/')
+	if len(ranges) == 0 {
+		return `[^\x00-\x{10ffff}]`, nil
+	}
+	return "[" + formatClass(ranges) + "]", nil
+}
+
+// classRanges returns the pairs of ranges of a character class like "[:digit:]".
+func classRanges(class string) ([]rune, error) {
+	parsed, err := syntax.Parse("["+class+"]", syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Op != syntax.OpCharClass {
+		return nil, fmt.Errorf("unsupported character class %q", class)
+	}
+	return parsed.Rune, nil
+}
+
+// normalizeRanges sorts the pairs of ranges and merges overlapping
+// and adjacent ones.
+func normalizeRanges(ranges []rune) []rune {
+	pairs := make([][2]rune, 0, len(ranges)/2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		pairs = append(pairs, [2]rune{ranges[i], ranges[i+1]})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0]
+	})
+
+	result := make([]rune, 0, len(ranges))
+	for _, pair := range pairs {
+		if n := len(result); n > 0 && pair[0] <= result[n-1]+1 {
+			if pair[1] > result[n-1] {
+				result[n-1] = pair[1]
+			}
+			continue
+		}
+		result = append(result, pair[0], pair[1])
+	}
+	return result
+}
+
+// removeRune removes the rune from the sorted pairs of ranges.
+func removeRune(ranges []rune, c rune) []rune {
+	result := make([]rune, 0, len(ranges)+2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if c < lo || c > hi {
+			result = append(result, lo, hi)
+			continue
+		}
+		if lo < c {
+			result = append(result, lo, c-1)
+		}
+		if c < hi {
+			result = append(result, c+1, hi)
+		}
+	}
+	return result
+}
+
+// formatClass formats the pairs of ranges as content of a regexp class.
+func formatClass(ranges []rune) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo == '/' && hi > lo {
+			// Keep the '/' separate, e.g. "[^/0-9]" instead of "[^/-9]".
+			b.WriteByte('/')
+			lo++
+		}
+		b.WriteString(formatClassRune(lo))
+		if hi > lo {
+			if hi > lo+1 {
+				b.WriteByte('-')
+			}
+			b.WriteString(formatClassRune(hi))
+		}
+	}
+	return b.String()
+}
+
+func formatClassRune(c rune) string {
+	switch {
+	case c == '/' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		return string(c)
+	case c < utf8.RuneSelf && (unicode.IsPunct(c) || unicode.IsSymbol(c)):
+		return `\` + string(c)
+	default:
+		return fmt.Sprintf(`\x{%x}`, c)
+	}
+}
@@ -0,0 +1,137 @@
+package nogo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileRanges(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		wantRegexp string
+		matches    []string
+		notMatches []string
+	}{
+		{
+			pattern:    "/file[a-z].txt",
+			wantRegexp: `^file[a-z]\.txt$`,
+			matches:    []string{"filea.txt"},
+			notMatches: []string{"fileA.txt", "file/.txt"},
+		},
+		{
+			pattern:    "/file[!a-z].txt",
+			wantRegexp: `^file[^/a-z]\.txt$`,
+			matches:    []string{"fileA.txt", "file1.txt"},
+			notMatches: []string{"filea.txt", "file/.txt"},
+		},
+		{
+			pattern:    "/file[^a-z].txt",
+			wantRegexp: `^file[^/a-z]\.txt$`,
+			matches:    []string{"fileA.txt", "file^.txt"},
+			notMatches: []string{"filea.txt", "file/.txt"},
+		},
+		{
+			// The range contains the '/'.
+			pattern:    "/file[+-0].txt",
+			wantRegexp: `^file[\+-\.0]\.txt$`,
+			matches:    []string{"file+.txt", "file..txt", "file0.txt"},
+			notMatches: []string{"file/.txt"},
+		},
+		{
+			pattern:    "/file[/].txt",
+			wantRegexp: `^file[^\x00-\x{10ffff}]\.txt$`,
+			notMatches: []string{"file/.txt"},
+		},
+		{
+			pattern:    "/file[*?].txt",
+			wantRegexp: `^file[\*\?]\.txt$`,
+			matches:    []string{"file*.txt", "file?.txt"},
+			notMatches: []string{"filea.txt"},
+		},
+		{
+			pattern:    "/[Dd]esktop.ini",
+			wantRegexp: `^[Dd]esktop\.ini$`,
+			matches:    []string{"desktop.ini", "Desktop.ini"},
+		},
+		{
+			pattern:    "/[[:digit:]x]",
+			wantRegexp: `^[0-9x]$`,
+			matches:    []string{"1", "x"},
+			notMatches: []string{"a"},
+		},
+		{
+			// Stars next to a negated range must not let it match a '/'.
+			pattern:    "a*[!x]c",
+			wantRegexp: `^(.*/)?a[^/]*[^/x]c$`,
+			matches:    []string{"abc", "abbc", "dir/abc"},
+			notMatches: []string{"a/c", "ab/c", "axc"},
+		},
+		{
+			pattern:    "/a/**/[!b]c",
+			wantRegexp: `^a/(.*/)?[^/b]c$`,
+			matches:    []string{"a/ac", "a/x/y/ac"},
+			notMatches: []string{"a/x/bc", "a/x//c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, rule, err := Compile("", tt.pattern)
+			require.NoError(t, err)
+			require.Len(t, rule.Regexp, 1)
+			assert.Equal(t, tt.wantRegexp, rule.Regexp[0].String())
+
+			for _, path := range tt.matches {
+				assert.True(t, rule.MatchPath(path).Found, path)
+			}
+			for _, path := range tt.notMatches {
+				assert.False(t, rule.MatchPath(path).Found, path)
+			}
+		})
+	}
+}
+
+// FuzzCompileRanges cross-checks patterns containing ranges against
+// "git check-ignore". It is skipped if git is not installed.
+func FuzzCompileRanges(f *testing.F) {
+	f.Add("file[a-z].txt", "filea.txt")
+	f.Add("file[!a-z].txt", "fileA.txt")
+	f.Add("a*[!x]c", "a/c")
+	f.Add("a*[!x]c", "ab/bc")
+	f.Add("*[!b]", "a/b")
+	f.Add("[+-0]", "a/b")
+	f.Add("/a/*/[!b]c", "a/x/ac")
+	f.Add("[[:digit:]]*", "1/x")
+	f.Add("x/[a-c]*[!a-c]", "x/bbb")
+
+	repo := newGitRepo(f)
+	f.Fuzz(func(t *testing.T, pattern string, path string) {
+		if !strings.Contains(pattern, "[") || !validRangeFuzzPattern(pattern) || !validGitFuzzPath(path) {
+			t.Skip()
+		}
+
+		rules, err := CompileAll("", []byte(pattern))
+		if err != nil {
+			t.Skip()
+		}
+		got := New(WithRules(rules...)).Match(path, false)
+
+		want, ok := repo.ignored(t, pattern, path, false)
+		if !ok {
+			t.Skip()
+		}
+		assert.Equal(t, want, got, "pattern %q, path %q", pattern, path)
+	})
+}
+
+// validRangeFuzzPattern accepts printable ASCII patterns.
+func validRangeFuzzPattern(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] < ' ' || pattern[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package nogo
+
+import (
+	"io"
+	"strings"
+)
+
+// AddReader reads all rules of an ignore file from r, e.g. from stdin,
+// an HTTP response or an archive. The prefix is the folder of the ignore
+// file relative to the root, like the folder of the path passed to AddFile.
+// Use "" for the root.
+//
+// As there is no file system, the "#!include:" lines of DialectGcloudignore
+// are not resolved and are treated as comments.
+func (n *NoGo) AddReader(prefix string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	rules, err := n.dialect.CompileAll(prefix, data)
+	if err != nil {
+		return err
+	}
+
+	n.addGroup(group{
+		prefix: prefix,
+		rules:  rules,
+	})
+	n.changed()
+	return nil
+}
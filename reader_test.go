@@ -0,0 +1,64 @@
+package nogo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_AddReader(t *testing.T) {
+	n := New()
+	require.NoError(t, n.AddReader("", strings.NewReader("*.log\n/build\n")))
+	require.NoError(t, n.AddReader("/sub/", strings.NewReader("!keep.log\n/out")))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "a.log", want: true},
+		{path: "build", isDir: true, want: true},
+		{path: "sub/keep.log", want: false},
+		{path: "keep.log", want: true},
+		{path: "sub/out", isDir: true, want: true},
+		{path: "out", isDir: true, want: false},
+		{path: "main.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir))
+		})
+	}
+
+	t.Run("same as AddFile", func(t *testing.T) {
+		data := "*.log\n!keep.log\n/build"
+		fromFile := New()
+		require.NoError(t, fromFile.AddFile(fstest.MapFS{"sub/.gitignore": {Data: []byte(data)}}, "sub/.gitignore"))
+		fromReader := New()
+		require.NoError(t, fromReader.AddReader("sub", strings.NewReader(data)))
+		require.Len(t, fromReader.groups, 1)
+		assert.Equal(t, fromFile.groups[0].prefix, fromReader.groups[0].prefix)
+		assert.Equal(t, fromFile.groups[0].rules, fromReader.groups[0].rules)
+		assert.Equal(t, "", fromReader.groups[0].source)
+	})
+
+	t.Run("dialect", func(t *testing.T) {
+		n := New(WithDialect(DialectGcloudignore))
+		require.NoError(t, n.AddReader("", strings.NewReader("*.log\n#!include:.gitignore")))
+		assert.True(t, n.Match("a.log", false))
+		assert.False(t, n.Match(".gitignore", false))
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("broken")
+		n := New()
+		err := n.AddReader("", iotest.ErrReader(readErr))
+		assert.ErrorIs(t, err, readErr)
+		assert.Empty(t, n.groups)
+	})
+}
@@ -0,0 +1,106 @@
+package nogo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rebase returns a new NoGo with all rules moved below the prefix, e.g. if
+// the tree the rules were loaded from is mounted at the prefix of another
+// file system. The rule "/build" of "src/.gitignore" then ignores
+// "mnt/src/build" for the prefix "mnt", the same as if the ignore file
+// was "mnt/src/.gitignore". Paths outside of the prefix are never ignored.
+//
+// All options are kept, except WithFS and WithStatFS, as their paths are not
+// below the prefix. So only the rules which were already loaded are rebased.
+// The cache and the stats of the new NoGo start empty.
+func (n *NoGo) Rebase(prefix string) *NoGo {
+	prefix = n.groupPrefix(prefix)
+
+	groups := make([]group, len(n.groups))
+	for i, g := range n.groups {
+		rules := make([]Rule, len(g.rules))
+		for ri, rule := range g.rules {
+			rules[ri] = rebaseRule(prefix, rule)
+		}
+		groups[i] = group{
+			prefix: rebasePrefix(prefix, g.prefix),
+			rules:  rules,
+			source: g.source,
+			folded: g.folded,
+			tag:    g.tag,
+		}
+	}
+	return n.derive(groups)
+}
+
+// derive creates a new NoGo with the groups and the options of n,
+// except WithFS and WithStatFS.
+func (n *NoGo) derive(groups []group) *NoGo {
+	derived := &NoGo{
+		groups:        groups,
+		backend:       n.backend,
+		stripStreams:  n.stripStreams,
+		precompiled:   n.precompiled,
+		allowList:     n.allowList,
+		validatePaths: n.validatePaths,
+		dialect:       n.dialect,
+		ignoreCase:    n.ignoreCase,
+		windowsPaths:  n.windowsPaths,
+		stats:         n.stats,
+		progress:      n.progress,
+		logger:        n.logger,
+	}
+	derived.copyLoads(n)
+	for tag := range n.disabledTags {
+		if derived.disabledTags == nil {
+			derived.disabledTags = make(map[string]bool)
+		}
+		derived.disabledTags[tag] = true
+	}
+	if n.cache != nil {
+		derived.cache = newMatchCache(n.cache.size)
+	}
+
+	derived.changed()
+	return derived
+}
+
+// rebaseRule moves the rule below the prefix.
+func rebaseRule(prefix string, rule Rule) Rule {
+	if prefix == "" {
+		return rule
+	}
+
+	newPrefix := rebasePrefix(prefix, rule.Prefix)
+	if rule.Pattern != "" {
+		// Only the prefix of the rule changes, so its pattern compiles again.
+		// Otherwise its regexps are rebased below like for rules without a pattern.
+		if skip, rebased, err := Compile(newPrefix, rule.Pattern); err == nil && !skip {
+			return rebased
+		}
+	}
+
+	// Rules without a pattern, e.g. of DialectHgignore, only have their regexps.
+	regexps := make([]*regexp.Regexp, len(rule.Regexp))
+	for i, reg := range rule.Regexp {
+		expr := reg.String()
+		if strings.HasPrefix(expr, "^") {
+			expr = expr[1:]
+		} else {
+			expr = ".*(?:" + expr + ")"
+		}
+		regexps[i] = regexp.MustCompile("^" + regexp.QuoteMeta(ByteRunes(prefix+"/")) + "(?:" + expr + ")")
+	}
+	rule.Regexp = regexps
+	rule.Prefix = newPrefix
+	return rule
+}
+
+// rebasePrefix moves the prefix of a group or rule below the new prefix.
+func rebasePrefix(prefix string, old string) string {
+	if old == "" {
+		return prefix
+	}
+	return joinPrefix(prefix, old)
+}
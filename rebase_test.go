@@ -0,0 +1,75 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_Rebase(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("/build\n*.log\n!keep.log")},
+		"src/.gitignore": {Data: []byte("/gen\nDocs/")},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		prefix string
+		path   string
+		isDir  bool
+		want   bool
+	}{
+		{name: "anchored", prefix: "mnt", path: "mnt/build", isDir: true, want: true},
+		{name: "anchored old path", prefix: "mnt", path: "build", isDir: true, want: false},
+		{name: "anchored deeper", prefix: "mnt", path: "mnt/src/build", isDir: true, want: false},
+		{name: "unanchored", prefix: "mnt", path: "mnt/src/debug.log", want: true},
+		{name: "unanchored outside", prefix: "mnt", path: "debug.log", want: false},
+		{name: "negated", prefix: "mnt", path: "mnt/keep.log", want: false},
+		{name: "sub folder", prefix: "mnt", path: "mnt/src/gen", isDir: true, want: true},
+		{name: "sub folder old path", prefix: "mnt", path: "src/gen", isDir: true, want: false},
+		{name: "only folder", prefix: "mnt", path: "mnt/src/Docs", want: false},
+		{name: "deep prefix", prefix: "/a/b/", path: "a/b/src/gen/x.go", want: true},
+		{name: "empty prefix", prefix: "", path: "build", isDir: true, want: true},
+		{name: "glob backend", opts: []Option{WithBackend(GlobBackend)}, prefix: "mnt", path: "mnt/src/gen", isDir: true, want: true},
+		{name: "ignore case", opts: []Option{WithIgnoreCase()}, prefix: "Mnt", path: "mnt/SRC/docs", isDir: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ForFS(fsys, ".gitignore", tt.opts...)
+			require.NoError(t, err)
+
+			rebased := n.Rebase(tt.prefix)
+			assert.Equal(t, tt.want, rebased.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestNoGo_Rebase_Original(t *testing.T) {
+	n := New(WithCache(10))
+	require.NoError(t, n.AddPatterns("src", []string{"/build"}))
+
+	rebased := n.Rebase("mnt")
+	assert.True(t, rebased.Match("mnt/src/build", true))
+	assert.False(t, rebased.Match("src/build", true))
+	assert.True(t, n.Match("src/build", true))
+
+	require.Len(t, rebased.Groups(), 1)
+	assert.Equal(t, "mnt/src", rebased.Groups()[0].Prefix)
+	assert.Equal(t, "mnt/src", rebased.Groups()[0].Rules[0].Prefix)
+	assert.Equal(t, "src", n.Groups()[0].Rules[0].Prefix)
+}
+
+func TestNoGo_Rebase_Hgignore(t *testing.T) {
+	n := New(WithDialect(DialectHgignore))
+	require.NoError(t, n.AddPatterns("sub", []string{`\.pyc$`, "syntax: glob", "build"}))
+
+	rebased := n.Rebase("mnt")
+	assert.True(t, rebased.Match("mnt/sub/a/b.pyc", false))
+	assert.True(t, rebased.Match("mnt/sub/build", true))
+	assert.False(t, rebased.Match("sub/a/b.pyc", false))
+	assert.False(t, rebased.Match("mnt/a/b.pyc", false))
+}
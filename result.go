@@ -1,16 +1,76 @@
 package nogo
 
+// MatchKind describes why a path is ignored or not, see Result.Kind.
+type MatchKind int
+
+const (
+	// NoMatch means that no rule matched the path or any of its parents,
+	// so it is not ignored.
+	NoMatch MatchKind = iota
+	// DirectMatch means that a rule matched the path itself, so it is ignored.
+	DirectMatch
+	// ParentDirMatch means that a rule matched a parent directory of the path,
+	// so it is ignored together with everything else inside of it.
+	ParentDirMatch
+	// NegatedMatch means that a negated rule (e.g. "!keep.log") matched the path
+	// or one of its parents, so it is not ignored.
+	NegatedMatch
+)
+
+func (k MatchKind) String() string {
+	switch k {
+	case NoMatch:
+		return "no match"
+	case DirectMatch:
+		return "direct match"
+	case ParentDirMatch:
+		return "parent dir match"
+	case NegatedMatch:
+		return "negated match"
+	default:
+		return "unknown"
+	}
+}
+
 type Result struct {
 	Rule
 
 	// Found is true if any matching rule was found.
 	// Do not use it to check if the file is actually to be ignored!
 	// For this use Resolve as it takes into account some special cases.
+	//
+	// Deprecated: Use Kind, which also takes Negate and ParentMatch into account.
 	Found bool
 
 	// ParentMatch saves if the actual rule matched for a parent or not.
 	// In case of a parent match the check for OnlyFolder has to be different.
+	//
+	// Deprecated: Use Kind, which returns ParentDirMatch in this case.
 	ParentMatch bool
+
+	// Tag is the tag of the rule if it was added by AddRulesTagged.
+	Tag string
+}
+
+// Kind returns the outcome of the Result, so the flags don't have to be
+// combined by the caller.
+//
+// It is meant for the Results of the matchers, e.g. NoGo.MatchBecause or
+// NoGo.MatchAll, which only contain rules ending with a '/' if the matched
+// path is a directory. For the Result of Rule.MatchPath use Resolve.
+// For allow lists, the Kind describes the matched rule. Use the returned bool
+// of the matcher to know if the path is ignored.
+func (r Result) Kind() MatchKind {
+	switch {
+	case !r.Found:
+		return NoMatch
+	case r.Negate:
+		return NegatedMatch
+	case r.ParentMatch:
+		return ParentDirMatch
+	default:
+		return DirectMatch
+	}
 }
 
 // Resolve the Result by taking into account OnlyFolder
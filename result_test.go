@@ -182,3 +182,44 @@ func TestResult_Resolve(t *testing.T) {
 		})
 	}
 }
+
+func TestResult_Kind(t *testing.T) {
+	n := New(WithRules(MustCompileAll("", []byte("*.log\n!keep.log\nbuild/\ndoc/\n!doc/"))...))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  MatchKind
+	}{
+		{path: "main.go", want: NoMatch},
+		{path: "debug.log", want: DirectMatch},
+		{path: "keep.log", want: NegatedMatch},
+		{path: "build", isDir: true, want: DirectMatch},
+		{path: "build", want: NoMatch},
+		{path: "build/main.go", want: ParentDirMatch},
+		{path: "build/keep.log", want: ParentDirMatch},
+		{path: "doc/readme.md", want: NegatedMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			match, because := n.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, tt.want, because.Kind())
+			assert.Equal(t, match, tt.want == DirectMatch || tt.want == ParentDirMatch)
+
+			path := tt.path
+			if tt.isDir {
+				path += "/"
+			}
+			_, results := n.MatchAll([]string{path})
+			assert.Equal(t, tt.want, results[0].Kind())
+		})
+	}
+}
+
+func TestMatchKind_String(t *testing.T) {
+	assert.Equal(t, "no match", NoMatch.String())
+	assert.Equal(t, "direct match", DirectMatch.String())
+	assert.Equal(t, "parent dir match", ParentDirMatch.String())
+	assert.Equal(t, "negated match", NegatedMatch.String())
+	assert.Equal(t, "unknown", MatchKind(42).String())
+}
@@ -2,13 +2,30 @@ package nogo
 
 import (
 	"regexp"
+	"regexp/syntax"
 	"strings"
+	"unicode/utf8"
 )
 
 type Rule struct {
 	// Regexp defines all regexp-rules which have to pass in order
 	// to pass the rule.
-	Regexp     []*regexp.Regexp
+	//
+	// Like git, nogo matches bytes instead of UTF-8 characters. Therefore the
+	// regexps are compiled so that each rune represents a single byte
+	// (e.g. "ä" becomes "\u00c3\u00a4"). MatchPath converts the path the
+	// same way using ByteRunes before matching it.
+	//
+	// Compile and NewRule convert the regexps. AddRules, AddRulesTagged and
+	// ReplaceGroup convert regexps of hand-built rules which contain UTF-8
+	// text, so e.g. regexp.MustCompile("^ä$") matches "ä". Only literal text
+	// is converted, escapes like "\x{e4}" match the byte with that value.
+	Regexp []*regexp.Regexp
+
+	// Prefix is the folder of the rule, e.g. the folder of its ignore file.
+	// The rule only applies to paths inside of it. The prefix is compared by
+	// path segments, so the prefix "a" applies to "a/b" but never to "ab/c".
+	// A trailing slash is ignored.
 	Prefix     string
 	Pattern    string
 	Negate     bool
@@ -19,7 +36,14 @@ var (
 	DotGitRule = MustCompileAll("", []byte(".git"))[0]
 )
 
+// MatchPath matches the path against the regexps of the rule.
+// Paths which are not inside of the Prefix never match.
 func (r Rule) MatchPath(path string) Result {
+	if !isPathInside(path, strings.TrimSuffix(r.Prefix, "/")) {
+		return Result{Rule: r}
+	}
+	path = ByteRunes(path)
+
 	var match bool
 	for _, reg := range r.Regexp {
 		match = reg.MatchString(path)
@@ -38,42 +62,83 @@ func (r Rule) MatchPath(path string) Result {
 	}
 }
 
-// These bytes won't be in any valid file, so they should be perfectly valid as temporary replacement.
-const (
-	doubleStar        = "\000"
-	singleStar        = "\001"
-	questionMark      = "\002"
-	negatedMatchStart = "\003"
-	matchStart        = "\004"
-	matchEnd          = "\005"
-	escapedMatchStart = "\006"
-	escapedMatchEnd   = "\007"
-)
+// ByteRunes converts each byte of the string to a rune with the same value.
+// It is used to match paths byte by byte using the regexps of a Rule,
+// as Go regexps always match whole UTF-8 characters.
+//
+// Strings which only contain ASCII characters are returned unchanged.
+func ByteRunes(s string) string {
+	i := 0
+	for i < len(s) && s[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(s) {
+		return s
+	}
 
-var (
-	// findRangeReg matches the replacements of [, [! and ].
-	// The ? in the regexp enables ungreedy mode.
-	findRangeReg = regexp.MustCompile(`[` + matchStart + negatedMatchStart + `].*?` + matchEnd)
-)
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+	b.WriteString(s[:i])
+	for ; i < len(s); i++ {
+		b.WriteRune(rune(s[i]))
+	}
+	return b.String()
+}
 
-// Compile the pattern into a single regexp.
-// skip means that this pattern doesn't contain any rule (e.g. just a comment or empty line).
-func Compile(prefix string, pattern string) (skip bool, rule Rule, err error) {
-	rule = Rule{
-		Prefix: prefix,
+// byteRegexps converts the regexps of the rule using ByteRunes if they
+// contain UTF-8 text, e.g. of a rule which was built by hand.
+// The regexps of the rule are not changed.
+func byteRegexps(rule Rule) Rule {
+	copied := false
+	for i, reg := range rule.Regexp {
+		if !needsByteRunes(reg.String()) {
+			continue
+		}
 
-		// The original pattern of the source file.
-		Pattern: pattern,
+		converted, err := regexp.Compile(ByteRunes(reg.String()))
+		if err != nil {
+			// Keep the regexp as it is if it can't be converted.
+			continue
+		}
+		if !copied {
+			rule.Regexp = append([]*regexp.Regexp(nil), rule.Regexp...)
+			copied = true
+		}
+		rule.Regexp[i] = converted
+	}
+	return rule
+}
+
+// needsByteRunes checks if the regexp source contains UTF-8 text which is
+// not converted using ByteRunes yet. Converted sources only contain runes
+// up to 0xff which are valid UTF-8 if they are read as bytes.
+func needsByteRunes(expr string) bool {
+	converted := make([]byte, 0, len(expr))
+	for _, r := range expr {
+		if r > 0xff {
+			return true
+		}
+		converted = append(converted, byte(r))
 	}
+	return !utf8.Valid(converted)
+}
 
+// neverMatchReg doesn't match anything.
+var neverMatchReg = regexp.MustCompile(`[^\x00-\x{10ffff}]`)
+
+// cleanPattern removes everything from a single line of an ignore file which
+// is not part of the actual glob: comments, the escaping of a leading '#',
+// trailing spaces and the negating '!'.
+// skip is true if the line does not contain any pattern.
+func cleanPattern(pattern string) (cleaned string, negate bool, skip bool) {
 	// ignoreFs empty lines.
 	if len(pattern) == 0 {
-		return true, Rule{}, nil
+		return "", false, true
 	}
 
 	// ignoreFs lines starting with # as these are comments.
 	if pattern[0] == '#' {
-		return true, Rule{}, nil
+		return "", false, true
 	}
 
 	// Unescape \# to #.
@@ -81,156 +146,227 @@ func Compile(prefix string, pattern string) (skip bool, rule Rule, err error) {
 		pattern = pattern[1:]
 	}
 
-	// ignoreFs spaces except when the last one is escaped: 'something   \ '.
-	// TODO: actually I am not sure if this is correct but that's what I understand by
-	//  "* Trailing spaces are ignored unless they are quoted with backslash ("\")."
-	//  However I don't think that this is very often used.
-	if strings.HasSuffix(pattern, "\\ ") {
-		pattern = strings.TrimSuffix(pattern, "\\ ") + " "
-	} else {
-		pattern = strings.TrimRight(pattern, " ")
+	// Trailing spaces are ignored unless they are escaped: 'something   \ '.
+	pattern = trimTrailingSpaces(pattern)
+
+	// A line which only consists of spaces is like an empty line.
+	if len(pattern) == 0 {
+		return "", false, true
 	}
 
 	// '!' negates the pattern.
 	if pattern[0] == '!' {
-		rule.Negate = true
+		negate = true
 		pattern = pattern[1:]
 	}
 
-	// If any '/' is at the beginning or middle, it is relative to the prefix.
-	// Else it may be anywhere bellow it and we have to apply a wildcard
-	if strings.Count(strings.TrimSuffix(pattern, "/"), "/") == 0 {
-		pattern = "**/" + strings.TrimPrefix(pattern, "/")
-	} else if prefix != "" {
-		// In most other cases we have to make sure the prefix ends with a '/'
-		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	// A single '!' does not contain any pattern and neither do only slashes,
+	// as git removes the trailing one and the pattern is empty then.
+	if strings.Trim(pattern, "/") == "" {
+		return "", false, true
 	}
 
-	// Replace all special chars with placeholders, then quote the rest.
-	// After that the special regexp for that special cases can be replaced.
+	return pattern, negate, false
+}
 
-	pattern = strings.ReplaceAll(pattern, "**", doubleStar)
-	pattern = strings.ReplaceAll(pattern, "*", singleStar)
-	pattern = strings.ReplaceAll(pattern, "?", questionMark)
+// trimTrailingSpaces removes the trailing spaces which are not escaped
+// by a backslash, the same way as git does it. The escaping backslashes are kept.
+func trimTrailingSpaces(pattern string) string {
+	lastSpace := -1
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case ' ':
+			if lastSpace < 0 {
+				lastSpace = i
+			}
+		case '\\':
+			i++
+			lastSpace = -1
+		default:
+			lastSpace = -1
+		}
+	}
+	if lastSpace < 0 {
+		return pattern
+	}
+	return pattern[:lastSpace]
+}
 
-	// Re-Replace escaped replacements.
-	pattern = strings.ReplaceAll(pattern, `\`+doubleStar, "**")
-	pattern = strings.ReplaceAll(pattern, `\`+singleStar, "*")
-	pattern = strings.ReplaceAll(pattern, `\`+questionMark, "?")
+// hasEmptySegment checks if the pattern contains "//" outside of a range.
+func hasEmptySegment(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			if end := rangeEnd(pattern, i+1); end >= 0 {
+				i = end
+			}
+		case '/':
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	pattern = regexp.QuoteMeta(pattern)
+// Compile the pattern into a single regexp.
+// skip means that this pattern doesn't contain any rule (e.g. just a comment or empty line).
+// If the pattern is invalid, a *PatternError is returned.
+func Compile(prefix string, pattern string) (skip bool, rule Rule, err error) {
+	rule = Rule{
+		Prefix: prefix,
+
+		// The original pattern of the source file.
+		Pattern: pattern,
+	}
+
+	pattern, rule.Negate, skip = cleanPattern(pattern)
+	if skip {
+		return true, Rule{}, nil
+	}
 
-	// Unescape and transform character matches.
-	// First replace all by the input escaped brackets to ignore them in the next replaces)
-	pattern = strings.ReplaceAll(pattern, `\\[`, escapedMatchStart)
-	pattern = strings.ReplaceAll(pattern, `\\]`, escapedMatchEnd)
+	// Paths never contain empty segments, so git never matches patterns like "a//b".
+	if hasEmptySegment(pattern) {
+		rule.Regexp = []*regexp.Regexp{neverMatchReg}
+		return false, rule, nil
+	}
 
-	// Then do the same with the negated one to ignore its bracket in the next replace.
-	pattern = strings.ReplaceAll(pattern, `\[!`, negatedMatchStart)
-	pattern = strings.ReplaceAll(pattern, `\[`, matchStart)
-	pattern = strings.ReplaceAll(pattern, `\]`, matchEnd)
-	// Now we can add any new regexp using [ and ] and still
-	// Do something with the placeholders later.
+	if hasUnclosedRange(pattern) {
+		// Git never matches unclosed ranges.
+		err = &syntax.Error{Code: syntax.ErrMissingBracket, Expr: rule.Pattern}
+		return false, Rule{}, &PatternError{Pattern: rule.Pattern, Cause: err}
+	}
 
 	// If any '/' is at the end, it matches only folders.
 	// Note, as the input does not show us if it is a folder, the bool
 	// is set and it has to be checked separately.
-	if strings.HasSuffix(pattern, "/") {
-		pattern = strings.TrimSuffix(pattern, "/")
-		rule.OnlyFolder = true
-	}
-
-	// Check the placeholders:
+	rule.OnlyFolder = strings.HasSuffix(pattern, "/")
 
-	// '?' matches any char but '/'.
-	pattern = strings.ReplaceAll(pattern, questionMark, "[^/]?")
+	primitives := rule.Matchers()
+	if prefix != "" {
+		// The prefix is matched literally and not by its primitive.
+		primitives = primitives[2:]
+	}
 
-	// Replace the placeholders:
-	// A leading "**" followed by a slash means matches in all directories.
-	if strings.HasPrefix(pattern, doubleStar+"/") {
-		if prefix == "" {
-			pattern = "(.*/)?" + strings.TrimPrefix(pattern, doubleStar+"/")
-		} else {
-			pattern = "(/.*)?" + strings.TrimPrefix(pattern, doubleStar)
+	expr, err := primitivesRegexp(prefix, primitives)
+	if err != nil {
+		return false, Rule{}, &PatternError{Pattern: rule.Pattern, Cause: err}
+	}
+	reg, err := regexp.Compile(ByteRunes(expr))
+	if err != nil {
+		return false, Rule{}, &PatternError{Pattern: rule.Pattern, Cause: err}
+	}
+	rule.Regexp = []*regexp.Regexp{reg}
 
-			// Also remove a possible '/' from the prefix so that it concatenates correctly with the wildcard
-			prefix = strings.TrimSuffix(prefix, "/")
+	return false, rule, nil
+}
 
+// hasUnclosedRange checks if the pattern contains a '[' without its ']'.
+func hasUnclosedRange(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			end := rangeEnd(pattern, i+1)
+			if end < 0 {
+				return true
+			}
+			i = end
 		}
 	}
+	return false
+}
 
-	// A trailing "/**" matches everything inside.
-	if strings.HasSuffix(pattern, "/"+doubleStar) {
-		pattern = strings.TrimSuffix(pattern, doubleStar) + ".*"
+// primitivesRegexp converts the primitives of a pattern to the source of
+// a regexp which matches the whole path. The prefix is matched literally
+// in front of the primitives.
+func primitivesRegexp(prefix string, primitives []Primitive) (string, error) {
+	// Split the primitives into segments. Several "**" in a row match the
+	// same as a single one.
+	var segments [][]Primitive
+	segment := []Primitive{}
+	for _, p := range append(primitives, Primitive{Kind: PrimitiveSeparator}) {
+		if p.Kind != PrimitiveSeparator {
+			segment = append(segment, p)
+			continue
+		}
+		if !(isDoubleStarSegment(segment) && len(segments) > 0 && isDoubleStarSegment(segments[len(segments)-1])) {
+			segments = append(segments, segment)
+		}
+		segment = []Primitive{}
 	}
 
-	// A slash followed by two consecutive asterisks then a slash matches zero or more directories.
-	pattern = strings.ReplaceAll(pattern, "/"+doubleStar+"/", ".*/")
-
-	// '*' matches anything but '/'.
-	pattern = strings.ReplaceAll(pattern, singleStar, "[^/]*")
-
-	// Now replace all still existing doubleStars and all stars by the single star rule.
-	// TODO: Not sure if that is the correct behavior.
-	pattern = strings.ReplaceAll(pattern, doubleStar, "[^/]*")
-
-	// Add an additional regexp which checks for non-slash on all range patterns.
-	// As the range should not match slashes, but as Go doesn't support look-ahead,
-	// I just add a new rule for this.
-	additionalPattern := findRangeReg.ReplaceAllString(pattern, `[^/]`)
-
-	finishPattern := func(p string) error {
-		// Now replace back the escaped brackets.
-		p = strings.ReplaceAll(p, escapedMatchStart, `[`)
-		p = strings.ReplaceAll(p, escapedMatchEnd, `]`)
-		p = strings.ReplaceAll(p, negatedMatchStart, "[^")
-		p = strings.ReplaceAll(p, matchStart, "[")
-		p = strings.ReplaceAll(p, matchEnd, "]")
-
-		reg, err := regexp.Compile("^" + regexp.QuoteMeta(prefix) + strings.TrimPrefix(p, "/") + "$")
-		if err != nil {
-			return err
+	var b strings.Builder
+	b.WriteString("^")
+	prefix = strings.TrimSuffix(prefix, "/")
+	b.WriteString(regexp.QuoteMeta(prefix))
+
+	// separator is true if a '/' is needed before the next segment.
+	separator := prefix != ""
+	for i, segment := range segments {
+		if isDoubleStarSegment(segment) {
+			switch {
+			case i == len(segments)-1:
+				// A trailing "/**" matches everything inside.
+				if separator {
+					b.WriteString("/")
+				}
+				b.WriteString(".*")
+			case !separator:
+				// A leading "**" followed by a slash means matches in all directories.
+				b.WriteString("(.*/)?")
+			case i == 0:
+				b.WriteString("(/.*)?/")
+			default:
+				// A slash followed by two consecutive asterisks then a slash matches zero or more directories.
+				b.WriteString("/(.*/)?")
+			}
+			separator = false
+			continue
 		}
 
-		rule.Regexp = append(rule.Regexp, reg)
-		return nil
-	}
-
-	// Skip that additional pattern if nothing was replaced.
-	if additionalPattern != pattern {
-		err := finishPattern(additionalPattern)
-		if err != nil {
-			return false, Rule{}, err
+		if separator {
+			b.WriteString("/")
+		}
+		separator = true
+
+		for _, p := range segment {
+			switch p.Kind {
+			case PrimitiveLiteral:
+				b.WriteString(regexp.QuoteMeta(p.Value))
+			case PrimitiveStar:
+				// '*' matches anything but '/'.
+				b.WriteString("[^/]*")
+			case PrimitiveQuestionMark:
+				// '?' matches any char but '/'.
+				b.WriteString("[^/]")
+			case PrimitiveRange:
+				// Ranges never match a '/', so they are replaced by classes which exclude it.
+				class, err := compileRange(p)
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(class)
+			}
 		}
 	}
 
-	err = finishPattern(pattern)
-	if err != nil {
-		return false, Rule{}, err
-	}
+	b.WriteString("$")
+	return b.String(), nil
+}
 
-	return false, rule, nil
+// isDoubleStarSegment checks if the segment only consists of "**".
+func isDoubleStarSegment(segment []Primitive) bool {
+	return len(segment) == 1 && segment[0].Kind == PrimitiveDoubleStar
 }
 
 // CompileAll rules in the given data line by line.
 // The prefix is added to all rules.
 func CompileAll(prefix string, data []byte) ([]Rule, error) {
-	rules := make([]Rule, 0)
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		// Remove \r on windows.
-		line = strings.TrimSuffix(line, "\r")
-
-		skip, rule, err := Compile(prefix, line)
-		if err != nil {
-			return nil, err
-		}
-
-		if !skip {
-			rules = append(rules, rule)
-		}
-	}
-	return rules, nil
+	return DialectGitignore.CompileAll(prefix, data)
 }
 
 // MustCompileAll does the same as CompileAll but panics on error.
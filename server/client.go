@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultBatchSize is the default amount of paths the Client sends per request.
+const DefaultBatchSize = 1000
+
+// Client queries a Handler.
+type Client struct {
+	// URL is the base URL of the server, e.g. "http://localhost:8080".
+	URL string
+
+	// HTTPClient is used for the requests.
+	// If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// BatchSize is the amount of paths sent per request.
+	// If it is <= 0, DefaultBatchSize is used. It must not be bigger than
+	// the WithMaxPaths of the server.
+	BatchSize int
+}
+
+// NewClient creates a Client for the server at the url.
+func NewClient(url string) *Client {
+	return &Client{URL: url}
+}
+
+// Match returns the verdicts of all paths in the same order.
+// Paths ending with a '/' are directories.
+// Many paths are split into several requests of BatchSize paths.
+func (c *Client) Match(ctx context.Context, paths []string) ([]Verdict, error) {
+	size := c.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	verdicts := make([]Verdict, 0, len(paths))
+	for start := 0; start < len(paths); start += size {
+		end := start + size
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		batch, err := c.match(ctx, paths[start:end])
+		if err != nil {
+			return nil, err
+		}
+		verdicts = append(verdicts, batch...)
+	}
+	return verdicts, nil
+}
+
+// MatchPath returns the verdict of a single path.
+func (c *Client) MatchPath(ctx context.Context, path string, isDir bool) (Verdict, error) {
+	if isDir && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	verdicts, err := c.match(ctx, []string{path})
+	if err != nil {
+		return Verdict{}, err
+	}
+	return verdicts[0], nil
+}
+
+// match sends a single request.
+func (c *Client) match(ctx context.Context, paths []string) ([]Verdict, error) {
+	body, err := json.Marshal(MatchRequest{Paths: paths})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.URL, "/")+"/match", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errRes errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errRes); err != nil || errRes.Error == "" {
+			return nil, fmt.Errorf("server: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("server: %s: %s", resp.Status, errRes.Error)
+	}
+
+	var res MatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("server: invalid response: %w", err)
+	}
+	if len(res.Verdicts) != len(paths) {
+		return nil, fmt.Errorf("server: got %d verdicts for %d paths", len(res.Verdicts), len(paths))
+	}
+	return res.Verdicts, nil
+}
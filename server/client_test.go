@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Match(t *testing.T) {
+	var requests int32
+	h := NewHandler(newTestNoGo(), WithMaxPaths(3))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/")
+	client.BatchSize = 3
+
+	paths := make([]string, 8)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("file%d.log", i)
+	}
+	paths[5] = "keep.log"
+
+	verdicts, err := client.Match(context.Background(), paths)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	require.Len(t, verdicts, len(paths))
+	for i, verdict := range verdicts {
+		assert.Equal(t, paths[i], verdict.Path)
+		assert.Equal(t, i != 5, verdict.Ignored, verdict.Path)
+	}
+
+	verdicts, err = client.Match(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, verdicts)
+}
+
+func TestClient_MatchPath(t *testing.T) {
+	server := httptest.NewServer(NewHandler(newTestNoGo(), WithMaxPaths(3)))
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	verdict, err := client.MatchPath(context.Background(), "build", true)
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Path: "build/", Ignored: true, Kind: "direct match", Cause: &Cause{Pattern: "build/"}}, verdict)
+
+	verdict, err = client.MatchPath(context.Background(), "build", false)
+	require.NoError(t, err)
+	assert.False(t, verdict.Ignored)
+
+	_, err = client.MatchPath(context.Background(), "a//b", false)
+	assert.ErrorContains(t, err, "400 Bad Request")
+
+	// The batch size is bigger than the limit of the server.
+	client.BatchSize = 4
+	_, err = client.Match(context.Background(), []string{"a", "b", "c", "d"})
+	assert.ErrorContains(t, err, "too many paths")
+}
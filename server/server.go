@@ -0,0 +1,221 @@
+// Package server exposes a NoGo matcher over HTTP, so tools which are not
+// written in Go can query the same ignore rules, e.g. in a polyglot monorepo.
+//
+// The Handler serves a single endpoint:
+//
+//	POST /match
+//	{"paths": ["src/main.go", "build/"]}
+//
+// Paths ending with a '/' are directories. The response contains a Verdict
+// for each path in the same order:
+//
+//	{"verdicts": [
+//		{"path": "src/main.go", "ignored": false, "kind": "no match"},
+//		{"path": "build/", "ignored": true, "kind": "direct match", "cause": {"pattern": "build/"}}
+//	]}
+//
+// Errors are returned with a status code other than 200 as {"error": "..."}.
+// The Client does the requests and splits big requests into batches.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aligator/nogo"
+)
+
+const (
+	// DefaultMaxPaths is the default amount of paths a single request may contain.
+	DefaultMaxPaths = 10000
+
+	// DefaultMaxBodySize is the default maximum size of a request body in bytes.
+	DefaultMaxBodySize = 16 << 20
+)
+
+// MatchRequest is the body of a request to /match.
+type MatchRequest struct {
+	// Paths to match, relative to the root of the rules and separated by '/'.
+	// Paths ending with a '/' are directories.
+	Paths []string `json:"paths"`
+}
+
+// MatchResponse is the body of a successful response of /match.
+type MatchResponse struct {
+	// Verdicts contains the result of each path in the same order as the paths.
+	Verdicts []Verdict `json:"verdicts"`
+}
+
+// Verdict tells if a path is ignored and why.
+type Verdict struct {
+	// Path is the path as it was sent.
+	Path string `json:"path"`
+
+	// Ignored is true if the path is ignored.
+	Ignored bool `json:"ignored"`
+
+	// Kind is the string of the nogo.MatchKind, e.g. "direct match".
+	Kind string `json:"kind"`
+
+	// Cause is the rule which decided the verdict.
+	// It is nil if no rule matched the path.
+	Cause *Cause `json:"cause,omitempty"`
+}
+
+// Cause is the rule which decided a Verdict.
+type Cause struct {
+	// Prefix is the folder of the rule, see nogo.Rule.Prefix.
+	Prefix string `json:"prefix,omitempty"`
+	// Pattern is the pattern of the rule as it was written in the ignore file.
+	Pattern string `json:"pattern"`
+	// Tag is set if the rule was added by nogo.NoGo.AddRulesTagged.
+	Tag string `json:"tag,omitempty"`
+}
+
+// errorResponse is the body of a failed response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Option configures a Handler.
+type Option interface {
+	apply(h *Handler)
+}
+
+type optionFunc func(h *Handler)
+
+func (f optionFunc) apply(h *Handler) {
+	f(h)
+}
+
+// WithMaxPaths limits the amount of paths of a single request.
+// Bigger requests are rejected with 413 Request Entity Too Large.
+// The default is DefaultMaxPaths.
+func WithMaxPaths(max int) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxPaths = max
+	})
+}
+
+// WithMaxBodySize limits the size of a request body in bytes.
+// Bigger requests are rejected with 413 Request Entity Too Large.
+// The default is DefaultMaxBodySize.
+func WithMaxBodySize(max int64) Option {
+	return optionFunc(func(h *Handler) {
+		h.maxBodySize = max
+	})
+}
+
+// Handler serves the /match endpoint for a NoGo.
+type Handler struct {
+	n           *nogo.NoGo
+	maxPaths    int
+	maxBodySize int64
+	mux         *http.ServeMux
+}
+
+// NewHandler creates a Handler which matches the paths using n.
+// The rules must not be changed while the Handler is used,
+// except by the lazy loading of nogo.New or nogo.WithLazyLoading.
+func NewHandler(n *nogo.NoGo, opts ...Option) *Handler {
+	h := &Handler{
+		n:           n,
+		maxPaths:    DefaultMaxPaths,
+		maxBodySize: DefaultMaxBodySize,
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt.apply(h)
+	}
+
+	h.mux.HandleFunc("/match", h.match)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) match(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	body := r.Body
+	if h.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	}
+
+	var req MatchRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body too large, at most %d bytes are allowed", tooLarge.Limit))
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	if h.maxPaths > 0 && len(req.Paths) > h.maxPaths {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("too many paths: %d, at most %d are allowed", len(req.Paths), h.maxPaths))
+		return
+	}
+
+	// Validate all paths first, so that no partial result is computed.
+	for _, path := range req.Paths {
+		if err := h.validate(path); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	res := MatchResponse{Verdicts: make([]Verdict, len(req.Paths))}
+	for i, path := range req.Paths {
+		res.Verdicts[i] = h.verdict(path)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// validate checks the path the same way it is normalized by the NoGo,
+// so that matching it can't panic, e.g. for absolute paths with
+// nogo.WithWindowsPaths.
+func (h *Handler) validate(path string) error {
+	normalized, err := h.n.NormalizePath(strings.TrimSuffix(path, "/"))
+	if err != nil {
+		return err
+	}
+	return nogo.ValidatePath(normalized)
+}
+
+// verdict matches a single path.
+func (h *Handler) verdict(path string) Verdict {
+	isDir := strings.HasSuffix(path, "/")
+	ignored, because := h.n.MatchBecause(strings.TrimSuffix(path, "/"), isDir)
+
+	verdict := Verdict{
+		Path:    path,
+		Ignored: ignored,
+		Kind:    because.Kind().String(),
+	}
+	if because.Found {
+		verdict.Cause = &Cause{
+			Prefix:  because.Prefix,
+			Pattern: because.Pattern,
+			Tag:     because.Tag,
+		}
+	}
+	return verdict
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
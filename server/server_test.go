@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aligator/nogo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNoGo() *nogo.NoGo {
+	n := nogo.New(nogo.WithRules(nogo.MustCompileAll("", []byte("*.log\n!keep.log\nbuild/\nsub/*.tmp"))...))
+	n.AddRulesTagged("builtin", nogo.MustCompileAll("", []byte("node_modules/"))...)
+	return n
+}
+
+func TestHandler_match(t *testing.T) {
+	h := NewHandler(newTestNoGo())
+
+	body, err := json.Marshal(MatchRequest{Paths: []string{"main.go", "a/debug.log", "keep.log", "build/", "build", "build/out.o", "node_modules/", "sub/a.tmp"}})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res MatchResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, []Verdict{
+		{Path: "main.go", Ignored: false, Kind: "no match"},
+		{Path: "a/debug.log", Ignored: true, Kind: "direct match", Cause: &Cause{Pattern: "*.log"}},
+		{Path: "keep.log", Ignored: false, Kind: "negated match", Cause: &Cause{Pattern: "!keep.log"}},
+		{Path: "build/", Ignored: true, Kind: "direct match", Cause: &Cause{Pattern: "build/"}},
+		{Path: "build", Ignored: false, Kind: "no match"},
+		{Path: "build/out.o", Ignored: true, Kind: "parent dir match", Cause: &Cause{Pattern: "build/"}},
+		{Path: "node_modules/", Ignored: true, Kind: "direct match", Cause: &Cause{Pattern: "node_modules/", Tag: "builtin"}},
+		{Path: "sub/a.tmp", Ignored: true, Kind: "direct match", Cause: &Cause{Pattern: "sub/*.tmp"}},
+	}, res.Verdicts)
+}
+
+func TestHandler_errors(t *testing.T) {
+	h := NewHandler(newTestNoGo(), WithMaxPaths(2), WithMaxBodySize(64))
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "wrong method", method: http.MethodGet, target: "/match", wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown endpoint", method: http.MethodPost, target: "/other", body: `{}`, wantStatus: http.StatusNotFound},
+		{name: "invalid json", method: http.MethodPost, target: "/match", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid path", method: http.MethodPost, target: "/match", body: `{"paths": ["a/../b"]}`, wantStatus: http.StatusBadRequest},
+		{name: "too many paths", method: http.MethodPost, target: "/match", body: `{"paths": ["a", "b", "c"]}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "body too large", method: http.MethodPost, target: "/match", body: `{"paths": ["` + strings.Repeat("a", 64) + `"]}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "empty", method: http.MethodPost, target: "/match", body: `{"paths": []}`, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body)))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantStatus != http.StatusOK && tt.wantStatus != http.StatusNotFound {
+				var res errorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				assert.NotEmpty(t, res.Error)
+			}
+		})
+	}
+}
+
+func TestHandler_windowsPaths(t *testing.T) {
+	h := NewHandler(nogo.New(nogo.WithWindowsPaths(), nogo.WithRules(nogo.MustCompileAll("", []byte("sub/*.tmp"))...)))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: `sub\\a.tmp`, wantStatus: http.StatusOK},
+		{path: `C:foo`, wantStatus: http.StatusBadRequest},
+		{path: `C:\\foo`, wantStatus: http.StatusBadRequest},
+		{path: `\\\\server\\share`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match", bytes.NewBufferString(`{"paths": ["`+tt.path+`"]}`)))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
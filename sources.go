@@ -0,0 +1,69 @@
+package nogo
+
+import "time"
+
+// SourceInfo describes an ignore file which was loaded.
+type SourceInfo struct {
+	// Path is the ignore file, the same as GroupInfo.Source.
+	Path string
+
+	// Prefix is the folder the rules of the file are relative to.
+	Prefix string
+
+	// Rules is the amount of rules loaded from the file.
+	Rules int
+
+	// LoadedAt is the time the file was loaded.
+	LoadedAt time.Time
+
+	// Duration is the time it took to read and compile the file.
+	Duration time.Duration
+}
+
+// sourceLoad describes when a source was loaded.
+type sourceLoad struct {
+	at       time.Time
+	duration time.Duration
+}
+
+// loaded records that the source was loaded, starting at start.
+func (n *NoGo) loaded(source string, start time.Time) {
+	if n.loads == nil {
+		n.loads = make(map[string]sourceLoad)
+	}
+	n.loads[source] = sourceLoad{at: start, duration: time.Since(start)}
+}
+
+// copyLoads copies the load times of all sources of from.
+func (n *NoGo) copyLoads(from *NoGo) {
+	for source, load := range from.loads {
+		if n.loads == nil {
+			n.loads = make(map[string]sourceLoad)
+		}
+		n.loads[source] = load
+	}
+}
+
+// Sources returns all ignore files which were loaded, in the order their
+// rules are checked. Rules added by AddRules or AddPatterns are not included.
+//
+// Ignore files inside of ignored folders are never loaded, so tools can use
+// it to show where the rules come from and to detect ignore files which were
+// skipped.
+func (n *NoGo) Sources() []SourceInfo {
+	sources := make([]SourceInfo, 0)
+	for _, g := range n.groups {
+		if g.source == "" {
+			continue
+		}
+		load := n.loads[g.source]
+		sources = append(sources, SourceInfo{
+			Path:     g.source,
+			Prefix:   g.prefix,
+			Rules:    len(g.rules),
+			LoadedAt: load.at,
+			Duration: load.duration,
+		})
+	}
+	return sources
+}
@@ -0,0 +1,57 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_Sources(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":                  {Data: []byte("ignored\n*.log\n")},
+		"sub/.gitignore":              {Data: []byte("!keep.log\n")},
+		"ignored/.gitignore":          {Data: []byte("!*\n")},
+		"sub/deeper/other/.gitignore": {Data: []byte("# only a comment\n")},
+	}
+
+	start := time.Now()
+	n, err := ForFS(fsys, ".gitignore", DotGitRule)
+	require.NoError(t, err)
+
+	sources := n.Sources()
+	var paths []string
+	for _, source := range sources {
+		paths = append(paths, source.Path)
+		assert.False(t, source.LoadedAt.Before(start), source.Path)
+		assert.GreaterOrEqual(t, source.Duration, time.Duration(0), source.Path)
+	}
+	// The ignore file inside of the ignored folder is skipped.
+	assert.Equal(t, []string{".gitignore", "sub/.gitignore", "sub/deeper/other/.gitignore"}, paths)
+
+	assert.Equal(t, "", sources[0].Prefix)
+	assert.Equal(t, 2, sources[0].Rules)
+	assert.Equal(t, "sub", sources[1].Prefix)
+	assert.Equal(t, 1, sources[1].Rules)
+	assert.Equal(t, 0, sources[2].Rules)
+
+	t.Run("merged", func(t *testing.T) {
+		merged := Merge(New(), n, MergeAppend)
+		assert.Equal(t, sources, merged.Sources())
+	})
+
+	t.Run("without ignore files", func(t *testing.T) {
+		n := New(DotGitRule)
+		assert.Empty(t, n.Sources())
+	})
+
+	t.Run("updated file", func(t *testing.T) {
+		before := sources[1].LoadedAt
+		require.NoError(t, n.UpdateFile("sub/.gitignore", []byte("a\nb\nc\n")))
+		updated := n.Sources()[1]
+		assert.Equal(t, 3, updated.Rules)
+		assert.False(t, updated.LoadedAt.Before(before))
+	})
+}
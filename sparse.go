@@ -0,0 +1,157 @@
+package nogo
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+// sparseConeRules converts the lines of a sparse-checkout file in cone mode
+// to rules, which ignore everything outside of the cone.
+// Lines like "!/dir/*/" are only valid after "/dir/", as git requires it.
+func sparseConeRules(prefix string, lines []string) ([]Rule, error) {
+	recursive := make(map[string]bool)
+	rules := make([]Rule, 0, len(lines))
+	for i, line := range lines {
+		dir, negate, skip, err := parseSparseConeLine(line)
+		if err == nil && negate && dir != "" && !recursive[dir] {
+			err = errors.New("sparse-checkout: the directory has to be added before excluding its sub directories")
+		}
+		if err != nil {
+			return nil, &PatternError{Line: i + 1, Pattern: line, Cause: err}
+		}
+		if skip {
+			continue
+		}
+		if !negate {
+			recursive[dir] = true
+		}
+
+		lineRules, err := DialectSparseCone.compile(prefix, line)
+		if err != nil {
+			return nil, patternErrorAt(err, "", i+1)
+		}
+		rules = append(rules, lineRules...)
+	}
+	return rules, nil
+}
+
+// compileSparseCone compiles a single line of a sparse-checkout file in cone mode.
+// The patterns of the file include paths. So they are inverted to rules which
+// ignore everything outside of the cone:
+//   - "/*" includes the files of the root, which are never ignored anyway.
+//   - "!/*/" excludes all directories of the root, so it becomes "/*/".
+//   - "/dir/" includes the directory recursively, so it becomes "!/dir/".
+//   - "!/dir/*/" excludes the sub directories of dir, so it becomes "/dir/*/".
+func compileSparseCone(prefix string, line string) ([]Rule, error) {
+	dir, negate, skip, err := parseSparseConeLine(line)
+	if err != nil {
+		return nil, &PatternError{Pattern: line, Cause: err}
+	}
+	if skip || !negate && dir == "" {
+		return nil, nil
+	}
+
+	pattern := "!/" + dir + "/"
+	if negate {
+		pattern = "/" + joinPrefix(dir, "*") + "/"
+	}
+	_, rule, err := Compile(prefix, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return []Rule{rule}, nil
+}
+
+// parseSparseConeLine parses a line of a sparse-checkout file in cone mode.
+// dir is "" for the lines of the root ("/*" and "!/*/").
+// skip is true for empty lines and comments.
+func parseSparseConeLine(line string) (dir string, negate bool, skip bool, err error) {
+	line = strings.TrimRight(line, " ")
+	if line == "" || line[0] == '#' {
+		return "", false, true, nil
+	}
+
+	switch {
+	case line == "/*":
+		return "", false, false, nil
+	case line == "!/*/":
+		return "", true, false, nil
+	case strings.HasPrefix(line, "!/") && strings.HasSuffix(line, "/*/"):
+		dir = strings.TrimSuffix(strings.TrimPrefix(line, "!/"), "/*/")
+		negate = true
+	case strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/") && len(line) > 2:
+		dir = strings.TrimSuffix(strings.TrimPrefix(line, "/"), "/")
+	default:
+		return "", false, false, errors.New("sparse-checkout: not a cone pattern")
+	}
+
+	if dir == "" || hasUnescapedWildcard(dir) || hasEmptySegment("/"+dir+"/") {
+		return "", false, false, errors.New("sparse-checkout: cone patterns only contain directory names")
+	}
+	return dir, negate, false, nil
+}
+
+// hasUnescapedWildcard checks if the pattern contains '*', '?' or '[' which
+// is not escaped using a backslash.
+func hasUnescapedWildcard(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+// SparseConePatterns returns the content of a sparse-checkout file in cone mode,
+// which includes the directories recursively, the same way as
+// "git sparse-checkout set" writes it. The files of the root and of all
+// parents of the directories are included, too.
+// The lines can be compiled using DialectSparseCone.
+func SparseConePatterns(dirs ...string) []string {
+	recursive := make(map[string]bool)
+	for _, dir := range dirs {
+		dir = strings.Trim(path.Clean("/"+dir), "/")
+		if dir != "" {
+			recursive[escapeGlob(dir)] = true
+		}
+	}
+
+	// Directories inside of other recursive directories are included anyway.
+	parents := make(map[string]bool)
+	for dir := range recursive {
+		for parent := path.Dir(dir); parent != "."; parent = path.Dir(parent) {
+			if recursive[parent] {
+				delete(recursive, dir)
+				break
+			}
+		}
+	}
+	for dir := range recursive {
+		for parent := path.Dir(dir); parent != "."; parent = path.Dir(parent) {
+			parents[parent] = true
+		}
+	}
+
+	lines := []string{"/*", "!/*/"}
+	all := make([]string, 0, len(parents)+len(recursive))
+	for dir := range parents {
+		all = append(all, dir)
+	}
+	for dir := range recursive {
+		all = append(all, dir)
+	}
+	sort.Strings(all)
+
+	for _, dir := range all {
+		lines = append(lines, "/"+dir+"/")
+		if parents[dir] {
+			lines = append(lines, "!/"+dir+"/*/")
+		}
+	}
+	return lines
+}
@@ -0,0 +1,128 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparseConeRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "cone",
+			data: "/*\n!/*/\n/a/\n!/a/*/\n/a/b/\n/c/\n",
+			want: map[string]bool{
+				"README.md":     false,
+				"x/file":        true,
+				"a/file":        false,
+				"a/b/file":      false,
+				"a/b/d/file":    false,
+				"a/other/file":  true,
+				"c/d/e/file":    false,
+				"cc/file":       true,
+				"x/a/file":      true,
+				"a/b2/file.txt": true,
+			},
+		},
+		{
+			name: "full checkout",
+			data: "/*\n",
+			want: map[string]bool{
+				"README.md": false,
+				"x/file":    false,
+			},
+		},
+		{
+			name: "comments and empty lines",
+			data: "# comment\n\n/*\n!/*/\n/a/\n",
+			want: map[string]bool{
+				"a/file": false,
+				"b/file": true,
+			},
+		},
+		{
+			name: "escaped wildcard",
+			data: "/*\n!/*/\n/a\\*b/\n",
+			want: map[string]bool{
+				"a*b/file": false,
+				"axb/file": true,
+			},
+		},
+		{
+			name:    "not a cone pattern",
+			data:    "/*\n!/*/\n*.go\n",
+			wantErr: true,
+		},
+		{
+			name:    "wildcard in directory",
+			data:    "/*\n!/*/\n/a*/\n",
+			wantErr: true,
+		},
+		{
+			name:    "sub directories excluded before the directory",
+			data:    "/*\n!/*/\n!/a/*/\n/a/\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := DialectSparseCone.CompileAll("", []byte(tt.data))
+			if tt.wantErr {
+				var patternErr *PatternError
+				assert.ErrorAs(t, err, &patternErr)
+				return
+			}
+			require.NoError(t, err)
+
+			n := New()
+			n.AddRules(rules...)
+
+			for path, want := range tt.want {
+				assert.Equal(t, want, n.Match(path, false), path)
+			}
+		})
+	}
+}
+
+func TestSparseConePatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		dirs []string
+		want []string
+	}{
+		{
+			name: "none",
+			want: []string{"/*", "!/*/"},
+		},
+		{
+			name: "nested",
+			dirs: []string{"a/b/c", "d"},
+			want: []string{"/*", "!/*/", "/a/", "!/a/*/", "/a/b/", "!/a/b/*/", "/a/b/c/", "/d/"},
+		},
+		{
+			name: "inside of other directory",
+			dirs: []string{"a/b", "a/", "./a/c"},
+			want: []string{"/*", "!/*/", "/a/"},
+		},
+		{
+			name: "special characters",
+			dirs: []string{"a*"},
+			want: []string{"/*", "!/*/", "/a\\*/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := SparseConePatterns(tt.dirs...)
+			assert.Equal(t, tt.want, lines)
+
+			_, err := DialectSparseCone.compileLines("", lines)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package nogo
+
+import (
+	"sync/atomic"
+)
+
+// RuleStat contains how often a rule matched.
+type RuleStat struct {
+	Rule Rule
+	// Matches is the amount of checked paths (including parent folders)
+	// the rule matched, even if a later rule overrides the decision.
+	Matches uint64
+}
+
+// WithStats counts how often each rule matches, e.g. to find entries of
+// ignore files which don't match anything anymore. Use Stats to get the counts.
+//
+// It disables the shortcut of Match for terminal rules, as all rules have to be
+// checked. Paths which are answered by the cache (see WithCache) are not counted again.
+func WithStats() Option {
+	return optionFunc(func(n *NoGo) {
+		n.stats = true
+		n.changed()
+	})
+}
+
+// initStats adds the counters to all groups which don't have them yet.
+func (n *NoGo) initStats() {
+	for i, g := range n.groups {
+		if len(g.hits) != len(g.rules) {
+			n.groups[i].hits = make([]uint64, len(g.rules))
+		}
+	}
+}
+
+// Stats returns the amount of matches of all rules in the order they are checked.
+// Rules with zero matches never matched any path since they were added.
+// It returns nil if WithStats is not used.
+//
+// It is safe to call Stats while matching, but not while adding rules
+// (except by WithLazyLoading).
+func (n *NoGo) Stats() []RuleStat {
+	if !n.stats {
+		return nil
+	}
+
+	if n.lazy != nil {
+		n.lazy.mu.Lock()
+		defer n.lazy.mu.Unlock()
+	}
+
+	var stats []RuleStat
+	for _, g := range n.groups {
+		for i, rule := range g.rules {
+			stats = append(stats, RuleStat{
+				Rule:    rule,
+				Matches: atomic.LoadUint64(&g.hits[i]),
+			})
+		}
+	}
+	return stats
+}
@@ -0,0 +1,64 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStats(t *testing.T) {
+	rules := "node_modules\n*.log\n!keep.log\n/unused\nbuild/"
+
+	for _, backend := range []Backend{RegexpBackend, GlobBackend} {
+		for _, compile := range []bool{false, true} {
+			n := New(WithBackend(backend), WithStats())
+			n.AddRules(MustCompileAll("", []byte(rules))...)
+			if compile {
+				n.Compile()
+			}
+
+			n.Match("web/node_modules/a.js", false)
+			n.Match("a.log", false)
+			n.Match("keep.log", false)
+			n.Match("build", false)
+
+			var got []uint64
+			for _, stat := range n.Stats() {
+				got = append(got, stat.Matches)
+			}
+			// node_modules is matched as parent, keep.log by *.log and !keep.log.
+			assert.Equal(t, []uint64{1, 2, 1, 0, 0}, got, "%v %v", backend, compile)
+			assert.Equal(t, "node_modules", n.Stats()[0].Rule.Pattern)
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		n := New(MustCompileAll("", []byte(rules))[0])
+		n.Match("node_modules", true)
+		assert.Nil(t, n.Stats())
+	})
+
+	t.Run("walk", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			".gitignore":     {Data: []byte("*.tmp\n/dead")},
+			"a.tmp":          {},
+			"sub/.gitignore": {Data: []byte("*.go\n")},
+			"sub/b.tmp":      {},
+			"main.go":        {},
+		}
+		n := New(WithStats())
+		err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			return err
+		}, WithIgnoreFile(".gitignore"))
+		require.NoError(t, err)
+
+		got := make(map[string]uint64)
+		for _, stat := range n.Stats() {
+			got[stat.Rule.Prefix+"|"+stat.Rule.Pattern] = stat.Matches
+		}
+		assert.Equal(t, map[string]uint64{"|*.tmp": 2, "|/dead": 0, "sub|*.go": 0}, got)
+	})
+}
@@ -0,0 +1,57 @@
+package nogo
+
+import (
+	"errors"
+	"path"
+)
+
+// WithStrictIO makes sure that ignore files which can't be read never result
+// in fewer ignored paths, e.g. for security sensitive tools which must not
+// include files by accident. Missing ignore files are no error.
+//
+// AddFromFS, ForFS and WalkDir with WithIgnoreFile always return such errors.
+// But ignore files which are loaded lazily (see WithLazyLoading) are loaded
+// while matching, so the error can only be returned by LazyLoadErr. Without
+// WithStrictIO the directory is then matched without the ignore file. With
+// WithStrictIO everything inside of the directory is ignored instead.
+//
+// Invalid patterns are no I/O errors, so they are handled as before.
+func WithStrictIO() Option {
+	return optionFunc(func(n *NoGo) {
+		n.strictIO = true
+	})
+}
+
+// isIOError checks if the error happened while reading a file,
+// in contrast to errors of the content such as invalid patterns.
+func isIOError(err error) bool {
+	var patternErr *PatternError
+	return !errors.As(err, &patternErr)
+}
+
+// ignoreUnreadable ignores everything inside of the directory, because its
+// ignore file can't be read. The rule is added with the ignore file as source,
+// so MatchBecause shows which file is the reason. For allow lists, the rule
+// is negated, so it excludes everything.
+func (n *NoGo) ignoreUnreadable(dir string, file string) {
+	prefix := path.Clean(dir)
+	if prefix == "." {
+		prefix = ""
+	}
+
+	pattern := "*"
+	if n.allowList {
+		pattern = "!*"
+	}
+	_, rule, err := Compile(prefix, pattern)
+	if err != nil {
+		// The pattern is always valid.
+		panic(err)
+	}
+	n.addGroup(group{
+		prefix: prefix,
+		rules:  []Rule{rule},
+		source: file,
+	})
+	n.changed()
+}
@@ -0,0 +1,60 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStrictIO(t *testing.T) {
+	newFS := func() ForbiddenFS {
+		return ForbiddenFS{
+			MapFS: fstest.MapFS{
+				".gitignore":         {Data: []byte("*.log\n")},
+				"sub/.gitignore":     {Data: []byte("secret.txt\n")},
+				"sub/secret.txt":     {},
+				"sub/public.txt":     {},
+				"broken/.gitignore":  {Data: []byte("[\n")},
+				"broken/public.txt":  {},
+				"other/.gitignore":   {Data: []byte("other.txt\n")},
+				"other/included.txt": {},
+			},
+			NotExpected: map[string]struct{}{"sub/.gitignore": {}},
+		}
+	}
+
+	t.Run("without strict io", func(t *testing.T) {
+		n := New(WithFS(newFS()))
+		assert.False(t, n.Match("sub/secret.txt", false))
+		assert.ErrorIs(t, n.LazyLoadErr(), ErrShouldNotBeReached)
+	})
+
+	t.Run("strict io", func(t *testing.T) {
+		n := New(WithFS(newFS()), WithStrictIO())
+		match, because := n.MatchBecause("sub/public.txt", false)
+		assert.True(t, match)
+		assert.Equal(t, "*", because.Pattern)
+		assert.True(t, n.Match("sub/deeper/file", false))
+		assert.ErrorIs(t, n.LazyLoadErr(), ErrShouldNotBeReached)
+
+		// Other directories are not affected.
+		assert.False(t, n.Match("other/included.txt", false))
+		assert.True(t, n.Match("other/a.log", false))
+		assert.False(t, n.Match("sub", true))
+	})
+
+	t.Run("invalid patterns are no io errors", func(t *testing.T) {
+		n := New(WithFS(newFS()), WithStrictIO())
+		assert.False(t, n.Match("broken/public.txt", false))
+		var patternErr *PatternError
+		assert.ErrorAs(t, n.LazyLoadErr(), &patternErr)
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		fsys := newFS()
+		fsys.MapFS[".gitignore"] = &fstest.MapFile{Data: []byte("sub/\n")}
+		n := NewAllowList(WithFS(fsys), WithStrictIO())
+		assert.True(t, n.Match("sub/public.txt", false))
+	})
+}
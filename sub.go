@@ -0,0 +1,167 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// Sub returns a new NoGo for the paths inside of dir, relative to dir, like
+// fs.Sub does it for a file system. So n.Sub("src").Match("main.go", false)
+// gives the same result as n.Match("src/main.go", false).
+//
+// The rules of ignore files inside of dir get their prefix trimmed and rules
+// which can't match anything inside of dir are dropped. The rules of parent
+// folders are converted to match the paths relative to dir, e.g. "/src/gen"
+// becomes "/gen" for the dir "src" and "*.log" stays the same.
+//
+// Like the root of fs.Sub, dir itself is never ignored. So rules which match
+// dir or one of its parents are dropped, too. Check dir using Match first
+// if this is needed. Rules without a pattern (e.g. of DialectHgignore) can only
+// be converted if they are inside of dir.
+//
+// All options are kept, except WithFS and WithStatFS, the same as for Rebase.
+func (n *NoGo) Sub(dir string) *NoGo {
+	dir = n.groupPrefix(dir)
+	if dir == "." {
+		dir = ""
+	}
+
+	var groups []group
+	for _, g := range n.groups {
+		var rules []Rule
+		for _, rule := range g.rules {
+			rules = append(rules, subRules(dir, rule)...)
+		}
+		if len(rules) == 0 {
+			continue
+		}
+
+		prefix := ""
+		if isPathInside(g.prefix, dir) {
+			prefix = trimPrefix(g.prefix, dir)
+		}
+		groups = append(groups, group{
+			prefix: prefix,
+			rules:  rules,
+			source: g.source,
+			folded: g.folded,
+			tag:    g.tag,
+		})
+	}
+	return n.derive(groups)
+}
+
+// subRules converts the rule to match the paths relative to dir.
+// It returns no rule if the rule can't match anything inside of dir.
+func subRules(dir string, rule Rule) []Rule {
+	if dir == "" {
+		return []Rule{rule}
+	}
+
+	// The rules inside of dir only need a new prefix.
+	if isPathInside(rule.Prefix, dir) {
+		return compileSubRules(trimPrefix(rule.Prefix, dir), rule.Pattern)
+	}
+
+	// Other rules can't match anything inside of dir if dir is not inside of their prefix.
+	if !isPathInside(dir, rule.Prefix) {
+		return nil
+	}
+
+	pattern, negate, skip := cleanPattern(rule.Pattern)
+	if skip {
+		return nil
+	}
+
+	// Unanchored rules match the name of a path at any depth.
+	trimmed := strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(trimmed, "/") {
+		return compileSubRules("", rule.Pattern)
+	}
+
+	dirSegments := strings.Split(trimPrefix(dir, rule.Prefix), "/")
+	segments := strings.Split(strings.TrimPrefix(trimmed, "/"), "/")
+	folder := ""
+	if strings.HasSuffix(pattern, "/") {
+		folder = "/"
+	}
+
+	var rules []Rule
+	seen := make(map[string]bool)
+	for _, remaining := range trimSegments(segments, dirSegments) {
+		// A single segment needs a leading slash to stay anchored and a
+		// leading '!' must not negate the rule.
+		line := strings.Join(remaining, "/") + folder
+		if len(remaining) == 1 || strings.HasPrefix(line, "!") {
+			line = "/" + line
+		}
+		line = patternLine(line, negate)
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		rules = append(rules, compileSubRules("", line)...)
+	}
+	return rules
+}
+
+// compileSubRules compiles the pattern with the new prefix.
+// Rules without a pattern are dropped.
+func compileSubRules(prefix string, pattern string) []Rule {
+	// The line only consists of the remaining segments of a compiled pattern,
+	// so it compiles, too. Sub can't return an error, so a line which fails
+	// anyway is dropped like a line without a pattern.
+	skip, rule, err := Compile(prefix, pattern)
+	if skip || err != nil {
+		return nil
+	}
+	return []Rule{rule}
+}
+
+// trimSegments returns all variants of the remaining segments of the pattern
+// after matching the segments of the dir. It returns nothing if the
+// pattern doesn't match the dir or if it matches the dir itself or a parent.
+func trimSegments(segments []string, dirSegments []string) [][]string {
+	if len(segments) == 0 {
+		// The pattern matches the dir itself or a parent.
+		return nil
+	}
+	if len(dirSegments) == 0 {
+		return [][]string{segments}
+	}
+
+	if segments[0] == "**" {
+		if len(segments) == 1 {
+			// A trailing "**" matches the dir itself.
+			return nil
+		}
+
+		// "**" matches no folder or at least one more.
+		return append(
+			trimSegments(segments[1:], dirSegments),
+			trimSegments(segments, dirSegments[1:])...,
+		)
+	}
+
+	if !segmentMatches(segments[0], dirSegments[0]) {
+		return nil
+	}
+	return trimSegments(segments[1:], dirSegments[1:])
+}
+
+// segmentMatches checks if a single segment of a pattern matches the name.
+func segmentMatches(segment string, name string) bool {
+	skip, rule, err := Compile("", patternLine("/"+segment, false))
+	if skip || err != nil {
+		return false
+	}
+	return rule.MatchPath(name).Found
+}
+
+// trimPrefix returns the path relative to the prefix.
+// The path has to be inside of the prefix.
+func trimPrefix(path string, prefix string) string {
+	if prefix == "" || path == prefix {
+		return strings.TrimPrefix(path, prefix)
+	}
+	return strings.TrimPrefix(path, prefix+"/")
+}
@@ -0,0 +1,93 @@
+package nogo
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_Sub(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte(
+			"*.log\n/build\nsrc/gen/\n!src/keep.log\nsrc/*/tmp\n**/cache/out\na/**/b\n/src\\ \nc/!d/e\n",
+		)},
+		"src/.gitignore":     {Data: []byte("/local\ndist/")},
+		"src/app/.gitignore": {Data: []byte("*.bin")},
+		"other/.gitignore":   {Data: []byte("*.go")},
+	}
+	n, err := ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	tests := []struct {
+		dir   string
+		path  string
+		isDir bool
+	}{
+		{dir: "src", path: "debug.log"},
+		{dir: "src", path: "keep.log"},
+		{dir: "src", path: "deep/keep.log"},
+		{dir: "src", path: "build", isDir: true},
+		{dir: "src", path: "gen", isDir: true},
+		{dir: "src", path: "gen"},
+		{dir: "src", path: "gen/a.go"},
+		{dir: "src", path: "app/tmp"},
+		{dir: "src", path: "app/x/tmp"},
+		{dir: "src", path: "cache/out"},
+		{dir: "src", path: "x/cache/out"},
+		{dir: "src", path: "local", isDir: true},
+		{dir: "src", path: "app/local", isDir: true},
+		{dir: "src", path: "app/dist", isDir: true},
+		{dir: "src", path: "app/main.bin"},
+		{dir: "src", path: "main.bin"},
+		{dir: "src", path: "main.go"},
+		{dir: "src/app", path: "tmp"},
+		{dir: "src/app", path: "main.bin"},
+		{dir: "src/app", path: "local", isDir: true},
+		{dir: "src/app/", path: "dist", isDir: true},
+		{dir: "a", path: "b"},
+		{dir: "a", path: "x/b"},
+		{dir: "a/x", path: "b"},
+		{dir: "a/x", path: "y/b"},
+		{dir: "a/b/c", path: "b"},
+		{dir: "c", path: "!d/e"},
+		{dir: "other", path: "main.go"},
+		{dir: "other", path: "main.log"},
+		{dir: ".", path: "build", isDir: true},
+		{dir: "", path: "src/gen", isDir: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir+"/"+tt.path, func(t *testing.T) {
+			want := n.Match(joinPrefix(strings.Trim(tt.dir, "/."), tt.path), tt.isDir)
+			assert.Equal(t, want, n.Sub(tt.dir).Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestNoGo_Sub_IgnoredDir(t *testing.T) {
+	n := New()
+	require.NoError(t, n.AddPatterns("", []string{"/src", "a/**", "/x/"}))
+
+	// Like the root of fs.Sub, the dir itself is never ignored.
+	assert.False(t, n.Sub("src").Match("main.go", false))
+	assert.False(t, n.Sub("a/b").Match("main.go", false))
+	assert.False(t, n.Sub("x").Match("main.go", false))
+	assert.Empty(t, n.Sub("src").Groups())
+}
+
+func TestNoGo_Sub_Groups(t *testing.T) {
+	n := New()
+	require.NoError(t, n.AddPatterns("", []string{"*.log", "/other"}))
+	require.NoError(t, n.AddPatterns("src/app", []string{"/gen"}))
+	require.NoError(t, n.AddPatterns("lib", []string{"*.go"}))
+
+	groups := n.Sub("src").Groups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, "", groups[0].Prefix)
+	assert.Equal(t, "*.log", groups[0].Rules[0].Pattern)
+	assert.Equal(t, "app", groups[1].Prefix)
+	assert.Equal(t, "app", groups[1].Rules[0].Prefix)
+}
@@ -0,0 +1,61 @@
+package nogo
+
+import (
+	"io/fs"
+	"path"
+	"sync"
+)
+
+// WithSubmodules treats directories which contain a ".git" file or directory
+// as roots of separate repositories, like git does it for submodules and
+// nested repositories. Inside of them, the ignore files of the outer repository
+// don't apply anymore; only their own ignore files are used, which are loaded
+// the same way as all other ignore files.
+//
+// The root directory of a submodule itself is still matched by the rules of
+// the outer repository. So if it is ignored, everything inside of it is ignored, too.
+// Rules which were not loaded from an ignore file, such as DotGitRule or
+// the rules of WithRules, apply to all repositories, like the global excludes of git.
+//
+// The submodules are detected using the file system of WithFS (or ForFS).
+// Compile has no effect with this option.
+func WithSubmodules() Option {
+	return optionFunc(func(n *NoGo) {
+		n.submodules = &submoduleRoots{roots: make(map[string]bool)}
+	})
+}
+
+// submoduleRoots caches which directories contain a ".git".
+type submoduleRoots struct {
+	mu    sync.Mutex
+	roots map[string]bool
+}
+
+// submoduleRoot returns the deepest parent of the path which is the root of
+// a submodule, or "" if there is none.
+func (n *NoGo) submoduleRoot(p string) string {
+	if n.submodules == nil || n.loadFS == nil {
+		return ""
+	}
+
+	for dir := path.Dir(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if n.submodules.isRoot(n.loadFS, dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// isRoot checks if the directory contains a ".git".
+func (s *submoduleRoots) isRoot(fsys fs.FS, dir string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root, ok := s.roots[dir]
+	if !ok {
+		_, err := fs.Stat(fsys, path.Join(dir, ".git"))
+		root = err == nil
+		s.roots[dir] = root
+	}
+	return root
+}
@@ -0,0 +1,85 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var submoduleTestFiles = map[string]string{
+	".gitignore":                "*.log\n/build\nsecret\nignored-sub/\n",
+	".git/HEAD":                 "",
+	"sub/.git":                  "gitdir: ../.git/modules/sub\n",
+	"sub/.gitignore":            "*.tmp\n",
+	"sub/inner/.git/HEAD":       "",
+	"sub/inner/.gitignore":      "*.bak\n",
+	"ignored-sub/.git":          "gitdir: ../.git/modules/ignored-sub\n",
+	"ignored-sub/.gitignore":    "!*.log\n",
+	"ignored-sub/main.go":       "",
+	"sub/debug.log":             "",
+	"sub/inner/debug.log":       "",
+	"sub/inner/a.tmp":           "",
+	"sub/inner/a.bak":           "",
+	"sub/a.tmp":                 "",
+	"sub/secret":                "",
+	"sub/build/out":             "",
+	"debug.log":                 "",
+	"a.tmp":                     "",
+	"submodule-like/.gitignore": "!*.log\n",
+	"submodule-like/keep.log":   "",
+}
+
+func TestWithSubmodules(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "debug.log", want: true},
+		{path: "a.tmp", want: false},
+		{path: "sub", isDir: true, want: false},
+		{path: "sub/debug.log", want: false},
+		{path: "sub/a.tmp", want: true},
+		{path: "sub/secret", want: false},
+		{path: "sub/build/out", want: false},
+		{path: "sub/.git", want: true},
+		{path: "sub/inner/debug.log", want: false},
+		{path: "sub/inner/a.tmp", want: false},
+		{path: "sub/inner/a.bak", want: true},
+		{path: "sub/inner/.git/HEAD", want: true},
+		// The root of a submodule is matched by the outer repository.
+		{path: "ignored-sub", isDir: true, want: true},
+		{path: "ignored-sub/main.go", want: true},
+		// Ignore files of normal folders still work as usual.
+		{path: "submodule-like/keep.log", want: false},
+	}
+
+	for _, lazy := range []bool{false, true} {
+		for _, compile := range []bool{false, true} {
+			opts := []Option{DotGitRule, WithSubmodules()}
+			if lazy {
+				opts = append(opts, WithLazyLoading())
+			}
+			n, err := ForFS(newTestMapFS(submoduleTestFiles), ".gitignore", opts...)
+			require.NoError(t, err)
+			if compile {
+				n.Compile()
+			}
+
+			for _, tt := range tests {
+				assert.Equal(t, tt.want, n.Match(tt.path, tt.isDir), "%s (lazy %v, compile %v)", tt.path, lazy, compile)
+			}
+		}
+	}
+}
+
+func TestWithSubmodules_Disabled(t *testing.T) {
+	n, err := ForFS(newTestMapFS(submoduleTestFiles), ".gitignore", DotGitRule)
+	require.NoError(t, err)
+
+	// Without the option, the rules of the outer repository apply everywhere.
+	assert.True(t, n.Match("sub/debug.log", false))
+	assert.True(t, n.Match("sub/secret", false))
+	assert.True(t, n.Match("sub/inner/a.tmp", false))
+}
@@ -0,0 +1,111 @@
+package nogo
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Suggest returns patterns which ignore the path, e.g. for "add to .gitignore"
+// actions of editors. The path is relative to the root of fsys, which is
+// expected to contain the ignore file.
+//
+// The candidates are the exact path, the name at any level, the extension in
+// the same folder and at any level, and the parent folder by path and by name.
+// They are ranked by specificity, so the patterns which match the fewest
+// entries of fsys come first. Patterns which match the same number of entries
+// keep the order above. Only patterns which actually match the path are returned.
+func Suggest(fsys fs.FS, p string) []string {
+	p = strings.Trim(path.Clean(p), "/")
+	if p == "." || p == "" {
+		return []string{}
+	}
+
+	isDir := false
+	if info, err := fs.Stat(fsys, p); err == nil {
+		isDir = info.IsDir()
+	}
+
+	type suggestion struct {
+		pattern string
+		n       *NoGo
+		matches int
+	}
+	var suggestions []*suggestion
+	seen := make(map[string]bool)
+	add := func(pattern string) {
+		if seen[pattern] {
+			return
+		}
+		seen[pattern] = true
+
+		skip, rule, err := Compile("", pattern)
+		if err != nil || skip {
+			return
+		}
+		n := New(WithRules(rule))
+		if n.Match(p, isDir) {
+			suggestions = append(suggestions, &suggestion{pattern: pattern, n: n})
+		}
+	}
+
+	dir, name := path.Split(p)
+	dir = strings.TrimSuffix(dir, "/")
+	folderSuffix := ""
+	if isDir {
+		folderSuffix = "/"
+	}
+
+	add("/" + escapeGlob(p) + folderSuffix)
+	if isNamePatternSafe(name) {
+		add(escapeGlob(name) + folderSuffix)
+	}
+	if ext := path.Ext(name); !isDir && ext != "" && ext != name {
+		if dir != "" {
+			add("/" + escapeGlob(dir) + "/*" + escapeGlob(ext))
+		}
+		add("*" + escapeGlob(ext))
+	}
+	if dir != "" {
+		add("/" + escapeGlob(dir) + "/")
+		if parent := path.Base(dir); isNamePatternSafe(parent) {
+			add(escapeGlob(parent) + "/")
+		}
+	}
+
+	// Count how many entries of fsys each pattern ignores, including the
+	// entries inside of ignored folders.
+	_ = fs.WalkDir(fsys, ".", func(entry string, d fs.DirEntry, err error) error {
+		if err != nil || entry == "." {
+			return nil
+		}
+		for _, s := range suggestions {
+			if s.n.Match(entry, d.IsDir()) {
+				s.matches++
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].matches < suggestions[j].matches
+	})
+
+	patterns := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		patterns[i] = s.pattern
+	}
+	return patterns
+}
+
+// isNamePatternSafe checks if the name can be used as pattern without
+// a leading slash, which is not possible for names which would be read as
+// comment, negation or with trailing spaces.
+func isNamePatternSafe(name string) bool {
+	return name != "" &&
+		!strings.HasPrefix(name, "#") &&
+		!strings.HasPrefix(name, "!") &&
+		!strings.HasSuffix(name, " ") &&
+		!strings.Contains(name, `\`)
+}
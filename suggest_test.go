@@ -0,0 +1,76 @@
+package nogo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"debug.log":           {},
+		"main.go":             {},
+		"sub/build/out.log":   {},
+		"sub/build/out.txt":   {},
+		"sub/build/other.log": {},
+		"sub/error.log":       {},
+		"other/build/x":       {},
+		"other/out.log":       {},
+		"#notes":              {},
+		".env":                {},
+		"file[1].txt":         {},
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{
+			path: "sub/build/out.log",
+			want: []string{
+				"/sub/build/out.log",
+				"out.log",
+				"/sub/build/*.log",
+				"/sub/build/",
+				"*.log",
+				"build/",
+			},
+		},
+		{
+			path: "sub/build",
+			want: []string{"/sub/build/", "build/", "/sub/", "sub/"},
+		},
+		{
+			path: "debug.log",
+			want: []string{"/debug.log", "debug.log", "*.log"},
+		},
+		{
+			// Names starting with '#' can only be matched using the path.
+			path: "#notes",
+			want: []string{"/#notes"},
+		},
+		{
+			// The name of dotfiles is no extension.
+			path: ".env",
+			want: []string{"/.env", ".env"},
+		},
+		{
+			path: "file[1].txt",
+			want: []string{`/file\[1\].txt`, `file\[1\].txt`, "*.txt"},
+		},
+		{
+			path: "missing/new.log",
+			want: []string{"/missing/new.log", "new.log", "/missing/*.log", "/missing/", "missing/", "*.log"},
+		},
+		{
+			path: ".",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, Suggest(fsys, tt.path))
+		})
+	}
+}
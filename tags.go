@@ -0,0 +1,62 @@
+package nogo
+
+// AddRulesTagged adds the rules like AddRules and tags them, e.g. with
+// "builtin" for the default excludes of a tool. Result.Tag tells which tag
+// caused a match, and DisableTag turns all rules of a tag off without
+// rebuilding the NoGo.
+// An empty tag is the same as AddRules.
+func (n *NoGo) AddRulesTagged(tag string, rules ...Rule) {
+	for _, rule := range rules {
+		n.addGroup(group{
+			prefix: rule.Prefix,
+			rules:  []Rule{byteRegexps(rule)},
+			tag:    tag,
+		})
+	}
+	n.changed()
+}
+
+// DisableTag ignores all rules added by AddRulesTagged with the tag until
+// EnableTag is called, e.g. to offer "ignore the built-in excludes for this run".
+func (n *NoGo) DisableTag(tag string) {
+	if n.disabledTags == nil {
+		n.disabledTags = make(map[string]bool)
+	}
+	n.disabledTags[tag] = true
+	n.changed()
+}
+
+// EnableTag enables the rules of a tag again after DisableTag.
+func (n *NoGo) EnableTag(tag string) {
+	if !n.disabledTags[tag] {
+		return
+	}
+	delete(n.disabledTags, tag)
+	n.changed()
+}
+
+// TagDisabled checks if the tag was disabled by DisableTag.
+func (n *NoGo) TagDisabled(tag string) bool {
+	return n.disabledTags[tag]
+}
+
+// enabledGroups returns the groups without the ones of disabled tags,
+// together with their globs. globs is nil if n.globs is nil.
+func (n *NoGo) enabledGroups() ([]group, [][]*glob) {
+	if len(n.disabledTags) == 0 {
+		return n.groups, n.globs
+	}
+
+	groups := make([]group, 0, len(n.groups))
+	var globs [][]*glob
+	for i, g := range n.groups {
+		if g.tag != "" && n.disabledTags[g.tag] {
+			continue
+		}
+		groups = append(groups, g)
+		if n.globs != nil {
+			globs = append(globs, n.globs[i])
+		}
+	}
+	return groups, globs
+}
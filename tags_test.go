@@ -0,0 +1,69 @@
+package nogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoGo_AddRulesTagged(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(n *NoGo)
+	}{
+		{name: "default", prepare: func(n *NoGo) {}},
+		{name: "compiled", prepare: func(n *NoGo) { n.Compile() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New(WithBackend(GlobBackend))
+			n.AddRulesTagged("builtin", MustCompileAll("", []byte("node_modules/\n*.log"))...)
+			n.AddRules(MustCompileAll("", []byte("*.tmp"))...)
+			tt.prepare(n)
+
+			match, because := n.MatchBecause("node_modules/a.js", false)
+			assert.True(t, match)
+			assert.Equal(t, "builtin", because.Tag)
+
+			match, because = n.MatchBecause("a.tmp", false)
+			assert.True(t, match)
+			assert.Equal(t, "", because.Tag)
+
+			n.DisableTag("builtin")
+			assert.True(t, n.TagDisabled("builtin"))
+			assert.False(t, n.Match("node_modules/a.js", false))
+			assert.False(t, n.Match("debug.log", false))
+			assert.True(t, n.Match("a.tmp", false))
+
+			n.EnableTag("builtin")
+			assert.False(t, n.TagDisabled("builtin"))
+			assert.True(t, n.Match("node_modules/a.js", false))
+			assert.True(t, n.Match("debug.log", false))
+		})
+	}
+}
+
+func TestNoGo_DisableTag_overridden(t *testing.T) {
+	n := New()
+	n.AddRulesTagged("builtin", MustCompileAll("", []byte("*.log"))...)
+	n.AddRulesTagged("user", MustCompileAll("", []byte("!keep.log"))...)
+
+	assert.False(t, n.Match("keep.log", false))
+	n.DisableTag("user")
+	assert.True(t, n.Match("keep.log", false))
+
+	data, err := n.MarshalBinary()
+	assert.NoError(t, err)
+	loaded := New()
+	assert.NoError(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, n.Groups(), loaded.Groups())
+
+	derived := n.Rebase("sub")
+	assert.True(t, derived.TagDisabled("user"))
+
+	groups := n.Groups()
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "builtin", groups[0].Tag)
+		assert.Equal(t, "user", groups[1].Tag)
+	}
+}
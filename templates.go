@@ -0,0 +1,56 @@
+package nogo
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./internal/gentemplates
+
+// templateFS contains a copy of some templates of https://github.com/github/gitignore.
+//
+//go:embed templates/*.gitignore
+var templateFS embed.FS
+
+// Templates returns the names of all embedded .gitignore templates, e.g. "Go" or "Node".
+func Templates() []string {
+	files, _ := fs.Glob(templateFS, "templates/*.gitignore")
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".gitignore"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Template returns the content of the .gitignore template of github/gitignore
+// with the name, e.g. "Go" or "Node". The name is not case-sensitive.
+// The templates are embedded, so no network access is needed.
+// It can be written to a .gitignore file or compiled using CompileAll.
+// errors.Is(err, fs.ErrNotExist) is true if there is no such template.
+func Template(name string) ([]byte, error) {
+	for _, template := range Templates() {
+		if strings.EqualFold(template, name) {
+			return templateFS.ReadFile("templates/" + template + ".gitignore")
+		}
+	}
+	return nil, fmt.Errorf("nogo: unknown template %q: %w", name, fs.ErrNotExist)
+}
+
+// TemplateRules returns the compiled rules of the template with the name,
+// e.g. to seed a matcher using WithRules:
+//
+//	rules, err := nogo.TemplateRules("Go")
+//	...
+//	n := nogo.New(nogo.DotGitRule, nogo.WithRules(rules...))
+func TemplateRules(name string) ([]Rule, error) {
+	data, err := Template(name)
+	if err != nil {
+		return nil, err
+	}
+	return CompileAll("", data)
+}
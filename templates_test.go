@@ -0,0 +1,72 @@
+package nogo
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates(t *testing.T) {
+	assert.Equal(t, []string{"C", "Go", "Java", "Node", "Python", "Rust"}, Templates())
+}
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Go", want: "go.work"},
+		{name: "go", want: "go.work"},
+		{name: "Node", want: "node_modules/"},
+		{name: "Cobol", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Template(tt.name)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, fs.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, string(got), tt.want)
+		})
+	}
+}
+
+func TestTemplatesCompile(t *testing.T) {
+	for _, name := range Templates() {
+		t.Run(name, func(t *testing.T) {
+			rules, err := TemplateRules(name)
+			require.NoError(t, err)
+			assert.NotEmpty(t, rules)
+		})
+	}
+}
+
+func TestTemplateRules(t *testing.T) {
+	rules, err := TemplateRules("Go")
+	require.NoError(t, err)
+
+	n := New(WithRules(rules...))
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "app.exe", want: true},
+		{path: "pkg/pkg.test", want: true},
+		{path: "go.work", want: true},
+		{path: "main.go", want: false},
+		{path: "vendor/mod.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.Match(tt.path, false))
+		})
+	}
+
+	_, err = TemplateRules("Cobol")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
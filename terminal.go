@@ -0,0 +1,191 @@
+package nogo
+
+import (
+	"strings"
+)
+
+// terminalRules contains rules which ignore a path and everything inside of it,
+// e.g. "/build", "build/**" or "node_modules". Paths matching them can be ignored
+// without evaluating any other rule.
+//
+// A rule is only terminal if no negated rule may re-include a path matched by it.
+// As nothing inside of an excluded directory can be re-included, only negated
+// rules matching the path itself or a parent have to be checked for rules
+// like "/build". For the other rules, negated rules matching anything inside
+// of them are checked, too.
+type terminalRules struct {
+	// paths contains the full paths of anchored rules.
+	// The value is false if only paths inside of it are ignored (e.g. "build/**").
+	paths map[string]bool
+
+	// names contains the prefixes of unanchored rules by their name.
+	// They ignore each path with that name below the prefix.
+	names map[string][]string
+}
+
+// terminalRule describes the paths which may be matched by a rule.
+type terminalRule struct {
+	// path is the literal start of all matched paths.
+	path string
+	// literal is true if the rule only consists of literals.
+	literal bool
+	// self is false if only paths inside of path are matched.
+	self bool
+	// unanchored rules match at any level below the path.
+	unanchored bool
+	name       string
+}
+
+// newTerminalRules returns nil if there are no terminal rules.
+func newTerminalRules(groups []group) *terminalRules {
+	var negated []terminalRule
+	var candidates []terminalRule
+	for _, g := range groups {
+		for _, rule := range g.rules {
+			t := analyzeTerminalRule(rule)
+			if rule.Negate {
+				negated = append(negated, t)
+				continue
+			}
+
+			if t.literal && !rule.OnlyFolder {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+
+	var t *terminalRules
+	for _, candidate := range candidates {
+		if overlapsAny(candidate, negated) {
+			continue
+		}
+
+		if t == nil {
+			t = &terminalRules{
+				paths: make(map[string]bool),
+				names: make(map[string][]string),
+			}
+		}
+
+		if candidate.unanchored {
+			t.names[candidate.name] = append(t.names[candidate.name], candidate.path)
+		} else {
+			t.paths[candidate.path] = t.paths[candidate.path] || candidate.self
+		}
+	}
+
+	return t
+}
+
+// analyzeTerminalRule calculates the literal path at the start of the rule.
+// literal is only true for rules which are either a literal path, a literal
+// path followed by "/**" or an unanchored literal name.
+func analyzeTerminalRule(rule Rule) terminalRule {
+	primitives := rule.Matchers()
+	if primitives == nil {
+		// Rules which only consist of regexps may match anything.
+		return terminalRule{unanchored: true}
+	}
+	if pattern, _, _ := cleanPattern(rule.Pattern); hasEmptySegment(pattern) {
+		// Rules with empty segments (e.g. "a//b") never match anything.
+		return terminalRule{unanchored: true}
+	}
+
+	t := terminalRule{path: strings.TrimSuffix(rule.Prefix, "/")}
+	if rule.Prefix != "" && len(primitives) >= 2 {
+		// Skip the prefix and its separator.
+		primitives = primitives[2:]
+	}
+
+	if len(primitives) >= 2 && primitives[0].Kind == PrimitiveDoubleStar && primitives[1].Kind == PrimitiveSeparator {
+		t.unanchored = true
+		primitives = primitives[2:]
+		if len(primitives) == 1 && primitives[0].Kind == PrimitiveLiteral {
+			t.literal = true
+			t.name = primitives[0].Value
+		}
+		return t
+	}
+
+	t.literal = true
+	t.self = true
+	// segment is only added to the path if it is a whole segment.
+	var segment string
+	for i, p := range primitives {
+		switch {
+		case p.Kind == PrimitiveLiteral:
+			segment += p.Value
+		case p.Kind == PrimitiveSeparator && segment == "":
+			// Empty segments (e.g. of "\\/a") never match anything.
+			return terminalRule{unanchored: true}
+		case p.Kind == PrimitiveSeparator:
+			t.path = joinPrefix(t.path, segment)
+			segment = ""
+		case p.Kind == PrimitiveDoubleStar && i == len(primitives)-1 && i > 0:
+			// A trailing "/**" matches everything inside.
+			t.self = false
+		default:
+			t.literal = false
+			t.self = false
+			return t
+		}
+	}
+	if segment != "" {
+		t.path = joinPrefix(t.path, segment)
+	}
+	return t
+}
+
+// overlapsAny checks if any of the rules may match the path of the terminal rule
+// or a parent of it. If the terminal rule doesn't exclude its path itself,
+// anything inside of it is checked, too.
+func overlapsAny(terminal terminalRule, rules []terminalRule) bool {
+	excludesSelf := terminal.self && !terminal.unanchored
+	for _, rule := range rules {
+		if isPathInside(terminal.path, rule.path) {
+			return true
+		}
+		if !excludesSelf && isPathInside(rule.path, terminal.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathInside checks if the path is the same as the parent or inside of it.
+func isPathInside(path, parent string) bool {
+	return parent == "" || path == parent || strings.HasPrefix(path, parent+"/")
+}
+
+func joinPrefix(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return prefix + "/" + path
+}
+
+// match checks if the path is ignored by any terminal rule.
+func (t *terminalRules) match(path string) bool {
+	// The other rules ignore trailing slashes, too.
+	path = strings.TrimRight(path, "/")
+
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) && path[i] != '/' {
+			continue
+		}
+
+		current := path[:i]
+		inside := i < len(path)
+		if self, ok := t.paths[current]; ok && (self || inside) {
+			return true
+		}
+
+		start := strings.LastIndexByte(current, '/') + 1
+		for _, prefix := range t.names[current[start:]] {
+			if prefix == "" || strings.HasPrefix(current[:start], prefix+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package nogo
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeTerminalRule(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		pattern string
+		want    terminalRule
+	}{
+		{pattern: "/build", want: terminalRule{path: "build", literal: true, self: true}},
+		{pattern: "a/build", want: terminalRule{path: "a/build", literal: true, self: true}},
+		{prefix: "sub", pattern: "/build", want: terminalRule{path: "sub/build", literal: true, self: true}},
+		{pattern: "build/**", want: terminalRule{path: "build", literal: true}},
+		{pattern: "node_modules", want: terminalRule{literal: true, unanchored: true, name: "node_modules"}},
+		{prefix: "sub", pattern: "node_modules", want: terminalRule{path: "sub", literal: true, unanchored: true, name: "node_modules"}},
+		{pattern: "**/node_modules", want: terminalRule{literal: true, unanchored: true, name: "node_modules"}},
+		{pattern: "*.log", want: terminalRule{unanchored: true}},
+		{pattern: "a/b*", want: terminalRule{path: "a"}},
+		{pattern: "a/**/b", want: terminalRule{path: "a"}},
+		{prefix: "sub", pattern: "a*/b", want: terminalRule{path: "sub"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+"|"+tt.pattern, func(t *testing.T) {
+			_, rule, err := Compile(tt.prefix, tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, analyzeTerminalRule(rule))
+		})
+	}
+}
+
+func TestNewTerminalRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  *terminalRules
+	}{
+		{
+			name:  "no terminal rules",
+			rules: "*.log\nbuild/",
+			want:  nil,
+		},
+		{
+			name:  "terminal rules",
+			rules: "/build\nnode_modules\nout/**\n*.log",
+			want: &terminalRules{
+				paths: map[string]bool{"build": true, "out": false},
+				names: map[string][]string{"node_modules": {""}},
+			},
+		},
+		{
+			name:  "negation inside",
+			rules: "/build\n/out\n!/build/keep",
+			want: &terminalRules{
+				paths: map[string]bool{"build": true, "out": true},
+				names: map[string][]string{},
+			},
+		},
+		{
+			name:  "negation inside of a not excluded path",
+			rules: "/build\nout/**\nnode_modules\n!/out/keep\n!/node_modules/keep",
+			want: &terminalRules{
+				paths: map[string]bool{"build": true},
+				names: map[string][]string{},
+			},
+		},
+		{
+			name:  "negation of a parent",
+			rules: "/a/build\n/out\n!/a",
+			want: &terminalRules{
+				paths: map[string]bool{"out": true},
+				names: map[string][]string{},
+			},
+		},
+		{
+			name:  "unanchored negation",
+			rules: "/build\n!*.keep",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New()
+			n.AddRules(MustCompileAll("", []byte(tt.rules))...)
+			assert.Equal(t, tt.want, n.terminals)
+		})
+	}
+}
+
+func TestTerminalRules_match(t *testing.T) {
+	n := New()
+	n.AddRules(MustCompileAll("", []byte("/build\nout/**\n*.log"))...)
+	n.AddRules(MustCompileAll("sub", []byte("node_modules"))...)
+	require.NotNil(t, n.terminals)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "build", want: true},
+		{path: "build/", want: true},
+		{path: "build/a/b", want: true},
+		{path: "buildx", want: false},
+		{path: "a/build", want: false},
+		{path: "out", want: false},
+		{path: "out/", want: false},
+		{path: "out/a", want: true},
+		{path: "sub/node_modules", want: true},
+		{path: "sub/a/node_modules/b", want: true},
+		{path: "node_modules", want: false},
+		{path: "subx/node_modules", want: false},
+		// Only checked by the other rules.
+		{path: "a.log", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.terminals.match(tt.path))
+
+			// The result must be the same as without the terminal rules.
+			match, _ := n.MatchBecause(tt.path, false)
+			assert.Equal(t, match || tt.want, n.Match(tt.path, false))
+			if tt.want {
+				assert.True(t, match)
+			}
+		})
+	}
+}
+
+func TestNoGo_Match_Terminal(t *testing.T) {
+	// Match uses the terminal rules, MatchBecause doesn't.
+	// Both must always return the same.
+	n := New()
+	n.AddRules(TestFSGroups[0].rules...)
+	for _, g := range TestFSGroups[1:] {
+		n.groups = append(n.groups, g)
+	}
+	n.changed()
+
+	for path, tt := range TestFSData {
+		match, _ := n.MatchBecause(path, tt.isDir)
+		assert.Equal(t, match, n.Match(path, tt.isDir), path)
+	}
+
+	for _, c := range append(Corpus(), Regressions()...) {
+		n := New()
+		n.AddRules(MustCompileAll("", []byte(c.Pattern))...)
+		match, _ := n.MatchBecause(c.Path, c.IsDir)
+		assert.Equal(t, match, n.Match(c.Path, c.IsDir), c.Pattern+"|"+c.Path)
+	}
+}
+
+func TestNoGo_Match_Terminal_random(t *testing.T) {
+	// Random rules and paths, as the terminal rules only support some kinds of patterns.
+	names := []string{"a", "b", "ab", "build", "x.log"}
+	forms := []string{"%", "/%", "%/", "%/**", "/%/**", "**/%", "/**/%", "%/**/%", "/%/%", "!%", "!/%", "*.log", "%*"}
+	random := rand.New(rand.NewSource(1))
+	name := func() string {
+		return names[random.Intn(len(names))]
+	}
+
+	for i := 0; i < 1000; i++ {
+		var rules []string
+		for j := random.Intn(4) + 1; j > 0; j-- {
+			rule := forms[random.Intn(len(forms))]
+			for strings.Contains(rule, "%") {
+				rule = strings.Replace(rule, "%", name(), 1)
+			}
+			rules = append(rules, rule)
+		}
+		n := New()
+		n.AddRules(MustCompileAll("", []byte(strings.Join(rules, "\n")))...)
+
+		for j := 0; j < 10; j++ {
+			segments := make([]string, random.Intn(4)+1)
+			for k := range segments {
+				segments[k] = name()
+			}
+			path := strings.Join(segments, "/")
+			isDir := random.Intn(2) == 0
+
+			match, _ := n.MatchBecause(path, isDir)
+			assert.Equal(t, match, n.Match(path, isDir), "rules %q, path %q", rules, path)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package nogo
+
+import "strings"
+
+// PatternMeta describes a pattern apart from its regexp.
+type PatternMeta struct {
+	// Skip is true if the line doesn't contain a pattern, e.g. a comment.
+	Skip bool
+
+	// Negate is true for patterns starting with '!'.
+	Negate bool
+
+	// OnlyFolder is true for patterns ending with '/', which only match folders.
+	OnlyFolder bool
+
+	// Anchored is true if the pattern contains a '/' at the beginning or in
+	// the middle. It only matches relative to the folder of the ignore file then.
+	// Otherwise it matches at any depth.
+	Anchored bool
+}
+
+// Translate converts a single line of an ignore file to the source of the
+// regexp nogo uses to match it, e.g. for syntax highlighters or linters which
+// don't need a whole Rule.
+//
+// The regexp matches paths relative to the folder of the ignore file and,
+// like Rule.Regexp, expects them to be converted using ByteRunes.
+// regexSrc is empty if meta.Skip is true.
+// If the pattern is invalid, a *PatternError is returned.
+func Translate(pattern string) (regexSrc string, meta PatternMeta, err error) {
+	skip, rule, err := Compile("", pattern)
+	if err != nil {
+		return "", PatternMeta{}, err
+	}
+	if skip {
+		return "", PatternMeta{Skip: true}, nil
+	}
+
+	cleaned, _, _ := cleanPattern(pattern)
+	meta = PatternMeta{
+		Negate:     rule.Negate,
+		OnlyFolder: rule.OnlyFolder,
+		Anchored:   strings.Contains(strings.TrimSuffix(cleaned, "/"), "/"),
+	}
+	return rule.Regexp[0].String(), meta, nil
+}
@@ -0,0 +1,42 @@
+package nogo
+
+import (
+	"regexp/syntax"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		wantRegex string
+		wantMeta  PatternMeta
+	}{
+		{pattern: "*.log", wantRegex: `^(.*/)?[^/]*\.log$`},
+		{pattern: "/build", wantRegex: `^build$`, wantMeta: PatternMeta{Anchored: true}},
+		{pattern: "doc/", wantRegex: `^(.*/)?doc$`, wantMeta: PatternMeta{OnlyFolder: true}},
+		{pattern: "!a/b/", wantRegex: `^a/b$`, wantMeta: PatternMeta{Negate: true, OnlyFolder: true, Anchored: true}},
+		{pattern: "a/**/b", wantRegex: `^a/(.*/)?b$`, wantMeta: PatternMeta{Anchored: true}},
+		{pattern: "file[!a-z]", wantRegex: `^(.*/)?file[^/a-z]$`},
+		{pattern: "# comment", wantMeta: PatternMeta{Skip: true}},
+		{pattern: "   ", wantMeta: PatternMeta{Skip: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			regexSrc, meta, err := Translate(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRegex, regexSrc)
+			assert.Equal(t, tt.wantMeta, meta)
+		})
+	}
+}
+
+func TestTranslate_Invalid(t *testing.T) {
+	_, _, err := Translate("file[a-z")
+	var patternErr *PatternError
+	require.ErrorAs(t, err, &patternErr)
+	var syntaxErr *syntax.Error
+	assert.ErrorAs(t, err, &syntaxErr)
+}
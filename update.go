@@ -0,0 +1,74 @@
+package nogo
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// UpdateFile replaces the rules loaded from the ignore file at path by the
+// rules of newContent, e.g. to keep the matcher of an editor in sync with the
+// unsaved buffer of an ignore file. Only that file is compiled again and all
+// caches are invalidated.
+//
+// Unlike ReplaceGroup, other ignore files of the same folder and rules added
+// by AddRules are kept. The rules keep their precedence. If the file was not
+// loaded before, it is added like by AddFile.
+//
+// The "#!include:" lines of DialectGcloudignore are resolved using the
+// file system of WithFS. Without it, they are treated as comments.
+//
+// With WithLazyLoading, the ignore files of the folder are loaded first, so
+// they are not loaded again later and overwrite the update.
+//
+// If newContent contains an invalid pattern, a *PatternError is returned and
+// the old rules are kept.
+func (n *NoGo) UpdateFile(filePath string, newContent []byte) error {
+	filePath = path.Clean(filepath.ToSlash(filePath))
+	n.lock(filePath)
+	defer n.unlock()
+	start := time.Now()
+
+	data := newContent
+	if n.loadFS != nil {
+		var err error
+		data, err = n.dialect.resolveIncludes(n.loadFS, filePath, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	folder := path.Dir(filePath)
+	if folder == "." {
+		folder = ""
+	}
+
+	rules, err := n.dialect.CompileAll(folder, data)
+	if err != nil {
+		return patternErrorAt(err, filePath, 0)
+	}
+
+	updated := group{
+		prefix: folder,
+		rules:  rules,
+		source: filePath,
+	}
+
+	replaced := false
+	for i, g := range n.groups {
+		if g.source == filePath {
+			// The rules keep their tag, so DisableTag still applies to them.
+			updated.tag = g.tag
+			n.groups[i] = updated
+			n.logRules(updated)
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		n.addGroup(updated)
+	}
+	n.loaded(filePath, start)
+	n.changed()
+	return nil
+}
@@ -0,0 +1,92 @@
+package nogo
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_UpdateFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n")},
+		".dockerignore":  {Data: []byte("/build\n")},
+		"sub/.gitignore": {Data: []byte("!keep.log\n")},
+	}
+	n := New(WithCache(10))
+	require.NoError(t, n.AddFile(fsys, ".gitignore"))
+	require.NoError(t, n.AddFile(fsys, ".dockerignore"))
+	n.AddRules(DotGitRule)
+	require.NoError(t, n.AddFile(fsys, "sub/.gitignore"))
+
+	assert.True(t, n.Match("debug.log", false))
+	assert.True(t, n.Match("build", true))
+	assert.False(t, n.Match("sub/keep.log", false))
+
+	require.NoError(t, n.UpdateFile(".gitignore", []byte("*.txt\n")))
+	assert.False(t, n.Match("debug.log", false), "the cache is invalidated")
+	assert.True(t, n.Match("a.txt", false))
+
+	// Other ignore files and rules of the same folder are kept.
+	assert.True(t, n.Match("build", true))
+	assert.True(t, n.Match(".git", true))
+
+	// The rules keep their precedence.
+	require.NoError(t, n.UpdateFile("sub/.gitignore", []byte("!keep.txt\n")))
+	assert.False(t, n.Match("sub/keep.txt", false))
+	assert.Equal(t, []string{".gitignore", ".dockerignore", "", "sub/.gitignore"}, groupSources(n))
+
+	t.Run("new file", func(t *testing.T) {
+		require.NoError(t, n.UpdateFile("other/.gitignore", []byte("/x\n")))
+		assert.True(t, n.Match("other/x", false))
+		assert.Equal(t, "other/.gitignore", groupSources(n)[4])
+	})
+
+	t.Run("tagged file", func(t *testing.T) {
+		n.groups[len(n.groups)-1].tag = "other"
+		require.NoError(t, n.UpdateFile("other/.gitignore", []byte("/y\n")))
+		_, because := n.MatchBecause("other/y", false)
+		assert.Equal(t, "other", because.Tag)
+
+		n.DisableTag("other")
+		assert.False(t, n.Match("other/y", false))
+		n.EnableTag("other")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		err := n.UpdateFile("sub/.gitignore", []byte("ok\n[a-z\n"))
+		var patternErr *PatternError
+		require.True(t, errors.As(err, &patternErr))
+		assert.Equal(t, "sub/.gitignore", patternErr.File)
+		assert.Equal(t, 2, patternErr.Line)
+
+		// The old rules are kept.
+		assert.False(t, n.Match("sub/keep.txt", false))
+		assert.False(t, n.Match("sub/ok", false))
+	})
+}
+
+func TestNoGo_UpdateFile_LazyLoading(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n")},
+		"sub/.gitignore": {Data: []byte("/out\n")},
+	}
+	n, err := ForFS(fsys, ".gitignore", WithLazyLoading())
+	require.NoError(t, err)
+
+	// The file on disk must not overwrite the update when sub is loaded.
+	require.NoError(t, n.UpdateFile("sub/.gitignore", []byte("/new\n")))
+	assert.False(t, n.Match("sub/out", false))
+	assert.True(t, n.Match("sub/new", false))
+	assert.True(t, n.Match("sub/a.log", false))
+}
+
+func groupSources(n *NoGo) []string {
+	var sources []string
+	for _, g := range n.Groups() {
+		sources = append(sources, g.Source)
+	}
+	return sources
+}
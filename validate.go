@@ -0,0 +1,85 @@
+package nogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InvalidPathError is returned by ValidatePath and MatchErr for paths
+// which can't be matched correctly.
+type InvalidPathError struct {
+	Path   string
+	Reason string
+}
+
+func (e *InvalidPathError) Error() string {
+	return fmt.Sprintf("nogo: invalid path %q: %s", e.Path, e.Reason)
+}
+
+// ValidatePath checks if the path has the form expected by NoGo:
+// It has to be relative to the root, use '/' as separator and must not
+// contain empty, "." or ".." elements. The root itself is ".".
+//
+// A path which is not valid is returned as *InvalidPathError.
+func ValidatePath(path string) error {
+	if path == "" {
+		return &InvalidPathError{Path: path, Reason: "empty path"}
+	}
+	if path == "." {
+		return nil
+	}
+	if strings.Contains(path, `\`) {
+		return &InvalidPathError{Path: path, Reason: `contains '\', use '/' as separator or NormalizeWindowsPath`}
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		switch segment {
+		case "":
+			return &InvalidPathError{Path: path, Reason: "empty path element"}
+		case ".", "..":
+			return &InvalidPathError{Path: path, Reason: fmt.Sprintf("contains %q element", segment)}
+		}
+	}
+	return nil
+}
+
+// WithValidatePaths enables the validation of all matched paths using ValidatePath.
+// By default invalid paths are matched as they are, which may silently give
+// wrong results, e.g. for "a\\b" or "a//b".
+//
+// As Match, MatchBecause and MatchWithoutParents can't return an error, invalid
+// paths are never ignored by them. Use MatchErr to get the *InvalidPathError
+// instead, e.g. to surface bugs of callers in tests and CI.
+func WithValidatePaths() Option {
+	return optionFunc(func(n *NoGo) {
+		n.validatePaths = true
+	})
+}
+
+// MatchErr does the same as Match but returns an *InvalidPathError if the path
+// is not valid (see WithValidatePaths and WithWindowsPaths).
+func (n *NoGo) MatchErr(path string, isDir bool) (bool, error) {
+	normalized, err := n.normalizeErr(path)
+	if err != nil {
+		return false, err
+	}
+	if n.validatePaths {
+		if err := ValidatePath(normalized); err != nil {
+			return false, err
+		}
+	}
+	return n.Match(path, isDir), nil
+}
+
+// NormalizePath returns the path the same way it is matched, e.g. normalized
+// using NormalizeWindowsPath if WithWindowsPaths is used.
+// It returns the *InvalidPathError which MatchErr would return for the path.
+// The path is not validated using ValidatePath.
+func (n *NoGo) NormalizePath(path string) (string, error) {
+	return n.normalizeErr(path)
+}
+
+// valid checks the normalized path if WithValidatePaths is used.
+func (n *NoGo) valid(path string) bool {
+	return !n.validatePaths || ValidatePath(path) == nil
+}
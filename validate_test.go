@@ -0,0 +1,85 @@
+package nogo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: ".", wantErr: false},
+		{path: "a", wantErr: false},
+		{path: "a/b/c.txt", wantErr: false},
+		{path: "a b/.c", wantErr: false},
+		{path: "", wantErr: true},
+		{path: `a\b`, wantErr: true},
+		{path: "a//b", wantErr: true},
+		{path: "/a", wantErr: true},
+		{path: "a/", wantErr: true},
+		{path: "a/./b", wantErr: true},
+		{path: "../a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := ValidatePath(tt.path)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			var pathErr *InvalidPathError
+			require.True(t, errors.As(err, &pathErr))
+			assert.Equal(t, tt.path, pathErr.Path)
+		})
+	}
+}
+
+func TestWithValidatePaths(t *testing.T) {
+	rules := MustCompileAll("", []byte("/build\n*.log"))
+
+	t.Run("lenient by default", func(t *testing.T) {
+		n := New()
+		n.AddRules(rules...)
+		assert.NotPanics(t, func() { n.Match(`build\a`, false) })
+
+		match, err := n.MatchErr("a//b.log", false)
+		assert.NoError(t, err)
+		assert.True(t, match)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		n := New(WithValidatePaths())
+		n.AddRules(rules...)
+
+		match, err := n.MatchErr("build/a", false)
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		for _, path := range []string{"", `build\a`, "a//b.log", "/build"} {
+			_, err := n.MatchErr(path, false)
+			var pathErr *InvalidPathError
+			assert.True(t, errors.As(err, &pathErr), path)
+
+			// Invalid paths are never ignored.
+			assert.False(t, n.Match(path, false), path)
+			match, because := n.MatchBecause(path, false)
+			assert.False(t, match, path)
+			assert.False(t, because.Found, path)
+			match, _ = n.MatchWithoutParents(path, false)
+			assert.False(t, match, path)
+			matches, results := n.MatchAll([]string{path})
+			assert.Equal(t, []bool{false}, matches, path)
+			assert.Equal(t, []Result{{}}, results, path)
+		}
+
+		// Directories end with a '/' in MatchAll.
+		matches, _ := n.MatchAll([]string{"build/"})
+		assert.True(t, matches[0])
+	})
+}
@@ -33,6 +33,7 @@ func (n *NoGo) WalkFunc(fsys fs.FS, path string, isDir bool, err error) (bool, e
 
 	if path != "." {
 		if match, _ := n.MatchWithoutParents(path, isDir); match {
+			n.report(ProgressEvent{Kind: ProgressSkipped, Path: path, IsDir: isDir})
 			if isDir {
 				return false, fs.SkipDir
 			}
@@ -49,9 +50,17 @@ func (n *NoGo) WalkFunc(fsys fs.FS, path string, isDir bool, err error) (bool, e
 //
 // You have to call AddFromFS with the same fs before running the walk!
 //
+// Like git, fs.WalkDir does not follow symlinks, so they are matched as files,
+// even if they point to a directory. Use NoGo.WalkDir with WithFollowSymlinks
+// to follow them.
+//
 // If you need something similar for any other Walk function (e.g. afero.Walk)
 // You can use WalkFunc for that.
 //
+// Errors of fs.WalkDir (e.g. if a directory can't be read) are passed to fn,
+// like fs.WalkDir does it. The errors returned by fn, including fs.SkipDir
+// and fs.SkipAll, are returned to fs.WalkDir unchanged.
+//
 // Example:
 //  if err := n.AddFromFS(walkFS, ".gitignore"); err != nil {
 //		panic(err)
@@ -67,15 +76,103 @@ func (n *NoGo) WalkFunc(fsys fs.FS, path string, isDir bool, err error) (bool, e
 //	}))
 func (n *NoGo) ForWalkDir(fsys fs.FS, root string, fn fs.WalkDirFunc) (fs.FS, string, fs.WalkDirFunc) {
 	return fsys, root, func(path string, d fs.DirEntry, err error) error {
-		ok, err := n.WalkFunc(fsys, path, d.IsDir(), err)
 		if err != nil {
+			// d is nil if the root can't be read.
+			return fn(path, d, err)
+		}
+
+		if ok, err := n.WalkFunc(fsys, path, d.IsDir(), nil); !ok {
 			return err
 		}
+		return fn(path, d, nil)
+	}
+}
+
+// ForWalkDirStrict does the same as ForWalkDir but doesn't match paths which
+// can't be matched correctly, e.g. file names containing a '\' (see ValidatePath)
+// or absolute Windows paths if WithWindowsPaths is used.
+// Instead, fn gets called with the path and an *InvalidPathError.
+// If fn returns nil, the path is skipped, as it is unknown whether it is ignored.
+func (n *NoGo) ForWalkDirStrict(fsys fs.FS, root string, fn fs.WalkDirFunc) (fs.FS, string, fs.WalkDirFunc) {
+	return fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(path, d, err)
+		}
+
+		if path != "." {
+			match, err := n.matchStrict(path, d.IsDir())
+			if err != nil {
+				if err := fn(path, d, err); err != nil {
+					return err
+				}
+				match = true
+			}
+
+			if match {
+				n.report(ProgressEvent{Kind: ProgressSkipped, Path: path, IsDir: d.IsDir()})
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+
+		return fn(path, d, nil)
+	}
+}
 
-		if ok {
+// ForWalkDirPrecise does the same as ForWalkDir but uses MatchDir to decide
+// whether to descend into a directory. Ignored directories are still walked
+// if a later rule may include something inside of them, which is possible for
+// allow lists (see NewAllowList), and only the included paths are passed to fn.
+// Directories which are not ignored but all of their content is (e.g. "dist"
+// for the rule "dist/**") are passed to fn but not read.
+//
+// Like git, nothing inside of a directory excluded by gitignore rules can be
+// included again. So "dist/" followed by "!dist/keep.txt" still ignores
+// "dist/keep.txt", but "dist/*" followed by "!dist/keep.txt" does not.
+func (n *NoGo) ForWalkDirPrecise(fsys fs.FS, root string, fn fs.WalkDirFunc) (fs.FS, string, fs.WalkDirFunc) {
+	return fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == "." {
 			return fn(path, d, err)
 		}
 
-		return nil
+		if !d.IsDir() {
+			if n.Match(path, false) {
+				n.report(ProgressEvent{Kind: ProgressSkipped, Path: path})
+				return nil
+			}
+			return fn(path, d, nil)
+		}
+
+		ignored, mayContainUnignored := n.MatchDir(path)
+		if ignored {
+			if mayContainUnignored {
+				// Walk the directory without passing it to fn.
+				return nil
+			}
+			n.report(ProgressEvent{Kind: ProgressSkipped, Path: path, IsDir: true})
+			return fs.SkipDir
+		}
+
+		if err := fn(path, d, nil); err != nil || mayContainUnignored {
+			return err
+		}
+		return fs.SkipDir
 	}
 }
+
+// matchStrict does the same as MatchWithoutParents but returns an
+// *InvalidPathError for paths which are not valid.
+func (n *NoGo) matchStrict(path string, isDir bool) (bool, error) {
+	normalized, err := n.normalizeErr(path)
+	if err != nil {
+		return false, err
+	}
+	if err := ValidatePath(normalized); err != nil {
+		return false, err
+	}
+
+	match, _ := n.MatchWithoutParents(path, isDir)
+	return match, nil
+}
@@ -10,6 +10,7 @@ import (
 	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNoGo_WalkFunc(t *testing.T) {
@@ -327,3 +328,137 @@ func TestNoGo_AddFromFS_ignored_nested_files(t *testing.T) {
 		})
 	}
 }
+
+func TestNoGo_ForWalkDir(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	t.Run("SkipAll", func(t *testing.T) {
+		var got []string
+		err := fs.WalkDir(n.ForWalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			got = append(got, path)
+			if path == "main.go" {
+				return fs.SkipAll
+			}
+			return nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{".", ".gitignore", "main.go"}, got)
+	})
+
+	t.Run("SkipDir", func(t *testing.T) {
+		var got []string
+		err := fs.WalkDir(n.ForWalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			got = append(got, path)
+			if path == "sub" {
+				return fs.SkipDir
+			}
+			return nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{".", ".gitignore", "main.go", "sub"}, got)
+	})
+
+	t.Run("errors are passed to fn", func(t *testing.T) {
+		var gotErr error
+		err := fs.WalkDir(n.ForWalkDir(fsys, "missing", func(path string, d fs.DirEntry, err error) error {
+			assert.Nil(t, d)
+			gotErr = err
+			return nil
+		}))
+		assert.NoError(t, err)
+		assert.ErrorIs(t, gotErr, fs.ErrNotExist)
+	})
+}
+
+func TestNoGo_ForWalkDirStrict(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte("*.log")},
+		`a\b.log`:    {},
+		`dir\x/c.go`: {},
+		"main.go":    {},
+		"debug.log":  {},
+	}
+	n := newWalkTestNoGo(t, fsys)
+
+	var got []string
+	var invalid []string
+	err := fs.WalkDir(n.ForWalkDirStrict(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		var pathErr *InvalidPathError
+		if errors.As(err, &pathErr) {
+			invalid = append(invalid, path)
+			return nil
+		}
+		require.NoError(t, err)
+		got = append(got, path)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitignore", "main.go"}, got)
+	assert.Equal(t, []string{`a\b.log`, `dir\x`}, invalid)
+
+	t.Run("stop", func(t *testing.T) {
+		err := fs.WalkDir(n.ForWalkDirStrict(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			return err
+		}))
+		var pathErr *InvalidPathError
+		assert.True(t, errors.As(err, &pathErr))
+	})
+
+	t.Run("windows paths", func(t *testing.T) {
+		n := New(WithWindowsPaths())
+		n.AddRules(MustCompileAll("", []byte("*.log"))...)
+
+		var got []string
+		err := fs.WalkDir(n.ForWalkDirStrict(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got = append(got, path)
+			return nil
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{".", ".gitignore", `dir\x`, `dir\x/c.go`, "main.go"}, got)
+	})
+}
+
+func TestNoGo_ForWalkDirPrecise(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go":          {},
+		"src/testdata/keep":    {},
+		"src/testdata/other":   {},
+		"src/generated/gen.go": {},
+		"dist/keep.txt":        {},
+		"dist/app.js":          {},
+		"out/app.js":           {},
+		"vendor/keep.txt":      {},
+		"vendor/lib.go":        {},
+		"README.md":            {},
+	}
+
+	walk := func(n *NoGo) []string {
+		var got []string
+		err := fs.WalkDir(n.ForWalkDirPrecise(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got = append(got, path)
+			return nil
+		}))
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("allow list", func(t *testing.T) {
+		n := NewAllowList()
+		n.AddRules(MustCompileAll("", []byte("/src\n!/src/testdata\n/src/testdata/keep\n!/src/generated\n"))...)
+
+		// The excluded directory itself is not passed to fn, but the included file inside of it.
+		assert.Equal(t, []string{".", "src", "src/main.go", "src/testdata/keep"}, walk(n))
+	})
+
+	t.Run("gitignore", func(t *testing.T) {
+		n := New()
+		n.AddRules(MustCompileAll("", []byte("dist/\n!dist/keep.txt\nout/**\nvendor/*\n!vendor/keep.txt\n"))...)
+
+		// Like git, dist/keep.txt can't be included again, as dist is excluded.
+		// out is not ignored itself, but it isn't read, as everything inside of it is ignored.
+		assert.Equal(t, []string{".", "README.md", "out", "src", "src/generated", "src/generated/gen.go", "src/main.go", "src/testdata", "src/testdata/keep", "src/testdata/other", "vendor", "vendor/keep.txt"}, walk(n))
+	})
+}
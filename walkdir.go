@@ -0,0 +1,546 @@
+package nogo
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirStats contains aggregated information about the direct children of a directory.
+type DirStats struct {
+	// Included is the amount of children which are not ignored.
+	Included int
+	// Ignored is the amount of children which are ignored.
+	Ignored int
+}
+
+// PostDirFunc is called after all children of a directory were walked.
+// If it returns fs.SkipDir, the remaining entries of the parent directory are skipped.
+// Any other error stops the walk.
+type PostDirFunc func(path string, d fs.DirEntry, stats DirStats) error
+
+// WalkOption configures NoGo.WalkDir.
+type WalkOption interface {
+	apply(w *walker)
+}
+
+type walkOptionFunc func(w *walker)
+
+func (f walkOptionFunc) apply(w *walker) {
+	f(w)
+}
+
+// WithPostDir sets a function which is called for each walked directory
+// after all of its children were walked (post-order).
+// It gets the amount of included and ignored direct children of the directory.
+//
+// This is useful e.g. for archive writers or to sum up the size of directories.
+func WithPostDir(fn PostDirFunc) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.post = fn
+	})
+}
+
+// WithIgnoreFile loads the ignore files with the given name lazily while walking.
+// Each ignore file is loaded when its directory is entered, so it is not needed
+// to call AddFromFS before the walk.
+//
+// Only ignore files inside of the walked root are loaded.
+// Errors while loading an ignore file are reported to the WalkDirFunc
+// the same way as errors while reading the directory.
+func WithIgnoreFile(name string) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.ignoreFile = name
+	})
+}
+
+// WithFollowSymlinks follows symlinks to directories while walking.
+// The symlinks are then matched and walked as directories.
+// The fs.DirEntry passed to the WalkDirFunc returns true for IsDir and
+// fs.ModeDir|fs.ModeSymlink as Type.
+//
+// By default symlinks are never followed and always matched as files,
+// the same way as git handles them, even if they point to a directory.
+//
+// Symlinks which point to one of their parent directories are not followed,
+// if the fs.FS returns FileInfos which are supported by os.SameFile (e.g. os.DirFS).
+func WithFollowSymlinks() WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.followSymlinks = true
+	})
+}
+
+// WithSkipGitDir skips the .git directory of each git repository while walking,
+// so DotGitRule is not needed. A .git file, as used by submodules and worktrees,
+// is skipped, too.
+//
+// If the environment variable $GIT_DIR is set to a path relative to the root
+// of the fs.FS, that directory is skipped as well. An absolute $GIT_DIR can't
+// be mapped to the fs.FS and is not skipped.
+func WithSkipGitDir() WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.skipNames(".git")
+	})
+}
+
+// WithSkipVCSDirs does the same as WithSkipGitDir but also skips the directories
+// of Mercurial (.hg), Subversion (.svn) and Bazaar (.bzr).
+func WithSkipVCSDirs() WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.skipNames(".git", ".hg", ".svn", ".bzr")
+	})
+}
+
+// WithMaxDepth limits the depth of the walk, like "find -maxdepth". The root
+// has the depth 0 and its entries the depth 1. So with a depth of 1 only the
+// entries of the root are walked. A negative depth doesn't limit the walk.
+//
+// Directories at the maximum depth are still passed to the WalkDirFunc, but
+// they are not read. So the PostDirFunc gets empty DirStats for them.
+func WithMaxDepth(depth int) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.maxDepth = depth
+	})
+}
+
+// WithPruneDirs skips all directories which match one of the gitignore
+// patterns, even if they are not ignored by the rules of NoGo, e.g. to never
+// walk huge directories like "node_modules" or "target/". Like the ignore
+// rules, the patterns are relative to the root of the fs.FS and pruned
+// directories are handled the same way as ignored ones.
+//
+// Only directories are pruned, so "*.go" never skips a file.
+// Invalid patterns are skipped, like git skips lines it can't match.
+func WithPruneDirs(patterns ...string) WalkOption {
+	prune := New()
+	for _, pattern := range patterns {
+		// Each pattern is compiled on its own to skip only the invalid ones.
+		skip, rule, err := Compile("", pattern)
+		if err == nil && !skip {
+			prune.AddRules(rule)
+		}
+	}
+
+	return walkOptionFunc(func(w *walker) {
+		w.prune = prune
+	})
+}
+
+// WithContext stops the walk as soon as the context is done. The error of the
+// context is returned then, regardless of WithErrorPolicy. It is checked
+// before each directory is read, so long walks, e.g. over network mounts,
+// can be cancelled.
+func WithContext(ctx context.Context) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.ctx = ctx
+	})
+}
+
+// LoadEvent describes an ignore file which was loaded while walking.
+type LoadEvent struct {
+	// Path of the ignore file.
+	Path string
+	// Rules is the amount of rules loaded from the file.
+	Rules int
+	// Duration is the time it took to read and compile the file.
+	Duration time.Duration
+	// Err is set if the file could not be loaded.
+	Err error
+}
+
+// LoadFunc is called for each ignore file loaded while walking.
+type LoadFunc func(event LoadEvent)
+
+// WithLoadFunc sets a function which is called whenever an ignore file
+// got loaded by WithIgnoreFile, e.g. to show the progress of the first walk
+// of a big repository. Directories without an ignore file are not reported.
+//
+// The function is called from the goroutine which walks the directory,
+// so with WalkDirConcurrent it may be called concurrently.
+func WithLoadFunc(fn LoadFunc) WalkOption {
+	return walkOptionFunc(func(w *walker) {
+		w.load = fn
+	})
+}
+
+type walker struct {
+	n              *NoGo
+	fsys           fs.FS
+	fn             fs.WalkDirFunc
+	post           PostDirFunc
+	load           LoadFunc
+	ignoreFile     string
+	followSymlinks bool
+
+	// skippedFn is called for each ignored child of a walked directory.
+	skippedFn func(name string, d fs.DirEntry) error
+
+	// skip contains the names of the version control directories which are
+	// skipped and skipGitDir the path of $GIT_DIR.
+	skip       map[string]bool
+	skipGitDir string
+
+	// root is the walked root and maxDepth the depth set by WithMaxDepth.
+	// maxDepth is negative if the depth is not limited.
+	root     string
+	maxDepth int
+
+	// prune is nil if WithPruneDirs is not used.
+	prune *NoGo
+
+	// errs is nil if WithErrorPolicy is not used.
+	errs *walkErrors
+
+	// ctx is nil if WithContext is not used.
+	ctx context.Context
+
+	// mu protects the rules of n if they are loaded while walking.
+	mu sync.RWMutex
+}
+
+func newWalker(n *NoGo, fsys fs.FS, root string, fn fs.WalkDirFunc, opts []WalkOption) *walker {
+	w := &walker{
+		n:        n,
+		fsys:     fsys,
+		fn:       fn,
+		root:     root,
+		maxDepth: -1,
+	}
+	for _, opt := range opts {
+		opt.apply(w)
+	}
+	return w
+}
+
+// skipNames skips all paths with one of the names and $GIT_DIR.
+func (w *walker) skipNames(names ...string) {
+	if w.skip == nil {
+		w.skip = make(map[string]bool)
+	}
+	for _, name := range names {
+		w.skip[name] = true
+	}
+	w.skipGitDir = gitDirPath(os.Getenv("GIT_DIR"))
+}
+
+// gitDirPath converts the value of $GIT_DIR to a path of the fs.FS.
+// It returns "" if it is not set or not inside of the root.
+func gitDirPath(gitDir string) string {
+	if gitDir == "" || filepath.IsAbs(gitDir) {
+		return ""
+	}
+	gitDir = path.Clean(filepath.ToSlash(gitDir))
+	if gitDir == "." || gitDir == ".." || strings.HasPrefix(gitDir, "../") {
+		return ""
+	}
+	return gitDir
+}
+
+// skipped checks if the path is a version control directory skipped
+// by WithSkipGitDir or WithSkipVCSDirs.
+func (w *walker) skipped(name string) bool {
+	if w.skip == nil {
+		return false
+	}
+	return w.skip[path.Base(name)] || name == w.skipGitDir
+}
+
+// depth returns the depth of the path inside of the walked root,
+// which is 0 for the root itself.
+func (w *walker) depth(name string) int {
+	if name == w.root {
+		return 0
+	}
+	if w.root != "." {
+		name = strings.TrimPrefix(name, w.root+"/")
+	}
+	return strings.Count(name, "/") + 1
+}
+
+// dirChild is an entry of a directory which is not ignored.
+type dirChild struct {
+	name string
+	d    fs.DirEntry
+
+	// parents contains the FileInfos of all parent directories including
+	// the child itself. It is only set if symlinks are followed.
+	parents []fs.FileInfo
+}
+
+// WalkDir walks the file tree using a new NoGo instance with the extraRules
+// (e.g. DotGitRule). It loads all ignore files with the ignoreFileName which
+// are relevant for the root, including the ones of the parent directories.
+// See NoGo.WalkDir for details.
+//
+// Example:
+//
+//	err := nogo.WalkDir(fsys, ".gitignore", ".", func(path string, d fs.DirEntry, err error) error {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(path)
+//		return nil
+//	}, nogo.DotGitRule)
+func WalkDir(fsys fs.FS, ignoreFileName string, root string, fn fs.WalkDirFunc, extraRules ...Rule) error {
+	n := New()
+	n.AddRules(extraRules...)
+
+	// The ignore files inside of the root are loaded while walking,
+	// so only the ones of the parent directories have to be loaded before.
+	if root != "." {
+		dirs := []string{"."}
+		if parent := path.Dir(root); parent != "." {
+			segments := strings.Split(parent, "/")
+			for i := range segments {
+				dirs = append(dirs, strings.Join(segments[:i+1], "/"))
+			}
+		}
+
+		for _, dir := range dirs {
+			if match, _ := n.MatchWithoutParents(dir, true); match && dir != "." {
+				// Everything inside of it is ignored, including the root.
+				return nil
+			}
+
+			file := path.Join(dir, ignoreFileName)
+			if match, _ := n.MatchWithoutParents(file, false); match {
+				continue
+			}
+			err := n.AddFile(fsys, file)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+
+	return n.WalkDir(fsys, root, fn, WithIgnoreFile(ignoreFileName))
+}
+
+// WalkDir walks the file tree the same way as fs.WalkDir but skips all
+// ignored files and directories. The fn is only called for paths which
+// are not ignored.
+//
+// In contrast to using ForWalkDir with fs.WalkDir it supports additional
+// options, e.g. WithPostDir.
+//
+// Like for fs.WalkDir, fs.SkipAll returned by fn or the PostDirFunc
+// stops the walk and WalkDir returns nil.
+//
+// You have to call AddFromFS with the same fs before running the walk,
+// or load the ignore files while walking using WithIgnoreFile.
+func (n *NoGo) WalkDir(fsys fs.FS, root string, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	w := newWalker(n, fsys, root, fn, opts)
+
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		err = w.handleErr(root, nil, err)
+	} else {
+		d := statDirEntry{info}
+		if w.ignored(root, d.IsDir()) {
+			return nil
+		}
+		err = w.walk(dirChild{name: root, d: d, parents: w.parents(nil, d)})
+	}
+
+	return w.walkErr(err)
+}
+
+// ignored checks if the path is ignored. The root "." is never ignored.
+func (w *walker) ignored(name string, isDir bool) bool {
+	if name == "." {
+		return false
+	}
+	if w.skipped(name) {
+		return true
+	}
+	if isDir && w.prune != nil && w.prune.Match(name, true) {
+		return true
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	match, _ := w.n.MatchWithoutParents(name, isDir)
+	return match
+}
+
+// loadIgnoreFile loads the ignore file of the directory if WithIgnoreFile is used.
+func (w *walker) loadIgnoreFile(dir string) error {
+	if w.ignoreFile == "" {
+		return nil
+	}
+
+	name := path.Join(dir, w.ignoreFile)
+	if w.ignored(name, false) {
+		return nil
+	}
+
+	w.mu.Lock()
+	start := time.Now()
+	rules, err := w.n.addFile(w.fsys, name)
+	duration := time.Since(start)
+	w.mu.Unlock()
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	w.n.report(ProgressEvent{Kind: ProgressIgnoreFile, Path: name, Rules: rules, Err: err})
+	if w.load != nil {
+		w.load(LoadEvent{
+			Path:     name,
+			Rules:    rules,
+			Duration: duration,
+			Err:      err,
+		})
+	}
+	return err
+}
+
+// readDir loads the ignore file of the directory and returns all children
+// which are not ignored. Errors are handled according to the ErrorPolicy,
+// which reports them to the WalkDirFunc by default, and the result is returned.
+func (w *walker) readDir(name string, d fs.DirEntry, parents []fs.FileInfo) ([]dirChild, DirStats, error) {
+	if w.ctx != nil {
+		if err := w.ctx.Err(); err != nil {
+			return nil, DirStats{}, err
+		}
+	}
+
+	w.n.report(ProgressEvent{Kind: ProgressDir, Path: name})
+	if w.maxDepth >= 0 && w.depth(name) >= w.maxDepth {
+		return nil, DirStats{}, nil
+	}
+
+	err := w.loadIgnoreFile(name)
+	var entries []fs.DirEntry
+	if err == nil {
+		entries, err = fs.ReadDir(w.fsys, name)
+	}
+	if err != nil {
+		// Second call, to report the error.
+		if err := w.handleErr(name, d, err); err != nil {
+			return nil, DirStats{}, err
+		}
+	}
+
+	// The stats are calculated for all children, even if the walk of the
+	// directory gets stopped early by fs.SkipDir.
+	var stats DirStats
+	children := make([]dirChild, 0, len(entries))
+	for _, entry := range entries {
+		childName := path.Join(name, entry.Name())
+		if w.followSymlinks && entry.Type()&fs.ModeSymlink != 0 {
+			entry = w.followSymlink(childName, entry, parents)
+		}
+
+		if w.ignored(childName, entry.IsDir()) {
+			w.n.report(ProgressEvent{Kind: ProgressSkipped, Path: childName, IsDir: entry.IsDir()})
+			stats.Ignored++
+			if w.skippedFn != nil {
+				if err := w.skippedFn(childName, entry); err != nil {
+					return nil, stats, err
+				}
+			}
+			continue
+		}
+		stats.Included++
+		children = append(children, dirChild{
+			name:    childName,
+			d:       entry,
+			parents: w.parents(parents, entry),
+		})
+	}
+
+	return children, stats, nil
+}
+
+// followSymlink returns a fs.DirEntry for the target if the symlink points to a directory.
+// Otherwise, or if the target is one of the parents, the symlink is returned unchanged.
+func (w *walker) followSymlink(name string, symlink fs.DirEntry, parents []fs.FileInfo) fs.DirEntry {
+	info, err := fs.Stat(w.fsys, name)
+	if err != nil || !info.IsDir() {
+		return symlink
+	}
+
+	for _, parent := range parents {
+		if os.SameFile(info, parent) {
+			return symlink
+		}
+	}
+
+	return symlinkDirEntry{name: symlink.Name(), info: info}
+}
+
+// parents adds the FileInfo of the directory to the parents if symlinks are followed.
+func (w *walker) parents(parents []fs.FileInfo, d fs.DirEntry) []fs.FileInfo {
+	if !w.followSymlinks || !d.IsDir() {
+		return nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return parents
+	}
+
+	// Copy the slice, as it is shared by all children.
+	return append(parents[:len(parents):len(parents)], info)
+}
+
+func (w *walker) walk(child dirChild) error {
+	name, d := child.name, child.d
+	if err := w.fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			// Successfully skipped directory.
+			err = nil
+		}
+		return err
+	}
+
+	children, stats, err := w.readDir(name, d, child.parents)
+	if err != nil {
+		if err == fs.SkipDir {
+			err = nil
+		}
+		return err
+	}
+
+	for _, child := range children {
+		if err := w.walk(child); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+
+	if w.post != nil {
+		return w.post(name, d, stats)
+	}
+	return nil
+}
+
+// statDirEntry converts a fs.FileInfo to a fs.DirEntry.
+type statDirEntry struct {
+	info fs.FileInfo
+}
+
+func (d statDirEntry) Name() string               { return d.info.Name() }
+func (d statDirEntry) IsDir() bool                { return d.info.IsDir() }
+func (d statDirEntry) Type() fs.FileMode          { return d.info.Mode().Type() }
+func (d statDirEntry) Info() (fs.FileInfo, error) { return d.info, nil }
+
+// symlinkDirEntry is a symlink which points to a directory.
+type symlinkDirEntry struct {
+	name string
+	info fs.FileInfo
+}
+
+func (d symlinkDirEntry) Name() string               { return d.name }
+func (d symlinkDirEntry) IsDir() bool                { return true }
+func (d symlinkDirEntry) Type() fs.FileMode          { return fs.ModeDir | fs.ModeSymlink }
+func (d symlinkDirEntry) Info() (fs.FileInfo, error) { return d.info, nil }
@@ -0,0 +1,187 @@
+package nogo
+
+import (
+	"io/fs"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WalkDirConcurrent walks the file tree using a new NoGo instance which lazily
+// loads all .gitignore files inside of the root.
+// See NoGo.WalkDirConcurrent for details.
+func WalkDirConcurrent(fsys fs.FS, root string, workers int, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	opts = append([]WalkOption{WithIgnoreFile(".gitignore")}, opts...)
+	return New().WalkDirConcurrent(fsys, root, workers, fn, opts...)
+}
+
+// WalkDirConcurrent does the same as WalkDir but reads the directories
+// using a pool of workers. This is much faster on slow filesystems,
+// e.g. network filesystems.
+// If workers <= 0, runtime.GOMAXPROCS(0) workers are used.
+//
+// The fn is called concurrently from several goroutines and the paths are
+// not walked in lexical order. Only the entries of a single directory are
+// passed in lexical order.
+// If fn returns fs.SkipDir for a file, the remaining entries of the directory
+// are skipped. Any other error stops the walk and the first error is returned.
+// If it is fs.SkipAll, nil is returned instead. Note that fn may still be
+// called for the entries of directories which are walked at the same time.
+//
+// The PostDirFunc of WithPostDir is also called concurrently, but for each
+// directory still after all of its children. fs.SkipDir returned from it is ignored.
+//
+// Rules must not be added to the NoGo instance while walking,
+// except by WithIgnoreFile.
+func (n *NoGo) WalkDirConcurrent(fsys fs.FS, root string, workers int, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	c := &concurrentWalker{walker: newWalker(n, fsys, root, fn, opts)}
+	c.cond = sync.NewCond(&c.mu)
+
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		err = c.handleErr(root, nil, err)
+	} else {
+		d := statDirEntry{info}
+		if c.ignored(root, d.IsDir()) {
+			return nil
+		}
+
+		err = fn(root, d, nil)
+		if err == nil && d.IsDir() {
+			c.push(&dirJob{name: root, d: d, parents: c.parents(nil, d)})
+			c.run(workers)
+			err = c.err
+		}
+	}
+
+	return c.walkErr(err)
+}
+
+// dirJob is a directory which has to be read by a worker.
+type dirJob struct {
+	name    string
+	d       fs.DirEntry
+	parent  *dirJob
+	parents []fs.FileInfo
+
+	// pending is the amount of unfinished children plus one for the
+	// directory itself. The PostDirFunc is called when it reaches zero.
+	pending int32
+	skipped bool
+	stats   DirStats
+}
+
+type concurrentWalker struct {
+	*walker
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue []*dirJob
+	// active is the amount of queued and currently processed jobs.
+	active int
+	err    error
+}
+
+func (c *concurrentWalker) push(job *dirJob) {
+	atomic.AddInt32(&job.pending, 1)
+
+	c.mu.Lock()
+	c.queue = append(c.queue, job)
+	c.active++
+	c.mu.Unlock()
+	c.cond.Signal()
+}
+
+func (c *concurrentWalker) run(workers int) {
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			c.work()
+		}()
+	}
+	wg.Wait()
+}
+
+func (c *concurrentWalker) work() {
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && c.active > 0 && c.err == nil {
+			c.cond.Wait()
+		}
+		if c.err != nil || c.active == 0 {
+			c.mu.Unlock()
+			return
+		}
+		job := c.queue[len(c.queue)-1]
+		c.queue = c.queue[:len(c.queue)-1]
+		c.mu.Unlock()
+
+		err := c.process(job)
+
+		c.mu.Lock()
+		c.active--
+		if err != nil && c.err == nil {
+			c.err = err
+		}
+		c.mu.Unlock()
+		c.cond.Broadcast()
+	}
+}
+
+// process reads the directory of the job and calls fn for all children.
+// The fn for the directory itself was already called before it was queued.
+func (c *concurrentWalker) process(job *dirJob) error {
+	children, stats, err := c.readDir(job.name, job.d, job.parents)
+	if err != nil {
+		if err != fs.SkipDir {
+			return err
+		}
+		job.skipped = true
+		return c.finish(job)
+	}
+	job.stats = stats
+
+	for _, child := range children {
+		err := c.fn(child.name, child.d, nil)
+		if err == fs.SkipDir {
+			if child.d.IsDir() {
+				continue
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if child.d.IsDir() {
+			atomic.AddInt32(&job.pending, 1)
+			c.push(&dirJob{name: child.name, d: child.d, parent: job, parents: child.parents})
+		}
+	}
+
+	return c.finish(job)
+}
+
+// finish marks the job or one of its children as done.
+// If the job and all of its children are done, the PostDirFunc is called
+// and the parent gets notified.
+func (c *concurrentWalker) finish(job *dirJob) error {
+	for ; job != nil; job = job.parent {
+		if atomic.AddInt32(&job.pending, -1) > 0 {
+			return nil
+		}
+
+		if c.post != nil && !job.skipped {
+			if err := c.post(job.name, job.d, job.stats); err != nil && err != fs.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package nogo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGo_WalkDirConcurrent(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	var want []string
+	require.NoError(t, n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		want = append(want, path)
+		return nil
+	}))
+
+	for _, workers := range []int{0, 1, 4} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			var mu sync.Mutex
+			var got []string
+			err := n.WalkDirConcurrent(fsys, ".", workers, func(path string, d fs.DirEntry, err error) error {
+				require.NoError(t, err)
+				mu.Lock()
+				got = append(got, path)
+				mu.Unlock()
+				return nil
+			})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, want, got)
+		})
+	}
+}
+
+func TestWalkDirConcurrent(t *testing.T) {
+	// Many directories to make sure that the workers really run in parallel.
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte("*.log\n")},
+	}
+	var want []string
+	for i := 0; i < 20; i++ {
+		dir := fmt.Sprintf("dir%02d", i)
+		fsys[dir+"/.gitignore"] = &fstest.MapFile{Data: []byte("secret\n")}
+		fsys[dir+"/secret"] = &fstest.MapFile{Data: []byte("secret")}
+		fsys[dir+"/debug.log"] = &fstest.MapFile{Data: []byte("log")}
+		fsys[dir+"/sub/secret"] = &fstest.MapFile{Data: []byte("secret")}
+		fsys[dir+"/sub/file"] = &fstest.MapFile{Data: []byte("file")}
+		want = append(want, dir, dir+"/.gitignore", dir+"/sub", dir+"/sub/file")
+	}
+	want = append(want, ".", ".gitignore")
+
+	var mu sync.Mutex
+	var got []string
+	posts := make(map[string]DirStats)
+	err := WalkDirConcurrent(fsys, ".", 8, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		mu.Lock()
+		got = append(got, path)
+		mu.Unlock()
+		return nil
+	}, WithPostDir(func(path string, d fs.DirEntry, stats DirStats) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, exists := posts[path]
+		assert.False(t, exists, "post called twice for %v", path)
+		posts[path] = stats
+		return nil
+	}))
+	require.NoError(t, err)
+
+	sort.Strings(want)
+	sort.Strings(got)
+	assert.Equal(t, want, got)
+
+	assert.Len(t, posts, 41)
+	assert.Equal(t, DirStats{Included: 21}, posts["."])
+	assert.Equal(t, DirStats{Included: 2, Ignored: 2}, posts["dir00"])
+	assert.Equal(t, DirStats{Included: 1, Ignored: 1}, posts["dir00/sub"])
+}
+
+func TestNoGo_WalkDirConcurrent_PostOrder(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	var mu sync.Mutex
+	done := make(map[string]bool)
+	err := n.WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+		return nil
+	}, WithPostDir(func(path string, d fs.DirEntry, stats DirStats) error {
+		mu.Lock()
+		defer mu.Unlock()
+		switch path {
+		case "sub":
+			assert.True(t, done["sub/deeper"])
+		case ".":
+			assert.True(t, done["sub"])
+		}
+		done[path] = true
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{".": true, "sub": true, "sub/deeper": true}, done)
+}
+
+func TestNoGo_WalkDirConcurrent_Errors(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+	errStop := errors.New("stop")
+
+	err := n.WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+		if path == "sub/deeper" {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	err = n.WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+		return nil
+	}, WithPostDir(func(path string, d fs.DirEntry, stats DirStats) error {
+		return errStop
+	}))
+	assert.ErrorIs(t, err, errStop)
+
+	var mu sync.Mutex
+	var got []string
+	err = n.WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+		mu.Lock()
+		got = append(got, path)
+		mu.Unlock()
+		if path == "sub" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".", ".gitignore", "main.go", "sub"}, got)
+
+	err = n.WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+		if path == "sub/deeper" {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = n.WalkDirConcurrent(fsys, "missing", 4, func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
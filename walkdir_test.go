@@ -0,0 +1,530 @@
+package nogo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var walkTestFiles = map[string]string{
+	".gitignore":         "*.log\n/build\n",
+	"main.go":            "package main",
+	"debug.log":          "log",
+	"build/out":          "binary",
+	"sub/.gitignore":     "secret.txt",
+	"sub/secret.txt":     "secret",
+	"sub/public.txt":     "public",
+	"sub/deeper/a.log":   "log",
+	"sub/deeper/b.log":   "log",
+	"sub/deeper/keep.md": "keep",
+}
+
+func newWalkTestNoGo(t *testing.T, fsys fs.FS) *NoGo {
+	n := New()
+	require.NoError(t, n.AddFromFS(fsys, ".gitignore"))
+	return n
+}
+
+func TestNoGo_WalkDir(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	var got []string
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		got = append(got, path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// It must be the same as fs.WalkDir with ForWalkDir.
+	var want []string
+	require.NoError(t, fs.WalkDir(n.ForWalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		want = append(want, path)
+		return nil
+	})))
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{
+		".",
+		".gitignore",
+		"main.go",
+		"sub",
+		"sub/.gitignore",
+		"sub/deeper",
+		"sub/deeper/keep.md",
+		"sub/public.txt",
+	}, got)
+}
+
+func TestNoGo_WalkDir_SkipDir(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	var got []string
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		got = append(got, path)
+		if path == "sub/deeper" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitignore", "main.go", "sub", "sub/.gitignore", "sub/deeper", "sub/public.txt"}, got)
+
+	got = nil
+	err = n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		got = append(got, path)
+		if path == ".gitignore" {
+			// A file returning SkipDir skips the remaining entries of its directory.
+			return fs.SkipDir
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitignore"}, got)
+
+	got = nil
+	err = n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		got = append(got, path)
+		if path == "sub/.gitignore" {
+			return fs.SkipAll
+		}
+		return nil
+	}, WithPostDir(func(path string, d fs.DirEntry, stats DirStats) error {
+		t.Errorf("unexpected post call for %v", path)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", ".gitignore", "main.go", "sub", "sub/.gitignore"}, got)
+}
+
+func TestNoGo_WalkDir_Errors(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+	errStop := errors.New("stop")
+
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if path == "sub" {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	err = n.WalkDir(fsys, "missing", func(path string, d fs.DirEntry, err error) error {
+		assert.Nil(t, d)
+		return err
+	})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// An ignored root is not walked at all.
+	err = n.WalkDir(fsys, "build", func(path string, d fs.DirEntry, err error) error {
+		t.Errorf("unexpected call for %v", path)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestWithIgnoreFile(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	// The ignore file in the ignored build folder must not be loaded.
+	fsys["build/.gitignore"] = &fstest.MapFile{Data: []byte("!*.log")}
+	n := New()
+
+	var got []string
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		got = append(got, path)
+		return nil
+	}, WithIgnoreFile(".gitignore"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		".",
+		".gitignore",
+		"main.go",
+		"sub",
+		"sub/.gitignore",
+		"sub/deeper",
+		"sub/deeper/keep.md",
+		"sub/public.txt",
+	}, got)
+	assert.Len(t, n.groups, 2)
+
+	t.Run("invalid ignore file", func(t *testing.T) {
+		fsys := newTestMapFS(walkTestFiles)
+		fsys["sub/.gitignore"] = &fstest.MapFile{Data: []byte("[a")}
+
+		var errPaths []string
+		err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				errPaths = append(errPaths, path)
+				return fs.SkipDir
+			}
+			return nil
+		}, WithIgnoreFile(".gitignore"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sub"}, errPaths)
+	})
+}
+
+func TestWithLoadFunc(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	fsys["build/.gitignore"] = &fstest.MapFile{Data: []byte("!*.log")}
+	fsys["other/.gitignore"] = &fstest.MapFile{Data: []byte("[a")}
+
+	var events []LoadEvent
+	err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fs.SkipDir
+		}
+		return nil
+	}, WithIgnoreFile(".gitignore"), WithLoadFunc(func(event LoadEvent) {
+		assert.GreaterOrEqual(t, int64(event.Duration), int64(0))
+		event.Duration = 0
+		events = append(events, event)
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, LoadEvent{Path: ".gitignore", Rules: 2}, events[0])
+	assert.Equal(t, "other/.gitignore", events[1].Path)
+	assert.Error(t, events[1].Err)
+	assert.Equal(t, LoadEvent{Path: "sub/.gitignore", Rules: 1}, events[2])
+
+	t.Run("concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		var paths []string
+		err := New().WalkDirConcurrent(newTestMapFS(walkTestFiles), ".", 4, func(path string, d fs.DirEntry, err error) error {
+			return err
+		}, WithIgnoreFile(".gitignore"), WithLoadFunc(func(event LoadEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			paths = append(paths, event.Path)
+		}))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{".gitignore", "sub/.gitignore"}, paths)
+	})
+}
+
+func TestWithPostDir(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	n := newWalkTestNoGo(t, fsys)
+
+	var events []string
+	stats := make(map[string]DirStats)
+	err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		if d.IsDir() {
+			events = append(events, "pre "+path)
+		}
+		return nil
+	}, WithPostDir(func(path string, d fs.DirEntry, s DirStats) error {
+		assert.True(t, d.IsDir())
+		events = append(events, "post "+path)
+		stats[path] = s
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"pre .",
+		"pre sub",
+		"pre sub/deeper",
+		"post sub/deeper",
+		"post sub",
+		"post .",
+	}, events)
+	assert.Equal(t, map[string]DirStats{
+		".":          {Included: 3, Ignored: 2},
+		"sub":        {Included: 3, Ignored: 1},
+		"sub/deeper": {Included: 1, Ignored: 2},
+	}, stats)
+
+	t.Run("skipped directory", func(t *testing.T) {
+		var posts []string
+		err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if path == "sub" {
+				return fs.SkipDir
+			}
+			return nil
+		}, WithPostDir(func(path string, d fs.DirEntry, s DirStats) error {
+			posts = append(posts, path)
+			return nil
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"."}, posts)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := n.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			return nil
+		}, WithPostDir(func(path string, d fs.DirEntry, s DirStats) error {
+			return errStop
+		}))
+		assert.ErrorIs(t, err, errStop)
+	})
+}
+
+func TestWithFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "real"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "real", "file.txt"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("link/\n"), 0644))
+	if err := os.Symlink("real", filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+	require.NoError(t, os.Symlink("..", filepath.Join(dir, "real", "up")))
+	require.NoError(t, os.Symlink(filepath.Join("real", "file.txt"), filepath.Join(dir, "fileLink")))
+	fsys := os.DirFS(dir)
+
+	walk := func(t *testing.T, opts ...WalkOption) map[string]fs.FileMode {
+		got := make(map[string]fs.FileMode)
+		err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got[path] = d.Type()
+			return nil
+		}, append(opts, WithIgnoreFile(".gitignore"))...)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("like git", func(t *testing.T) {
+		// A symlink to a directory is a file for git, so "link/" does not match.
+		assert.Equal(t, map[string]fs.FileMode{
+			".":             fs.ModeDir,
+			".gitignore":    0,
+			"fileLink":      fs.ModeSymlink,
+			"link":          fs.ModeSymlink,
+			"real":          fs.ModeDir,
+			"real/file.txt": 0,
+			"real/up":       fs.ModeSymlink,
+		}, walk(t))
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		// Now the link is a directory and therefore ignored.
+		// The link to the parent directory is not followed to avoid endless loops.
+		assert.Equal(t, map[string]fs.FileMode{
+			".":             fs.ModeDir,
+			".gitignore":    0,
+			"fileLink":      fs.ModeSymlink,
+			"real":          fs.ModeDir,
+			"real/file.txt": 0,
+			"real/up":       fs.ModeSymlink,
+		}, walk(t, WithFollowSymlinks()))
+	})
+
+	t.Run("follow concurrent without ignore file", func(t *testing.T) {
+		var mu sync.Mutex
+		got := make(map[string]bool)
+		err := New().WalkDirConcurrent(fsys, ".", 2, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			mu.Lock()
+			got[path] = d.IsDir()
+			mu.Unlock()
+			return nil
+		}, WithFollowSymlinks())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{
+			".":             true,
+			".gitignore":    false,
+			"fileLink":      false,
+			"link":          true,
+			"link/file.txt": false,
+			"link/up":       false,
+			"real":          true,
+			"real/file.txt": false,
+			"real/up":       false,
+		}, got)
+	})
+}
+
+func TestWithSkipGitDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		".git/HEAD":        {},
+		".hg/store":        {},
+		"main.go":          {},
+		"module/.git":      {Data: []byte("gitdir: ../.git/modules/module")},
+		"module/lib.go":    {},
+		"custom-git/HEAD":  {},
+		"sub/.svn/entries": {},
+	}
+
+	walk := func(opts ...WalkOption) []string {
+		var got []string
+		err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got = append(got, path)
+			return nil
+		}, opts...)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Setenv("GIT_DIR", "")
+	assert.Equal(t, []string{".", "custom-git", "custom-git/HEAD", "main.go", "module", "module/lib.go", "sub"}, walk(WithSkipVCSDirs()))
+	assert.Equal(t, []string{".", ".hg", ".hg/store", "custom-git", "custom-git/HEAD", "main.go", "module", "module/lib.go", "sub", "sub/.svn", "sub/.svn/entries"}, walk(WithSkipGitDir()))
+
+	t.Setenv("GIT_DIR", "./custom-git/")
+	assert.Equal(t, []string{".", ".hg", ".hg/store", "main.go", "module", "module/lib.go", "sub", "sub/.svn", "sub/.svn/entries"}, walk(WithSkipGitDir()))
+}
+
+func TestGitDirPath(t *testing.T) {
+	tests := []struct {
+		gitDir string
+		want   string
+	}{
+		{gitDir: "", want: ""},
+		{gitDir: ".", want: ""},
+		{gitDir: "repo.git", want: "repo.git"},
+		{gitDir: "./a/b/", want: "a/b"},
+		{gitDir: "../outside", want: ""},
+		{gitDir: "/abs/.git", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.gitDir, func(t *testing.T) {
+			assert.Equal(t, tt.want, gitDirPath(tt.gitDir))
+		})
+	}
+}
+
+func TestWalkDir(t *testing.T) {
+	fsys := newTestMapFS(walkTestFiles)
+	fsys[".git/config"] = &fstest.MapFile{Data: []byte("config")}
+
+	walk := func(root string, extraRules ...Rule) []string {
+		var got []string
+		err := WalkDir(fsys, ".gitignore", root, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got = append(got, path)
+			return nil
+		}, extraRules...)
+		require.NoError(t, err)
+		return got
+	}
+
+	assert.Equal(t, []string{".", ".gitignore", "main.go", "sub", "sub/.gitignore", "sub/deeper", "sub/deeper/keep.md", "sub/public.txt"}, walk(".", DotGitRule))
+	assert.Contains(t, walk("."), ".git/config")
+
+	// The ignore files of the parents are loaded, too.
+	assert.Equal(t, []string{"sub/deeper", "sub/deeper/keep.md"}, walk("sub/deeper"))
+	assert.Equal(t, []string{"sub", "sub/.gitignore", "sub/deeper", "sub/deeper/keep.md", "sub/public.txt"}, walk("sub"))
+
+	// Ignored roots are not walked at all.
+	assert.Empty(t, walk("build"))
+	fsys["build/sub/file"] = &fstest.MapFile{Data: []byte("file")}
+	assert.Empty(t, walk("build/sub"))
+
+	t.Run("ignored ignore file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			".gitignore":         {Data: []byte("sub/.gitignore")},
+			"sub/.gitignore":     {Data: []byte("*")},
+			"sub/deeper/file.go": {},
+		}
+		var got []string
+		err := WalkDir(fsys, ".gitignore", "sub/deeper", func(path string, d fs.DirEntry, err error) error {
+			got = append(got, path)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sub/deeper", "sub/deeper/file.go"}, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := WalkDir(fsys, ".gitignore", "missing/dir", func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":      {Data: []byte("*.log\n")},
+		"a.go":            {},
+		"a.log":           {},
+		"sub/b.go":        {},
+		"sub/deep/c.go":   {},
+		"sub/deep/d/e.go": {},
+	}
+
+	walk := func(root string, opts ...WalkOption) []string {
+		var got []string
+		err := New().WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			got = append(got, path)
+			return nil
+		}, append(opts, WithIgnoreFile(".gitignore"))...)
+		require.NoError(t, err)
+		return got
+	}
+
+	assert.Equal(t, []string{"."}, walk(".", WithMaxDepth(0)))
+	assert.Equal(t, []string{".", ".gitignore", "a.go", "sub"}, walk(".", WithMaxDepth(1)))
+	assert.Equal(t, []string{".", ".gitignore", "a.go", "sub", "sub/b.go", "sub/deep"}, walk(".", WithMaxDepth(2)))
+	assert.Equal(t, []string{"sub", "sub/b.go", "sub/deep"}, walk("sub", WithMaxDepth(1)))
+	assert.Len(t, walk(".", WithMaxDepth(-1)), 9)
+
+	t.Run("post dir", func(t *testing.T) {
+		posts := make(map[string]DirStats)
+		err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			return err
+		}, WithMaxDepth(1), WithPostDir(func(path string, d fs.DirEntry, stats DirStats) error {
+			posts[path] = stats
+			return nil
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]DirStats{".": {Included: 4}, "sub": {}}, posts)
+	})
+}
+
+func TestWithPruneDirs(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":                    {Data: []byte("*.log\n")},
+		"main.go":                       {},
+		"node_modules/lib/index.js":     {},
+		"web/node_modules/lib/index.js": {},
+		"web/app.js":                    {},
+		"target/out.bin":                {},
+		"sub/target":                    {Data: []byte("a file")},
+		"build/out.log":                 {},
+	}
+
+	var got []string
+	err := New().WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		got = append(got, path)
+		return nil
+	}, WithIgnoreFile(".gitignore"), WithPruneDirs("node_modules", "/target/", "*.go", "[invalid"))
+	require.NoError(t, err)
+
+	// Files are never pruned and the ignore rules still apply.
+	assert.Equal(t, []string{".", ".gitignore", "build", "main.go", "sub", "sub/target", "web", "web/app.js"}, got)
+
+	t.Run("concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []string
+		err := New().WalkDirConcurrent(fsys, ".", 4, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			mu.Lock()
+			got = append(got, path)
+			mu.Unlock()
+			return nil
+		}, WithIgnoreFile(".gitignore"), WithPruneDirs("node_modules", "/target/"), WithMaxDepth(1))
+		require.NoError(t, err)
+
+		sort.Strings(got)
+		assert.Equal(t, []string{".", ".gitignore", "build", "main.go", "sub", "web"}, got)
+	})
+}
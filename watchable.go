@@ -0,0 +1,40 @@
+package nogo
+
+import (
+	"io/fs"
+)
+
+// WatchableDirs walks the file tree using a new NoGo instance which lazily
+// loads all .gitignore files inside of the root and returns all directories
+// which are not ignored. See NoGo.WatchableDirs for details.
+func WatchableDirs(fsys fs.FS, root string, opts ...WalkOption) ([]string, error) {
+	opts = append([]WalkOption{WithIgnoreFile(".gitignore")}, opts...)
+	return New().WatchableDirs(fsys, root, opts...)
+}
+
+// WatchableDirs walks the file tree the same way as WalkDir and returns all
+// directories which are not ignored, including the root, in lexical order.
+// It is meant for tools which have to register a watch for each directory,
+// e.g. using fsnotify, as changes inside of ignored directories are irrelevant.
+//
+// Like WalkDir, directories which may contain paths included again by a
+// later rule are returned, too, e.g. "src" of an allow list for "src/**/*.go".
+//
+// Use WithContext to cancel the walk and WithErrorPolicy to skip
+// unreadable directories instead of failing.
+func (n *NoGo) WatchableDirs(fsys fs.FS, root string, opts ...WalkOption) ([]string, error) {
+	var dirs []string
+	err := n.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
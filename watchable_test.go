@@ -0,0 +1,66 @@
+package nogo
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var watchableTestFiles = map[string]string{
+	".gitignore":                  "node_modules/\nbuild/*\n!build/keep/\n",
+	"main.go":                     "",
+	"node_modules/react/index.js": "",
+	"build/out/a.o":               "",
+	"build/keep/b.txt":            "",
+	"src/.gitignore":              "gen/\n",
+	"src/gen/a.go":                "",
+	"src/lib/b.go":                "",
+}
+
+func TestWatchableDirs(t *testing.T) {
+	dirs, err := WatchableDirs(newTestMapFS(watchableTestFiles), ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "build", "build/keep", "src", "src/lib"}, dirs)
+
+	dirs, err = WatchableDirs(newTestMapFS(watchableTestFiles), "src")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src", "src/lib"}, dirs)
+}
+
+func TestNoGo_WatchableDirs_allowList(t *testing.T) {
+	n := NewAllowList(WithRules(MustCompileAll("", []byte("src/**/*.go"))...))
+	dirs, err := n.WatchableDirs(newTestMapFS(watchableTestFiles), ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "src", "src/gen", "src/lib"}, dirs)
+}
+
+func TestWatchableDirs_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dirs, err := WatchableDirs(newTestMapFS(watchableTestFiles), ".", WithContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, dirs)
+
+	err = New().WalkDirConcurrent(newTestMapFS(watchableTestFiles), ".", 2, func(path string, d fs.DirEntry, err error) error {
+		return err
+	}, WithContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The walk is stopped as soon as the context is done.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	var walked []string
+	err = New().WalkDir(newTestMapFS(watchableTestFiles), ".", func(path string, d fs.DirEntry, err error) error {
+		walked = append(walked, path)
+		if path == "build" {
+			cancel()
+		}
+		return err
+	}, WithContext(ctx), WithErrorPolicy(ErrorCollect))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{".", ".gitignore", "build"}, walked)
+}
@@ -0,0 +1,130 @@
+package nogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+var windowsReservedNames = []string{"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+var (
+	// WindowsReservedNameRules ignore all files and folders with names which are
+	// reserved on Windows (e.g. "NUL" or "con.txt"), independent of the case.
+	WindowsReservedNameRules = MustCompileAll("", windowsReservedNamePatterns())
+)
+
+// windowsReservedNamePatterns builds case-insensitive patterns for all reserved names.
+// A reserved name is also reserved with any extension.
+func windowsReservedNamePatterns() []byte {
+	var b strings.Builder
+	for _, name := range windowsReservedNames {
+		var pattern strings.Builder
+		for _, c := range name {
+			if 'A' <= c && c <= 'Z' {
+				fmt.Fprintf(&pattern, "[%c%c]", c, c-'A'+'a')
+			} else {
+				pattern.WriteRune(c)
+			}
+		}
+
+		b.WriteString(pattern.String() + "\n")
+		b.WriteString(pattern.String() + ".*\n")
+	}
+	return []byte(b.String())
+}
+
+// IsWindowsReservedName checks if the name of a file or folder is reserved on Windows.
+// It only checks the last element of the path.
+func IsWindowsReservedName(path string) bool {
+	name := path[strings.LastIndexAny(path, `/\`)+1:]
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+
+	for _, reserved := range windowsReservedNames {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithWindowsReservedNames ignores all files and folders which have a name
+// reserved on Windows. See WindowsReservedNameRules.
+func WithWindowsReservedNames() Option {
+	return optionFunc(func(n *NoGo) {
+		n.AddRules(WindowsReservedNameRules...)
+	})
+}
+
+// WithWindowsStreams removes alternate data streams from the paths before
+// matching. So for example "file.txt:stream" and "file.txt::$DATA" are matched
+// exactly like "file.txt".
+//
+// Only use it for Windows paths, as ':' is a valid character in file names
+// on other systems.
+func WithWindowsStreams() Option {
+	return optionFunc(func(n *NoGo) {
+		n.stripStreams = true
+		n.changed()
+	})
+}
+
+// stripWindowsStreams removes the alternate data streams of all elements of the path.
+func stripWindowsStreams(path string) string {
+	if !strings.Contains(path, ":") {
+		return path
+	}
+
+	elements := strings.Split(path, "/")
+	for i, element := range elements {
+		if j := strings.IndexByte(element, ':'); j >= 0 {
+			elements[i] = element[:j]
+		}
+	}
+	return strings.Join(elements, "/")
+}
+
+// NormalizeWindowsPath converts a relative Windows path like `foo\bar\baz`
+// to the form expected by NoGo ("foo/bar/baz").
+//
+// Absolute Windows paths can't be matched, as NoGo doesn't know the root
+// the rules are relative to. So paths with a drive letter (`C:\foo`, `C:foo`)
+// and UNC or device paths (`\\server\share\foo`, `\\?\C:\foo`) are rejected
+// with an *InvalidPathError. Make them relative to the root first, e.g. using
+// filepath.Rel.
+func NormalizeWindowsPath(path string) (string, error) {
+	normalized := strings.ReplaceAll(path, `\`, "/")
+
+	if strings.HasPrefix(normalized, "//") {
+		return "", &InvalidPathError{Path: path, Reason: "UNC or device path, make it relative to the root"}
+	}
+	if len(normalized) >= 2 && normalized[1] == ':' && isASCIILetter(normalized[0]) {
+		return "", &InvalidPathError{Path: path, Reason: "path with drive letter, make it relative to the root"}
+	}
+	return normalized, nil
+}
+
+// WithWindowsPaths normalizes all matched paths using NormalizeWindowsPath,
+// independent of the operating system. So `foo\bar` gets the same result
+// as "foo/bar" also on Linux, where '\' is a valid character in file names.
+//
+// As Match, MatchBecause and MatchWithoutParents can't return an error,
+// absolute Windows paths are never ignored by them.
+// Use MatchErr to get the *InvalidPathError instead.
+//
+// Note that a path starting with a single letter followed by ':'
+// (e.g. "a:stream") is always a path with drive letter.
+func WithWindowsPaths() Option {
+	return optionFunc(func(n *NoGo) {
+		n.windowsPaths = true
+		n.changed()
+	})
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
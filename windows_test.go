@@ -0,0 +1,194 @@
+package nogo
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWindowsReservedName(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "NUL", want: true},
+		{path: "nul", want: true},
+		{path: "a/folder/Con.txt", want: true},
+		{path: `a\folder\com1.tar.gz`, want: true},
+		{path: "LPT9", want: true},
+		{path: "COM0", want: false},
+		{path: "console", want: false},
+		{path: "NUL/aFile", want: false},
+		{path: "aFile", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsWindowsReservedName(tt.path))
+		})
+	}
+}
+
+func TestWithWindowsReservedNames(t *testing.T) {
+	n := New(WithWindowsReservedNames())
+
+	for _, path := range []string{"NUL", "nul", "a/folder/Con.txt", "com1.tar.gz", "LPT9", "COM0", "console", "aFile", "aux/aFile"} {
+		t.Run(path, func(t *testing.T) {
+			// Everything in a reserved folder is ignored, too.
+			want := IsWindowsReservedName(path) || path == "aux/aFile"
+			assert.Equal(t, want, n.Match(path, false))
+		})
+	}
+}
+
+func TestWithWindowsStreams(t *testing.T) {
+	rules := MustCompileAll("", []byte("*.txt\n/aFolder"))
+
+	withStreams := New(WithWindowsStreams())
+	withStreams.AddRules(rules...)
+	without := New()
+	without.AddRules(rules...)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "file.txt:stream", want: true},
+		{path: "file.txt::$DATA", want: true},
+		{path: "a/file.txt:stream:$DATA", want: true},
+		{path: "aFolder:stream", isDir: true, want: true},
+		{path: "aFolder:$I30:$INDEX_ALLOCATION/file.go", want: true},
+		{path: "file.go:stream.txt", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, withStreams.Match(tt.path, tt.isDir))
+		})
+	}
+
+	// Without the option, ':' is just a normal character.
+	assert.False(t, without.Match("file.txt:stream", false))
+	assert.True(t, without.Match("file.go:stream.txt", false))
+}
+
+func TestNormalizeWindowsPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: `foo\bar\baz`, want: "foo/bar/baz"},
+		{path: "foo/bar/baz", want: "foo/bar/baz"},
+		{path: `foo/bar\baz`, want: "foo/bar/baz"},
+		{path: `dir\`, want: "dir/"},
+		{path: "a:b", wantErr: true},
+		{path: `C:\foo`, wantErr: true},
+		{path: `c:foo`, wantErr: true},
+		{path: `C:`, wantErr: true},
+		{path: `\\server\share\foo`, wantErr: true},
+		{path: `\\?\C:\foo`, wantErr: true},
+		{path: `\\.\device`, wantErr: true},
+		{path: "//server/share", wantErr: true},
+		{path: "ab:c", want: "ab:c"},
+		{path: "1:a", want: "1:a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := NormalizeWindowsPath(tt.path)
+			if tt.wantErr {
+				var pathErr *InvalidPathError
+				require.True(t, errors.As(err, &pathErr))
+				assert.Equal(t, tt.path, pathErr.Path)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithWindowsPaths(t *testing.T) {
+	rules := MustCompileAll("", []byte("/foo/bar\n*.txt\n!keep.txt\nbuild/"))
+
+	n := New(WithWindowsPaths())
+	n.AddRules(rules...)
+	slash := New()
+	slash.AddRules(rules...)
+
+	tests := []struct {
+		path  string
+		isDir bool
+	}{
+		{path: `foo\bar\baz`},
+		{path: `foo\bar`, isDir: true},
+		{path: `foo\baz`},
+		{path: `a\b.txt`},
+		{path: `a\keep.txt`},
+		{path: `a\build`, isDir: true},
+		{path: `a\build`},
+		{path: `main.go`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			slashPath := strings.ReplaceAll(tt.path, `\`, "/")
+			want, wantBecause := slash.MatchBecause(slashPath, tt.isDir)
+
+			assert.Equal(t, want, n.Match(tt.path, tt.isDir))
+			match, because := n.MatchBecause(tt.path, tt.isDir)
+			assert.Equal(t, want, match)
+			assert.Equal(t, wantBecause, because)
+
+			match, err := n.MatchErr(tt.path, tt.isDir)
+			require.NoError(t, err)
+			assert.Equal(t, want, match)
+
+			suffix := ""
+			if tt.isDir {
+				suffix = `\`
+			}
+			matches, results := n.MatchAll([]string{tt.path + suffix})
+			assert.Equal(t, want, matches[0])
+			assert.Equal(t, wantBecause, results[0])
+
+			hint := DirNo
+			if tt.isDir {
+				hint = DirYes
+			}
+			match, _ = n.MatchHint(tt.path, hint)
+			assert.Equal(t, want, match)
+		})
+	}
+
+	t.Run("absolute paths", func(t *testing.T) {
+		for _, path := range []string{`C:\foo\bar`, `\\server\share\foo`} {
+			_, err := n.MatchErr(path, false)
+			var pathErr *InvalidPathError
+			assert.True(t, errors.As(err, &pathErr), path)
+
+			assert.False(t, n.Match(path, false), path)
+			match, because := n.MatchBecause(path, false)
+			assert.False(t, match, path)
+			assert.False(t, because.Found, path)
+			matches, results := n.MatchAll([]string{path})
+			assert.Equal(t, []bool{false}, matches, path)
+			assert.Equal(t, []Result{{}}, results, path)
+			ignored, _ := n.MatchDir(path)
+			assert.False(t, ignored, path)
+		}
+	})
+
+	t.Run("without option", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("'\\' is always a separator on Windows")
+		}
+		assert.False(t, slash.Match(`foo\bar\baz`, false))
+
+		match, err := slash.MatchErr(`C:\foo`, false)
+		assert.NoError(t, err)
+		assert.False(t, match)
+	})
+}
@@ -0,0 +1,88 @@
+package nogo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNoPattern is returned by WriteRules and NoGo.Dump for rules which can't
+// be written as gitignore pattern, e.g. the regexps of DialectHgignore.
+var ErrNoPattern = errors.New("nogo: rule has no gitignore pattern")
+
+// WriteRules writes the rules as a single ignore file in the root folder,
+// one line per rule. Rules of ignore files in sub folders are rewritten
+// relative to the root, so the file matches the same paths, e.g. the
+// pattern "*.log" of the prefix "sub" is written as "sub/**/*.log".
+// Negation, anchoring and the trailing '/' of folder-only rules are kept.
+//
+// As later rules override earlier ones, the rules have to be in the order
+// they are checked, e.g. the order of the ignore files returned by NoGo.Groups.
+func WriteRules(w io.Writer, rules []Rule) error {
+	return writeRules(w, "", rules)
+}
+
+// Dump does the same as WriteRules with all rules of the prefix and its sub folders,
+// so the result can be used as the only ignore file of the prefix.
+// Use "" to dump all rules. Rules of parent folders are not included.
+func (n *NoGo) Dump(prefix string) ([]byte, error) {
+	if n.lazy != nil {
+		n.lazy.mu.Lock()
+		defer n.lazy.mu.Unlock()
+	}
+
+	prefix = n.groupPrefix(prefix)
+	var b bytes.Buffer
+	for _, g := range n.groups {
+		if !isPathInside(g.prefix, prefix) {
+			continue
+		}
+		if err := writeRules(&b, prefix, g.rules); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// writeRules writes the rules relative to the base folder.
+func writeRules(w io.Writer, base string, rules []Rule) error {
+	for _, rule := range rules {
+		line, err := ruleLine(base, rule)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleLine converts the rule to a line of an ignore file in the base folder.
+func ruleLine(base string, rule Rule) (string, error) {
+	pattern, _, skip := cleanPattern(rule.Pattern)
+	if skip {
+		return "", ErrNoPattern
+	}
+	if rule.OnlyFolder && !strings.HasSuffix(pattern, "/") {
+		pattern += "/"
+	}
+
+	prefix := strings.Trim(rule.Prefix, "/")
+	if !isPathInside(prefix, base) {
+		return "", fmt.Errorf("nogo: rule %q of %q is not inside of %q", rule.Pattern, prefix, base)
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(prefix, base), "/")
+
+	if rel != "" {
+		if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+			// Anchored patterns are relative to the prefix.
+			pattern = rel + "/" + strings.TrimPrefix(pattern, "/")
+		} else {
+			pattern = rel + "/**/" + pattern
+		}
+	}
+	return patternLine(pattern, rule.Negate), nil
+}
@@ -0,0 +1,66 @@
+package nogo
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRules(t *testing.T) {
+	tests := []struct {
+		prefix string
+		data   string
+		want   string
+	}{
+		{data: "*.log\n!keep.log\n/build\nnode_modules/\n\\#hash\n\\!bang\ntrailing\\ \n# comment", want: "*.log\n!keep.log\n/build\nnode_modules/\n\\#hash\n\\!bang\ntrailing\\ \n"},
+		{prefix: "sub", data: "*.log\n!/keep.log\na/b\ndir/", want: "sub/**/*.log\n!sub/keep.log\nsub/a/b\nsub/**/dir/\n"},
+		{prefix: "sub/", data: "#hash", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+"|"+tt.data, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, WriteRules(&b, MustCompileAll(tt.prefix, []byte(tt.data))))
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+
+	t.Run("no pattern", func(t *testing.T) {
+		rules, err := DialectHgignore.CompileAll("", []byte(`\.orig$`))
+		require.NoError(t, err)
+		assert.ErrorIs(t, WriteRules(&bytes.Buffer{}, rules), ErrNoPattern)
+	})
+}
+
+func TestNoGo_Dump(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":         {Data: []byte("*.log\n/build\n")},
+		"sub/.gitignore":     {Data: []byte("!debug.log\ntmp/\n/generated")},
+		"sub/dir/.gitignore": {Data: []byte("*.go\n")},
+		"other/.gitignore":   {Data: []byte("*")},
+	}
+	n, err := ForFS(fsys, ".gitignore")
+	require.NoError(t, err)
+
+	data, err := n.Dump("")
+	require.NoError(t, err)
+
+	// The dumped rules must match exactly the same paths.
+	dumped := New()
+	dumped.AddRules(MustCompileAll("", data)...)
+	for _, path := range []string{
+		"a.log", "sub/a.log", "sub/debug.log", "debug.log", "build", "sub/build",
+		"sub/tmp/a", "tmp/a", "sub/generated", "sub/x/generated", "sub/dir/a.go",
+		"a.go", "other/x", "main.c",
+	} {
+		assert.Equal(t, n.Match(path, false), dumped.Match(path, false), path)
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		data, err := n.Dump("sub")
+		require.NoError(t, err)
+		assert.Equal(t, "!debug.log\ntmp/\n/generated\ndir/**/*.go\n", string(data))
+	})
+}
@@ -0,0 +1,230 @@
+package x
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sync"
+
+	"github.com/aligator/nogo"
+)
+
+// WatchBackend notifies a Watcher about changes in the file system.
+// All paths are slash separated and relative to the root of the watched fs.FS,
+// the same as the paths used by nogo.NoGo.
+//
+// An implementation based on fsnotify can be found in the separate module
+// github.com/aligator/nogo/nogofsnotify.
+type WatchBackend interface {
+	// Add starts watching the directory for changes of its direct children.
+	Add(dir string) error
+	// Remove stops watching the directory.
+	Remove(dir string) error
+
+	// Events returns the channel which receives the paths of changed files
+	// and directories. It has to be closed by Close.
+	Events() <-chan string
+	// Errors returns the channel which receives errors of the backend.
+	Errors() <-chan error
+
+	// Close stops all watches and closes the channels.
+	Close() error
+}
+
+// WatchFunc is called by a Watcher after the rules were reloaded.
+// If the reload failed, err is set and n is still the previous NoGo instance.
+type WatchFunc func(n *nogo.NoGo, err error)
+
+// Watcher keeps the rules of all ignore files in a file system up to date.
+// It watches all directories which are not ignored for changes of ignore files
+// and replaces the NoGo instance with the reloaded rules atomically.
+//
+// Use NoGo to get the current instance.
+type Watcher struct {
+	fsys           fs.FS
+	ignoreFileName string
+	opts           []nogo.Option
+	backend        WatchBackend
+
+	mu          sync.RWMutex
+	current     *nogo.NoGo
+	dirs        map[string]bool
+	subscribers []WatchFunc
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewWatcher loads all ignore files of the fsys and starts watching them
+// using the backend. The options are passed to nogo.New for each reload.
+//
+// Close has to be called to stop watching.
+func NewWatcher(fsys fs.FS, ignoreFileName string, backend WatchBackend, opts ...nogo.Option) (*Watcher, error) {
+	w := &Watcher{
+		fsys:           fsys,
+		ignoreFileName: ignoreFileName,
+		opts:           opts,
+		backend:        backend,
+		dirs:           make(map[string]bool),
+		done:           make(chan struct{}),
+	}
+
+	if err := w.reload(); err != nil {
+		_ = backend.Close()
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// NoGo returns the nogo.NoGo instance with the current rules.
+// The returned instance is not changed by the Watcher,
+// so it can be used as long as needed.
+func (w *Watcher) NoGo() *nogo.NoGo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Match does the same as nogo.NoGo.Match using the current rules.
+func (w *Watcher) Match(path string, isDir bool) bool {
+	return w.NoGo().Match(path, isDir)
+}
+
+// Subscribe adds a function which is called after each reload of the rules.
+// It is called from the goroutine of the Watcher, so it should not block.
+func (w *Watcher) Subscribe(fn WatchFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Close stops watching and closes the backend.
+// Further calls do nothing and return the error of the first call.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.closeErr = w.backend.Close()
+		w.wg.Wait()
+	})
+	return w.closeErr
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	events := w.backend.Events()
+	errs := w.backend.Errors()
+	for {
+		select {
+		case <-w.done:
+			return
+		case name, ok := <-events:
+			if !ok {
+				return
+			}
+			if w.needsReload(name) {
+				w.notify(w.reload())
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			w.notify(err)
+		}
+	}
+}
+
+// needsReload checks if the changed path may change the rules.
+// This is the case for all ignore files and new directories, as they may
+// contain ignore files.
+func (w *Watcher) needsReload(name string) bool {
+	n := w.NoGo()
+	if path.Base(name) == w.ignoreFileName {
+		match, _ := n.MatchBecause(name, false)
+		return !match
+	}
+
+	info, err := fs.Stat(w.fsys, name)
+	if err != nil {
+		// Removed directories are handled by the reload of their parent.
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		return w.dirs[name]
+	}
+
+	if !info.IsDir() || n.Match(name, true) {
+		return false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return !w.dirs[name]
+}
+
+// reload loads all ignore files and updates the watched directories.
+func (w *Watcher) reload() error {
+	n := nogo.New(w.opts...)
+	dirs := make(map[string]bool)
+	err := n.WalkDir(w.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A directory may be removed while walking.
+			if errors.Is(err, fs.ErrNotExist) && path != "." {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			dirs[path] = true
+		}
+		return nil
+	}, nogo.WithIgnoreFile(w.ignoreFileName))
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var added []string
+	for dir := range dirs {
+		if !w.dirs[dir] {
+			if err := w.backend.Add(dir); err != nil {
+				// Keep watching the same directories as the previous rules.
+				for _, dir := range added {
+					_ = w.backend.Remove(dir)
+				}
+				return err
+			}
+			added = append(added, dir)
+		}
+	}
+	for _, dir := range added {
+		w.dirs[dir] = true
+	}
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			// The directory may already be removed, so the error is not important.
+			_ = w.backend.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+
+	w.current = n
+	return nil
+}
+
+func (w *Watcher) notify(err error) {
+	w.mu.RLock()
+	n := w.current
+	subscribers := w.subscribers
+	w.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(n, err)
+	}
+}
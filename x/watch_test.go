@@ -0,0 +1,216 @@
+package x
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/aligator/nogo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWatchBackend struct {
+	mu     sync.Mutex
+	dirs   map[string]bool
+	events chan string
+	errors chan error
+	closed bool
+
+	// failAdd is the directory which can't be watched.
+	failAdd string
+}
+
+func newFakeWatchBackend() *fakeWatchBackend {
+	return &fakeWatchBackend{
+		dirs:   make(map[string]bool),
+		events: make(chan string),
+		errors: make(chan error),
+	}
+}
+
+func (b *fakeWatchBackend) Add(dir string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if dir == b.failAdd {
+		return errors.New("can't watch " + dir)
+	}
+	b.dirs[dir] = true
+	return nil
+}
+
+func (b *fakeWatchBackend) setFailAdd(dir string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failAdd = dir
+}
+
+func (b *fakeWatchBackend) Remove(dir string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.dirs, dir)
+	return nil
+}
+
+func (b *fakeWatchBackend) Events() <-chan string { return b.events }
+func (b *fakeWatchBackend) Errors() <-chan error  { return b.errors }
+
+func (b *fakeWatchBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *fakeWatchBackend) watched() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var dirs []string
+	for dir := range b.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// reloaded waits for the next call of the subscriber.
+func reloaded(t *testing.T, calls <-chan error) error {
+	select {
+	case err := <-calls:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload")
+		return nil
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n/build\n")},
+		"build/out":      {Data: []byte("binary")},
+		"sub/.gitignore": {Data: []byte("secret.txt")},
+		"sub/secret.txt": {Data: []byte("secret")},
+	}
+	backend := newFakeWatchBackend()
+
+	w, err := NewWatcher(fsys, ".gitignore", backend, nogo.DotGitRule)
+	require.NoError(t, err)
+
+	calls := make(chan error)
+	w.Subscribe(func(n *nogo.NoGo, err error) {
+		assert.Same(t, w.NoGo(), n)
+		calls <- err
+	})
+
+	assert.Equal(t, []string{".", "sub"}, backend.watched())
+	assert.True(t, w.Match("debug.log", false))
+	assert.True(t, w.Match("sub/secret.txt", false))
+	assert.True(t, w.Match(".git", true), "the options must be used")
+	old := w.NoGo()
+
+	// Changed ignore file.
+	fsys["sub/.gitignore"] = &fstest.MapFile{Data: []byte("other.txt")}
+	backend.events <- "sub/.gitignore"
+	require.NoError(t, reloaded(t, calls))
+	assert.False(t, w.Match("sub/secret.txt", false))
+	assert.True(t, w.Match("sub/other.txt", false))
+	assert.True(t, old.Match("sub/secret.txt", false), "the old instance must not change")
+
+	// A new directory gets watched.
+	fsys["new/.gitignore"] = &fstest.MapFile{Data: []byte("new.txt")}
+	backend.events <- "new"
+	require.NoError(t, reloaded(t, calls))
+	assert.Equal(t, []string{".", "new", "sub"}, backend.watched())
+	assert.True(t, w.Match("new/new.txt", false))
+
+	// A removed directory is not watched anymore.
+	delete(fsys, "new/.gitignore")
+	backend.events <- "new"
+	require.NoError(t, reloaded(t, calls))
+	assert.Equal(t, []string{".", "sub"}, backend.watched())
+	assert.False(t, w.Match("new/new.txt", false))
+
+	// Ignore files in ignored directories and other files are not relevant.
+	fsys["build/.gitignore"] = &fstest.MapFile{Data: []byte("*")}
+	backend.events <- "build/.gitignore"
+	backend.events <- "sub/a.txt"
+	backend.events <- "build"
+
+	// Errors of the backend are passed to the subscribers.
+	errBackend := errors.New("backend error")
+	backend.errors <- errBackend
+	assert.ErrorIs(t, reloaded(t, calls), errBackend)
+
+	// An invalid ignore file keeps the old rules.
+	current := w.NoGo()
+	fsys[".gitignore"] = &fstest.MapFile{Data: []byte("[a")}
+	backend.events <- ".gitignore"
+	assert.Error(t, reloaded(t, calls))
+	assert.Same(t, current, w.NoGo())
+
+	require.NoError(t, w.Close())
+	assert.True(t, backend.closed)
+}
+
+func TestNewWatcher_Error(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte("[a")},
+	}
+	backend := newFakeWatchBackend()
+
+	_, err := NewWatcher(fsys, ".gitignore", backend)
+	assert.Error(t, err)
+	assert.True(t, backend.closed)
+}
+
+func TestWatcher_Close(t *testing.T) {
+	backend := newFakeWatchBackend()
+	w, err := NewWatcher(fstest.MapFS{}, ".gitignore", backend)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Close())
+	assert.NoError(t, w.Close(), "closing twice must not panic")
+	assert.True(t, backend.closed)
+}
+
+func TestWatcher_AddError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/file": {},
+		"b/file": {},
+	}
+
+	t.Run("new watcher", func(t *testing.T) {
+		backend := newFakeWatchBackend()
+		backend.setFailAdd("b")
+		_, err := NewWatcher(fsys, ".gitignore", backend)
+		assert.Error(t, err)
+		assert.Empty(t, backend.watched(), "the added directories must be removed again")
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		backend := newFakeWatchBackend()
+		w, err := NewWatcher(fsys, ".gitignore", backend)
+		require.NoError(t, err)
+		defer w.Close()
+
+		calls := make(chan error)
+		w.Subscribe(func(n *nogo.NoGo, err error) {
+			calls <- err
+		})
+
+		fsys["c/file"] = &fstest.MapFile{}
+		fsys["d/file"] = &fstest.MapFile{}
+		backend.setFailAdd("d")
+		backend.events <- "c"
+		assert.Error(t, reloaded(t, calls))
+		assert.Equal(t, []string{".", "a", "b"}, backend.watched())
+
+		backend.setFailAdd("")
+		backend.events <- "c"
+		require.NoError(t, reloaded(t, calls))
+		assert.Equal(t, []string{".", "a", "b", "c", "d"}, backend.watched())
+	})
+}
@@ -0,0 +1,8 @@
+// Package x contains experimental features of nogo.
+// They may change or be removed in any version, so only use them if you can
+// follow these changes. Features which proved to be useful get moved to the
+// stable package github.com/aligator/nogo.
+//
+// Currently it contains the Watcher, which keeps the rules of all ignore files
+// of a file system up to date.
+package x